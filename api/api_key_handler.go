@@ -0,0 +1,63 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"pansou/model"
+	"pansou/service"
+)
+
+var (
+	apiKeyService     *service.APIKeyService
+	apiKeyServiceOnce sync.Once
+)
+
+func getAPIKeyService() *service.APIKeyService {
+	apiKeyServiceOnce.Do(func() {
+		apiKeyService = service.NewAPIKeyService()
+	})
+	return apiKeyService
+}
+
+// CreateAPIKeyHandler 创建API密钥，响应中的key只返回这一次
+func CreateAPIKeyHandler(c *gin.Context) {
+	var req model.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的请求参数: "+err.Error()))
+		return
+	}
+
+	key, err := getAPIKeyService().Create(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrAPIKeyStore) {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, model.NewErrorResponse(status, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+// ListAPIKeysHandler 列出全部API密钥，不包含密钥本身
+func ListAPIKeysHandler(c *gin.Context) {
+	keys := getAPIKeyService().List()
+	c.JSON(http.StatusOK, model.APIKeysResponse{Keys: keys, Total: len(keys)})
+}
+
+// RevokeAPIKeyHandler 吊销API密钥
+func RevokeAPIKeyHandler(c *gin.Context) {
+	key, err := getAPIKeyService().Revoke(c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.NewErrorResponse(status, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
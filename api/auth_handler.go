@@ -1,13 +1,29 @@
 package api
 
 import (
+	"errors"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"pansou/config"
+	"pansou/service"
 	"pansou/util"
 )
 
+var (
+	authTokenStore     *service.AuthTokenStore
+	authTokenStoreOnce sync.Once
+)
+
+func getAuthTokenStore() *service.AuthTokenStore {
+	authTokenStoreOnce.Do(func() {
+		authTokenStore = service.NewAuthTokenStore()
+	})
+	return authTokenStore
+}
+
 // LoginRequest 登录请求结构
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
@@ -16,9 +32,22 @@ type LoginRequest struct {
 
 // LoginResponse 登录响应结构
 type LoginResponse struct {
-	Token     string `json:"token"`
-	ExpiresAt int64  `json:"expires_at"`
-	Username  string `json:"username"`
+	Token            string `json:"token"`              // 短期访问令牌
+	ExpiresAt        int64  `json:"expires_at"`         // 访问令牌过期时间（秒级时间戳）
+	RefreshToken     string `json:"refresh_token"`      // 刷新令牌，用于换取新的访问令牌
+	RefreshExpiresAt int64  `json:"refresh_expires_at"` // 登录会话过期时间（秒级时间戳）
+	Username         string `json:"username"`
+	Role             string `json:"role"`
+}
+
+// RefreshRequest 刷新令牌请求结构
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest 退出登录请求结构，refresh_token可选
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // LoginHandler 处理用户登录
@@ -30,48 +59,104 @@ func LoginHandler(c *gin.Context) {
 	}
 
 	// 验证认证系统是否启用
-	if !config.AppConfig.AuthEnabled {
+	if !config.Get().AuthEnabled {
 		c.JSON(403, gin.H{"error": "认证功能未启用"})
 		return
 	}
 
 	// 验证用户配置是否存在
-	if config.AppConfig.AuthUsers == nil || len(config.AppConfig.AuthUsers) == 0 {
+	if config.Get().AuthUsers == nil || len(config.Get().AuthUsers) == 0 {
 		c.JSON(500, gin.H{"error": "认证系统未正确配置"})
 		return
 	}
 
-	// 验证用户名和密码
-	storedPassword, exists := config.AppConfig.AuthUsers[req.Username]
-	if !exists || storedPassword != req.Password {
+	// 验证用户名和密码，密码可以是bcrypt或argon2id哈希
+	storedPassword, exists := config.Get().AuthUsers[req.Username]
+	if !exists || !util.VerifyPassword(storedPassword, req.Password) {
 		c.JSON(401, gin.H{"error": "用户名或密码错误"})
 		return
 	}
 
-	// 生成JWT token
-	token, err := util.GenerateToken(
-		req.Username,
-		config.AppConfig.AuthJWTSecret,
-		config.AppConfig.AuthTokenExpiry,
-	)
+	// 签发刷新令牌，登录会话的有效期为AUTH_TOKEN_EXPIRY
+	sessionExpiresAt := time.Now().Add(config.Get().AuthTokenExpiry)
+	refreshToken, err := getAuthTokenStore().IssueRefreshToken(req.Username, sessionExpiresAt)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "生成令牌失败"})
+		return
+	}
+
+	respondTokens(c, req.Username, refreshToken, sessionExpiresAt)
+}
+
+// RefreshHandler 使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌随即失效
+func RefreshHandler(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "参数错误：refresh_token不能为空"})
+		return
+	}
+
+	if !config.Get().AuthEnabled {
+		c.JSON(403, gin.H{"error": "认证功能未启用"})
+		return
+	}
+
+	username, sessionExpiresAt, refreshToken, err := getAuthTokenStore().RotateRefreshToken(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrRefreshTokenInvalid) {
+			c.JSON(401, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(500, gin.H{"error": "刷新令牌失败"})
+		}
+		return
+	}
+
+	// 已从AUTH_USERS中删除的用户不能继续刷新
+	if _, exists := config.Get().AuthUsers[username]; !exists {
+		getAuthTokenStore().RevokeRefreshToken(refreshToken)
+		c.JSON(401, gin.H{"error": service.ErrRefreshTokenInvalid.Error()})
+		return
+	}
+
+	respondTokens(c, username, refreshToken, sessionExpiresAt)
+}
+
+// respondTokens 签发访问令牌并返回令牌对，访问令牌不会晚于登录会话过期
+func respondTokens(c *gin.Context, username, refreshToken string, sessionExpiresAt time.Time) {
+	expiry := config.Get().AuthAccessTokenExpiry
+	if remaining := time.Until(sessionExpiresAt); remaining < expiry {
+		expiry = remaining
+	}
+
+	role := userRole(username)
+	token, err := util.GenerateToken(username, role, config.Get().AuthJWTSecret, expiry)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "生成令牌失败"})
 		return
 	}
 
-	// 返回token和过期时间
-	expiresAt := time.Now().Add(config.AppConfig.AuthTokenExpiry).Unix()
 	c.JSON(200, LoginResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		Username:  req.Username,
+		Token:            token,
+		ExpiresAt:        time.Now().Add(expiry).Unix(),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: sessionExpiresAt.Unix(),
+		Username:         username,
+		Role:             role,
 	})
 }
 
+// userRole 返回用户的角色，未在AUTH_USER_ROLES中配置的用户使用AUTH_DEFAULT_ROLE
+func userRole(username string) string {
+	if role, ok := config.Get().AuthUserRoles[username]; ok {
+		return role
+	}
+	return config.Get().AuthDefaultRole
+}
+
 // VerifyHandler 验证token有效性
 func VerifyHandler(c *gin.Context) {
 	// 如果未启用认证，直接返回有效
-	if !config.AppConfig.AuthEnabled {
+	if !config.Get().AuthEnabled {
 		c.JSON(200, gin.H{
 			"valid": true,
 			"message": "认证功能未启用",
@@ -89,12 +174,29 @@ func VerifyHandler(c *gin.Context) {
 	c.JSON(200, gin.H{
 		"valid":    true,
 		"username": username,
+		"role":     c.GetString("role"),
 	})
 }
 
-// LogoutHandler 退出登录（客户端删除token即可）
+// LogoutHandler 退出登录
+// 吊销Authorization头中的访问令牌（按jti）和请求体中的刷新令牌，吊销后即使未过期也不能再使用
 func LogoutHandler(c *gin.Context) {
-	// JWT是无状态的，服务端不需要处理注销
-	// 客户端删除存储的token即可
+	if config.Get().AuthEnabled {
+		store := getAuthTokenStore()
+
+		const bearerPrefix = "Bearer "
+		if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, bearerPrefix) {
+			claims, err := util.ValidateToken(strings.TrimPrefix(authHeader, bearerPrefix), config.Get().AuthJWTSecret)
+			if err == nil && claims.ExpiresAt != nil {
+				store.RevokeToken(claims.ID, claims.ExpiresAt.Time)
+			}
+		}
+
+		var req LogoutRequest
+		if err := c.ShouldBindJSON(&req); err == nil {
+			store.RevokeRefreshToken(req.RefreshToken)
+		}
+	}
+
 	c.JSON(200, gin.H{"message": "退出成功"})
 }
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/gin-gonic/gin"
+	"pansou/checker"
 	"pansou/model"
 	"pansou/service"
 )
@@ -33,6 +34,25 @@ func CheckHandler(c *gin.Context) {
 		return
 	}
 
-	response := getCheckService().Check(req.Items)
+	response := getCheckService().Check(c.Request.Context(), req.Items)
 	c.JSON(http.StatusOK, response)
 }
+
+// CheckProvidersHandler 列出支持链接检测的网盘类型
+func CheckProvidersHandler(c *gin.Context) {
+	checkers := checker.GetRegisteredCheckers()
+	providers := make([]model.CheckProvider, 0, len(checkers))
+	for _, linkChecker := range checkers {
+		providers = append(providers, model.CheckProvider{DiskType: linkChecker.DiskType()})
+	}
+
+	c.JSON(http.StatusOK, model.CheckProvidersResponse{
+		Providers: providers,
+		Total:     len(providers),
+	})
+}
+
+// CheckSweeperHandler 查看检测缓存后台复检的进度和最近失效的链接
+func CheckSweeperHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, getCheckService().SweeperStatus())
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pansou/config"
+	"pansou/model"
+)
+
+func init() {
+	// 默认过滤配置与请求中的filter参数使用相同的规则，启动和热加载时预先编译校验
+	config.AddValidator(func(cfg *config.Config) error {
+		if _, err := compileFilter(cfg.DefaultFilter, time.Now()); err != nil {
+			return fmt.Errorf("DEFAULT_FILTER无效: %w", err)
+		}
+		return nil
+	})
+}
+
+// ConfigHandler 返回当前生效的配置（环境变量和配置文件合并后的结果），敏感信息已脱敏
+func ConfigHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, model.ConfigResponse{
+		ConfigFile: config.ConfigFile(),
+		LoadedAt:   config.LoadedAt().UnixMilli(),
+		Reloadable: config.ReloadableFields(),
+		Config:     config.Redacted(),
+	})
+}
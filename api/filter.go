@@ -1,32 +1,198 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"pansou/model"
+	"pansou/service"
+	"pansou/util/normalize"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// applyResultFilter 应用过滤器到搜索响应
-func applyResultFilter(response model.SearchResponse, filter *model.FilterConfig, resultType string) model.SearchResponse {
-	if filter == nil || (len(filter.Include) == 0 && len(filter.Exclude) == 0) {
-		return response
+// 单个请求最多允许的过滤规则数
+const maxFilterRules = 20
+
+// 过滤规则字段的位标记，用于判断规则是否适用于当前匹配对象
+const (
+	filterFieldTitle = 1 << iota
+	filterFieldContent
+	filterFieldTags
+	filterFieldSource
+	filterFieldPassword
+	filterFieldDatetime
+)
+
+var filterFields = map[string]int{
+	model.FilterFieldTitle:    filterFieldTitle,
+	model.FilterFieldContent:  filterFieldContent,
+	model.FilterFieldTags:     filterFieldTags,
+	model.FilterFieldSource:   filterFieldSource,
+	model.FilterFieldPassword: filterFieldPassword,
+	model.FilterFieldDatetime: filterFieldDatetime,
+}
+
+// 各层级可以匹配的字段
+// merged_by_type中的链接没有content和tags，content匹配note，tags规则不适用
+const (
+	resultFields     = filterFieldTitle | filterFieldContent | filterFieldTags | filterFieldSource | filterFieldDatetime
+	resultLinkFields = filterFieldTitle | filterFieldPassword | filterFieldDatetime
+	mergedLinkFields = filterFieldTitle | filterFieldContent | filterFieldSource | filterFieldPassword | filterFieldDatetime
+)
+
+// resultFilter 预编译的过滤器，每个请求编译一次
+type resultFilter struct {
+	include  []string
+	exclude  []string
+	rules    []filterRule
+	minLinks int
+}
+
+// filterRule 预编译的过滤规则
+type filterRule struct {
+	field   int
+	pattern *regexp.Regexp
+	after   time.Time
+	before  time.Time
+	exclude bool
+}
+
+// filterSubject 规则匹配的对象，fields标记该层级可以匹配的字段
+type filterSubject struct {
+	fields   int
+	title    string
+	content  string
+	tags     []string
+	source   string
+	password string
+	datetime time.Time
+}
+
+// compileFilter 校验并预编译过滤配置，相对时间以now为基准
+// filter为空或没有任何条件时返回nil
+func compileFilter(filter *model.FilterConfig, now time.Time) (*resultFilter, error) {
+	if filter == nil || (len(filter.Include) == 0 && len(filter.Exclude) == 0 && len(filter.Rules) == 0 && filter.MinLinks <= 0) {
+		return nil, nil
+	}
+	if len(filter.Rules) > maxFilterRules {
+		return nil, fmt.Errorf("filter.rules最多允许%d条规则", maxFilterRules)
+	}
+
+	// 预处理关键词（归一化），只含空白或标点的关键词归一化后为空，
+	// 空串会被任何文本包含，保留的话exclude会排除全部结果，因此直接忽略
+	compiled := &resultFilter{
+		include:  normalizeKeywords(filter.Include),
+		exclude:  normalizeKeywords(filter.Exclude),
+		rules:    make([]filterRule, 0, len(filter.Rules)),
+		minLinks: filter.MinLinks,
+	}
+
+	for i, rule := range filter.Rules {
+		r, err := compileFilterRule(rule, now)
+		if err != nil {
+			return nil, fmt.Errorf("filter.rules[%d]: %w", i, err)
+		}
+		compiled.rules = append(compiled.rules, r)
+	}
+	if len(compiled.include) == 0 && len(compiled.exclude) == 0 && len(compiled.rules) == 0 && compiled.minLinks <= 0 {
+		return nil, nil
+	}
+	return compiled, nil
+}
+
+// normalizeKeywords 归一化过滤关键词，去掉归一化后为空的关键词
+func normalizeKeywords(keywords []string) []string {
+	normalized := make([]string, 0, len(keywords))
+	for _, kw := range keywords {
+		if kw = normalize.Text(kw); kw != "" {
+			normalized = append(normalized, kw)
+		}
+	}
+	return normalized
+}
+
+// compileFilterRule 预编译单条过滤规则
+func compileFilterRule(rule model.FilterRule, now time.Time) (filterRule, error) {
+	name := strings.ToLower(strings.TrimSpace(rule.Field))
+	if name == "" {
+		name = model.FilterFieldTitle
+	}
+	field, ok := filterFields[name]
+	if !ok {
+		return filterRule{}, fmt.Errorf("不支持的字段: %s", rule.Field)
+	}
+	r := filterRule{field: field, exclude: rule.Exclude}
+
+	if field == filterFieldDatetime {
+		if rule.Pattern != "" {
+			return filterRule{}, errors.New("datetime字段使用after和before，不支持pattern")
+		}
+		if rule.After == "" && rule.Before == "" {
+			return filterRule{}, errors.New("datetime字段需要指定after或before")
+		}
+		var err error
+		if r.after, err = parseFilterTime(rule.After, now); err != nil {
+			return filterRule{}, fmt.Errorf("无效的after: %w", err)
+		}
+		if r.before, err = parseFilterTime(rule.Before, now); err != nil {
+			return filterRule{}, fmt.Errorf("无效的before: %w", err)
+		}
+		return r, nil
 	}
 
-	// 预处理关键词（转小写）
-	includeKeywords := make([]string, len(filter.Include))
-	for i, kw := range filter.Include {
-		includeKeywords[i] = strings.ToLower(kw)
+	if rule.After != "" || rule.Before != "" {
+		return filterRule{}, errors.New("after和before只能用于datetime字段")
 	}
-	
-	excludeKeywords := make([]string, len(filter.Exclude))
-	for i, kw := range filter.Exclude {
-		excludeKeywords[i] = strings.ToLower(kw)
+	if rule.Pattern == "" {
+		// 只有password字段允许省略pattern，表示存在提取码
+		if field != filterFieldPassword {
+			return filterRule{}, errors.New("pattern不能为空")
+		}
+		return r, nil
+	}
+	pattern, err := regexp.Compile("(?i)" + rule.Pattern)
+	if err != nil {
+		return filterRule{}, fmt.Errorf("无效的正则表达式: %w", err)
+	}
+	r.pattern = pattern
+	return r, nil
+}
+
+// parseFilterTime 解析时间，支持YYYY-MM-DD、RFC3339和相对时长（30d表示30天前，12h表示12小时前）
+func parseFilterTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("无法解析时间: %s", value)
+}
+
+// applyResultFilter 应用过滤器到搜索响应
+func applyResultFilter(response model.SearchResponse, filter *resultFilter, resultType string) model.SearchResponse {
+	if filter == nil {
+		return response
 	}
 
 	// 根据结果类型决定过滤策略
 	if resultType == "merged_by_type" || resultType == "" {
-		// 过滤 merged_by_type 的 note 字段
-		response.MergedByType = filterMergedByType(response.MergedByType, includeKeywords, excludeKeywords)
-		
+		// 过滤 merged_by_type 的链接
+		response.MergedByType = filterMergedByType(response.MergedByType, filter)
+
 		// 重新计算 total
 		total := 0
 		for _, links := range response.MergedByType {
@@ -34,13 +200,13 @@ func applyResultFilter(response model.SearchResponse, filter *model.FilterConfig
 		}
 		response.Total = total
 	} else if resultType == "all" || resultType == "results" {
-		// 过滤 results 的 title 和 links 的 work_title
-		response.Results = filterResults(response.Results, includeKeywords, excludeKeywords)
+		// 过滤 results 和其中的 links
+		response.Results = filterResults(response.Results, filter)
 		response.Total = len(response.Results)
-		
+
 		// 如果是 all 类型，也需要过滤 merged_by_type
 		if resultType == "all" {
-			response.MergedByType = filterMergedByType(response.MergedByType, includeKeywords, excludeKeywords)
+			response.MergedByType = filterMergedByType(response.MergedByType, filter)
 		}
 	}
 
@@ -48,46 +214,73 @@ func applyResultFilter(response model.SearchResponse, filter *model.FilterConfig
 }
 
 // filterMergedByType 过滤 merged_by_type 中的链接
-func filterMergedByType(mergedLinks model.MergedLinks, includeKeywords, excludeKeywords []string) model.MergedLinks {
+func filterMergedByType(mergedLinks model.MergedLinks, filter *resultFilter) model.MergedLinks {
 	if mergedLinks == nil {
 		return nil
 	}
 
 	filtered := make(model.MergedLinks)
-	
+
 	for linkType, links := range mergedLinks {
 		filteredLinks := make([]model.MergedLink, 0)
-		
+
 		for _, link := range links {
-			if matchFilter(link.Note, includeKeywords, excludeKeywords) {
+			if !matchFilter(link.Note, filter.include, filter.exclude) {
+				continue
+			}
+			subject := filterSubject{
+				fields:   mergedLinkFields,
+				title:    link.Note,
+				content:  link.Note,
+				source:   link.Source,
+				password: link.Password,
+				datetime: link.Datetime,
+			}
+			if filter.matchRules(subject) {
 				filteredLinks = append(filteredLinks, link)
 			}
 		}
-		
+
 		// 只添加非空的类型
 		if len(filteredLinks) > 0 {
 			filtered[linkType] = filteredLinks
 		}
 	}
-	
+
 	return filtered
 }
 
 // filterResults 过滤 results 数组
-func filterResults(results []model.SearchResult, includeKeywords, excludeKeywords []string) []model.SearchResult {
+func filterResults(results []model.SearchResult, filter *resultFilter) []model.SearchResult {
 	if results == nil {
 		return nil
 	}
 
+	// 过滤后的链接数不足时丢弃该结果，默认至少保留1个链接
+	minLinks := filter.minLinks
+	if minLinks < 1 {
+		minLinks = 1
+	}
+
 	filtered := make([]model.SearchResult, 0)
-	
+
 	for _, result := range results {
-		// 先检查 title 是否匹配
-		if !matchFilter(result.Title, includeKeywords, excludeKeywords) {
+		// 先检查 title 和结果级别的规则是否匹配
+		if !matchFilter(result.Title, filter.include, filter.exclude) {
+			continue
+		}
+		if !filter.matchRules(filterSubject{
+			fields:   resultFields,
+			title:    result.Title,
+			content:  result.Content,
+			tags:     result.Tags,
+			source:   service.ResultSource(result),
+			datetime: result.Datetime,
+		}) {
 			continue
 		}
-		
-		// title 匹配后，过滤 links 中的 work_title
+
+		// 结果匹配后，按链接级别的规则过滤 links
 		filteredLinks := make([]model.Link, 0)
 		for _, link := range result.Links {
 			// 如果 link 有 work_title，检查它；否则使用 result.Title
@@ -95,38 +288,103 @@ func filterResults(results []model.SearchResult, includeKeywords, excludeKeyword
 			if checkText == "" {
 				checkText = result.Title
 			}
-			
-			if matchFilter(checkText, includeKeywords, excludeKeywords) {
+			datetime := link.Datetime
+			if datetime.IsZero() {
+				datetime = result.Datetime
+			}
+
+			if !matchFilter(checkText, filter.include, filter.exclude) {
+				continue
+			}
+			if filter.matchRules(filterSubject{
+				fields:   resultLinkFields,
+				title:    checkText,
+				password: link.Password,
+				datetime: datetime,
+			}) {
 				filteredLinks = append(filteredLinks, link)
 			}
 		}
-		
-		// 只有有链接的结果才添加
-		if len(filteredLinks) > 0 {
+
+		// 只有链接数满足要求的结果才添加
+		if len(filteredLinks) >= minLinks {
 			result.Links = filteredLinks
 			filtered = append(filtered, result)
 		}
 	}
-	
+
 	return filtered
 }
 
+// matchRules 检查对象是否满足全部适用的规则：普通规则必须匹配，exclude规则不能匹配
+func (f *resultFilter) matchRules(subject filterSubject) bool {
+	for _, rule := range f.rules {
+		if subject.fields&rule.field == 0 {
+			continue
+		}
+		if rule.match(subject) == rule.exclude {
+			return false
+		}
+	}
+	return true
+}
+
+// match 检查单条规则是否匹配对象
+func (r filterRule) match(subject filterSubject) bool {
+	switch r.field {
+	case filterFieldTitle:
+		return r.pattern.MatchString(subject.title)
+	case filterFieldContent:
+		return r.pattern.MatchString(subject.content)
+	case filterFieldTags:
+		for _, tag := range subject.tags {
+			if r.pattern.MatchString(tag) {
+				return true
+			}
+		}
+		return false
+	case filterFieldSource:
+		return r.pattern.MatchString(subject.source)
+	case filterFieldPassword:
+		if subject.password == "" {
+			return false
+		}
+		return r.pattern == nil || r.pattern.MatchString(subject.password)
+	case filterFieldDatetime:
+		// 没有发布时间的对象不满足时间范围
+		if subject.datetime.IsZero() {
+			return false
+		}
+		if !r.after.IsZero() && subject.datetime.Before(r.after) {
+			return false
+		}
+		if !r.before.IsZero() && subject.datetime.After(r.before) {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
 // matchFilter 检查文本是否匹配过滤条件
 func matchFilter(text string, includeKeywords, excludeKeywords []string) bool {
-	lowerText := strings.ToLower(text)
-	
+	if len(includeKeywords) == 0 && len(excludeKeywords) == 0 {
+		return true
+	}
+	normalizedText := normalize.Text(text)
+
 	// 检查 exclude（任一匹配则排除）
 	for _, kw := range excludeKeywords {
-		if strings.Contains(lowerText, kw) {
+		if strings.Contains(normalizedText, kw) {
 			return false
 		}
 	}
-	
+
 	// 检查 include（如果有 include 列表，必须至少匹配一个）
 	if len(includeKeywords) > 0 {
 		matched := false
 		for _, kw := range includeKeywords {
-			if strings.Contains(lowerText, kw) {
+			if strings.Contains(normalizedText, kw) {
 				matched = true
 				break
 			}
@@ -135,6 +393,6 @@ func matchFilter(text string, includeKeywords, excludeKeywords []string) bool {
 			return false
 		}
 	}
-	
+
 	return true
 }
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pansou/config"
+	"pansou/model"
+)
+
+func TestCompileFilterKeywords(t *testing.T) {
+	now := time.Now()
+
+	f, err := compileFilter(&model.FilterConfig{
+		Include: []string{"進擊的巨人", " ", "第八季"},
+		Exclude: []string{"ＣＡＭ", "！", ""},
+	}, now)
+	if err != nil {
+		t.Fatalf("compileFilter() error = %v", err)
+	}
+	if want := []string{"进击的巨人", "第8季"}; !reflect.DeepEqual(f.include, want) {
+		t.Errorf("include = %q, want %q", f.include, want)
+	}
+	if want := []string{"cam"}; !reflect.DeepEqual(f.exclude, want) {
+		t.Errorf("exclude = %q, want %q", f.exclude, want)
+	}
+
+	// 只有空白或标点的关键词等同于没有过滤条件
+	f, err = compileFilter(&model.FilterConfig{Include: []string{"  "}, Exclude: []string{"-"}}, now)
+	if err != nil || f != nil {
+		t.Errorf("compileFilter() = %v, %v, want nil, nil", f, err)
+	}
+}
+
+func TestFilterIgnoresEmptyExclude(t *testing.T) {
+	f, err := compileFilter(&model.FilterConfig{Exclude: []string{" ", "枪版"}, MinLinks: 1}, time.Now())
+	if err != nil {
+		t.Fatalf("compileFilter() error = %v", err)
+	}
+
+	results := []model.SearchResult{
+		{Title: "进击的巨人 第八季", Links: []model.Link{{Type: "quark", URL: "https://pan.quark.cn/s/a"}}},
+		{Title: "进击的巨人 枪版", Links: []model.Link{{Type: "quark", URL: "https://pan.quark.cn/s/b"}}},
+	}
+	got := filterResults(results, f)
+	if len(got) != 1 || got[0].Title != "进击的巨人 第八季" {
+		t.Errorf("filterResults() = %+v, want only the first result", got)
+	}
+
+	merged := model.MergedLinks{"quark": {{URL: "https://pan.quark.cn/s/a", Note: "進擊的巨人"}}}
+	if got := filterMergedByType(merged, f); len(got["quark"]) != 1 {
+		t.Errorf("filterMergedByType() = %+v, want the link kept", got)
+	}
+}
+
+func TestCompileFilterRuleErrors(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		rule model.FilterRule
+	}{
+		{"unknown field", model.FilterRule{Field: "size", Pattern: "x"}},
+		{"invalid regex", model.FilterRule{Pattern: "(4k"}},
+		{"empty pattern", model.FilterRule{Field: "content"}},
+		{"datetime with pattern", model.FilterRule{Field: "datetime", Pattern: "2024"}},
+		{"datetime without bounds", model.FilterRule{Field: "datetime"}},
+		{"invalid after", model.FilterRule{Field: "datetime", After: "yesterday"}},
+		{"invalid before", model.FilterRule{Field: "datetime", Before: "-3d"}},
+		{"after on title", model.FilterRule{Pattern: "x", After: "30d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compileFilterRule(tt.rule, now); err == nil {
+				t.Errorf("compileFilterRule(%+v) error = nil, want error", tt.rule)
+			}
+		})
+	}
+
+	// 错误信息包含规则下标，规则数有上限
+	_, err := compileFilter(&model.FilterConfig{Rules: []model.FilterRule{{Pattern: "4k"}, {Pattern: "[a"}}}, now)
+	if err == nil || !strings.Contains(err.Error(), "filter.rules[1]") {
+		t.Errorf("compileFilter() error = %v, want filter.rules[1]", err)
+	}
+	if _, err := compileFilter(&model.FilterConfig{Rules: make([]model.FilterRule, maxFilterRules+1)}, now); err == nil {
+		t.Error("超过规则数上限时应返回错误")
+	}
+}
+
+func TestParseFilterTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+		{"30d", now.AddDate(0, 0, -30)},
+		{"0d", now},
+		{"12h", now.Add(-12 * time.Hour)},
+		{" 90m ", now.Add(-90 * time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseFilterTime(tt.value, now)
+			if err != nil || !got.Equal(tt.want) {
+				t.Errorf("parseFilterTime(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"abc", "-1d", "-2h", "2024/01/02", "d"} {
+		if _, err := parseFilterTime(value, now); err == nil {
+			t.Errorf("parseFilterTime(%q) error = nil, want error", value)
+		}
+	}
+}
+
+func TestMatchRules(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	subject := filterSubject{
+		fields:   resultFields | filterFieldPassword,
+		title:    "庆余年 第二季 4K HDR",
+		content:  "更新至第10集",
+		tags:     []string{"剧集", "国产"},
+		source:   "plugin:labi",
+		password: "ab12",
+		datetime: now.AddDate(0, 0, -10),
+	}
+
+	tests := []struct {
+		name  string
+		rules []model.FilterRule
+		want  bool
+	}{
+		{"no rules", nil, true},
+		{"title regex ignores case", []model.FilterRule{{Pattern: `4k\s+hdr`}}, true},
+		{"title regex no match", []model.FilterRule{{Pattern: `1080p`}}, false},
+		{"title exclude", []model.FilterRule{{Pattern: `4K`, Exclude: true}}, false},
+		{"content", []model.FilterRule{{Field: "content", Pattern: `第\d+集`}}, true},
+		{"content is not title", []model.FilterRule{{Field: "content", Pattern: `庆余年`}}, false},
+		{"any tag", []model.FilterRule{{Field: "tags", Pattern: `^国产$`}}, true},
+		{"no tag", []model.FilterRule{{Field: "tags", Pattern: `^美剧$`}}, false},
+		{"source", []model.FilterRule{{Field: "source", Pattern: `^plugin:`}}, true},
+		{"source exclude", []model.FilterRule{{Field: "source", Pattern: `labi`, Exclude: true}}, false},
+		{"has password", []model.FilterRule{{Field: "password"}}, true},
+		{"password pattern", []model.FilterRule{{Field: "password", Pattern: `^\d+$`}}, false},
+		{"after date", []model.FilterRule{{Field: "datetime", After: "2024-06-01"}}, true},
+		{"before date", []model.FilterRule{{Field: "datetime", Before: "2024-06-01"}}, false},
+		{"relative after", []model.FilterRule{{Field: "datetime", After: "30d"}}, true},
+		{"relative after too recent", []model.FilterRule{{Field: "datetime", After: "7d"}}, false},
+		{"between", []model.FilterRule{{Field: "datetime", After: "30d", Before: "168h"}}, true},
+		{"excluded range", []model.FilterRule{{Field: "datetime", After: "30d", Exclude: true}}, false},
+		// 全部规则满足才保留
+		{"all rules", []model.FilterRule{{Pattern: `庆余年`}, {Field: "source", Pattern: `tg:`}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := compileFilter(&model.FilterConfig{Rules: tt.rules, MinLinks: 1}, now)
+			if err != nil {
+				t.Fatalf("compileFilter() error = %v", err)
+			}
+			if got := f.matchRules(subject); got != tt.want {
+				t.Errorf("matchRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 规则只作用于该层级存在的字段，没有的字段跳过
+func TestMatchRulesSkipsMissingFields(t *testing.T) {
+	f, err := compileFilter(&model.FilterConfig{Rules: []model.FilterRule{
+		{Field: "tags", Pattern: `国产`},
+		{Field: "password"},
+	}}, time.Now())
+	if err != nil {
+		t.Fatalf("compileFilter() error = %v", err)
+	}
+	// merged_by_type的链接没有tags，结果层级没有password
+	if !f.matchRules(filterSubject{fields: mergedLinkFields, password: "x"}) {
+		t.Error("tags规则不应作用于合并链接")
+	}
+	if !f.matchRules(filterSubject{fields: resultFields, tags: []string{"国产"}}) {
+		t.Error("password规则不应作用于结果")
+	}
+
+	// 没有发布时间的对象不满足时间范围
+	f, _ = compileFilter(&model.FilterConfig{Rules: []model.FilterRule{{Field: "datetime", After: "30d"}}}, time.Now())
+	if f.matchRules(filterSubject{fields: resultFields}) {
+		t.Error("没有发布时间时不应满足datetime规则")
+	}
+}
+
+func TestFilterResultsRulesAndMinLinks(t *testing.T) {
+	now := time.Now()
+	f, err := compileFilter(&model.FilterConfig{
+		Rules:    []model.FilterRule{{Field: "password"}, {Field: "datetime", After: "30d"}},
+		MinLinks: 2,
+	}, now)
+	if err != nil {
+		t.Fatalf("compileFilter() error = %v", err)
+	}
+
+	recent, old := now.AddDate(0, 0, -1), now.AddDate(0, 0, -60)
+	results := []model.SearchResult{
+		{Title: "two links", Datetime: recent, Links: []model.Link{
+			{URL: "a", Password: "1111"},
+			{URL: "b", Password: "2222"},
+			{URL: "c"},
+		}},
+		// 链接的发布时间优先于结果
+		{Title: "one old link", Datetime: recent, Links: []model.Link{
+			{URL: "d", Password: "1111"},
+			{URL: "e", Password: "2222", Datetime: old},
+		}},
+		{Title: "old result", Datetime: old, Links: []model.Link{
+			{URL: "f", Password: "1111"},
+			{URL: "g", Password: "2222"},
+		}},
+	}
+	got := filterResults(results, f)
+	if len(got) != 1 || got[0].Title != "two links" || len(got[0].Links) != 2 {
+		t.Errorf("filterResults() = %+v, want only two links with 2 links", got)
+	}
+}
+
+func TestSearchRejectsInvalidFilter(t *testing.T) {
+	setTestConfig(t, &config.Config{})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/search", SearchHandler)
+
+	for _, filter := range []string{
+		`{"rules":[{"pattern":"(4k"}]}`,
+		`{"rules":[{"field":"size","pattern":"x"}]}`,
+		`{"rules":[{"field":"datetime","after":"yesterday"}]}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?kw=abc&filter="+url.QueryEscape(filter), nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "filter") {
+			t.Errorf("filter %s: status = %d, body = %s, want 400", filter, w.Code, w.Body.String())
+		}
+	}
+}
@@ -2,16 +2,20 @@ package api
 
 import (
 	// "fmt"
+	"errors"
 	"net/http"
 	// "os"
 	
 	"github.com/gin-gonic/gin"
 	"pansou/config"
 	"pansou/model"
+	"pansou/plugin"
 	"pansou/service"
 	jsonutil "pansou/util/json"
 	"pansou/util"
+	"pansou/util/query"
 	"strings"
+	"time"
 )
 
 // 保存搜索服务的实例
@@ -30,147 +34,266 @@ func SearchHandler(c *gin.Context) {
 	// 根据请求方法不同处理参数
 	if c.Request.Method == http.MethodGet {
 		// GET方式：从URL参数获取
-		// 获取keyword，必填参数
-		keyword := c.Query("kw")
-		
-		// 处理channels参数，支持逗号分隔
-		channelsStr := c.Query("channels")
-		var channels []string
-		// 只有当参数非空时才处理
-		if channelsStr != "" && channelsStr != " " {
-			parts := strings.Split(channelsStr, ",")
-			for _, part := range parts {
-				trimmed := strings.TrimSpace(part)
-				if trimmed != "" {
-					channels = append(channels, trimmed)
-				}
-			}
+		req, err = parseSearchQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+			return
 		}
-		
-		// 处理并发数
-		concurrency := 0
-		concStr := c.Query("conc")
-		if concStr != "" && concStr != " " {
-			concurrency = util.StringToInt(concStr)
+	} else {
+		// POST方式：从请求体获取
+		data, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "读取请求数据失败: "+err.Error()))
+			return
 		}
-		
-		// 处理强制刷新
-		forceRefresh := false
-		refreshStr := c.Query("refresh")
-		if refreshStr != "" && refreshStr != " " && refreshStr == "true" {
-			forceRefresh = true
+
+		if err := jsonutil.Unmarshal(data, &req); err != nil {
+			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的请求参数: "+err.Error()))
+			return
 		}
-		
-		// 处理结果类型和来源类型
-		resultType := c.Query("res")
-		if resultType == "" || resultType == " " {
-			resultType = "merge" // 直接设置为默认值merge
+	}
+	
+	normalizeSearchRequest(&req)
+
+	// kw只有排除条件或字段过滤时，不向数据源发送空搜索
+	if err := query.Parse(req.Keyword).Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	// 预编译过滤规则，规则无效时直接返回错误
+	filter, err := compileFilter(req.Filter, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的filter参数: "+err.Error()))
+		return
+	}
+
+	// 可选：启用调试输出（生产环境建议注释掉）
+	// fmt.Printf("🔧 [调试] 搜索参数: keyword=%s, channels=%v, concurrency=%d, refresh=%v, resultType=%s, sourceType=%s, plugins=%v, cloudTypes=%v, ext=%v\n", 
+	//	req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext)
+	
+	// 指定了page、page_size或cursor时返回分页结果
+	if req.IsPaged() {
+		searchPageHandler(c, req, filter)
+		return
+	}
+
+	// 执行搜索
+	result, err := searchService.Search(c.Request.Context(), req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext, req.CheckLinks(), req.Debug)
+	
+	if err != nil {
+		response := model.NewErrorResponse(500, "搜索失败: "+err.Error())
+		jsonData, _ := jsonutil.Marshal(response)
+		c.Data(http.StatusInternalServerError, "application/json", jsonData)
+		return
+	}
+
+	// 应用过滤器
+	if filter != nil {
+		result = applyResultFilter(result, filter, req.ResultType)
+		// 诊断信息中的链接数按过滤后的合并链接重新统计
+		if len(result.Diagnostics) > 0 && result.MergedByType != nil {
+			service.CountDiagnosticLinks(result.Diagnostics, result.MergedByType)
 		}
-		
-		sourceType := c.Query("src")
-		if sourceType == "" || sourceType == " " {
-			sourceType = "all" // 直接设置为默认值all
+	}
+
+	// 包装SearchResponse到标准响应格式中
+	response := model.NewSuccessResponse(result)
+	jsonData, _ := jsonutil.Marshal(response)
+	c.Data(http.StatusOK, "application/json", jsonData)
+}
+
+// searchPageHandler 分页搜索，后续页从服务端缓存的结果快照读取
+func searchPageHandler(c *gin.Context, req model.SearchRequest, filter *resultFilter) {
+	// 过滤在分页前应用于完整快照，保证各页的过滤规则一致
+	var applyFilter func(model.SearchResponse) model.SearchResponse
+	if filter != nil {
+		applyFilter = func(response model.SearchResponse) model.SearchResponse {
+			return applyResultFilter(response, filter, "all")
 		}
-		
-		// 处理plugins参数，支持逗号分隔
-		var plugins []string
-		// 检查请求中是否存在plugins参数
-		if c.Request.URL.Query().Has("plugins") {
-			pluginsStr := c.Query("plugins")
-			// 判断参数是否非空
-			if pluginsStr != "" && pluginsStr != " " {
-				parts := strings.Split(pluginsStr, ",")
-				for _, part := range parts {
-					trimmed := strings.TrimSpace(part)
-					if trimmed != "" {
-						plugins = append(plugins, trimmed)
-					}
-				}
+	}
+
+	result, err := searchService.SearchPage(c.Request.Context(), req, applyFilter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "搜索失败: " + err.Error()
+		if errors.Is(err, service.ErrInvalidCursor) {
+			status = http.StatusBadRequest
+			message = err.Error()
+		}
+		jsonData, _ := jsonutil.Marshal(model.NewErrorResponse(status, message))
+		c.Data(status, "application/json", jsonData)
+		return
+	}
+
+	jsonData, _ := jsonutil.Marshal(model.NewSuccessResponse(result))
+	c.Data(http.StatusOK, "application/json", jsonData)
+}
+
+// parseSearchQuery 从URL参数解析搜索请求（GET方式）
+func parseSearchQuery(c *gin.Context) (model.SearchRequest, error) {
+	// 获取keyword，必填参数
+	keyword := c.Query("kw")
+	
+	// 处理channels参数，支持逗号分隔
+	channelsStr := c.Query("channels")
+	var channels []string
+	// 只有当参数非空时才处理
+	if channelsStr != "" && channelsStr != " " {
+		parts := strings.Split(channelsStr, ",")
+		for _, part := range parts {
+			trimmed := strings.TrimSpace(part)
+			if trimmed != "" {
+				channels = append(channels, trimmed)
 			}
-		} else {
-			// 如果请求中不存在plugins参数，设置为nil
-			plugins = nil
 		}
-		
-		// 处理cloud_types参数，支持逗号分隔
-		var cloudTypes []string
-		// 检查请求中是否存在cloud_types参数
-		if c.Request.URL.Query().Has("cloud_types") {
-			cloudTypesStr := c.Query("cloud_types")
-			// 判断参数是否非空
-			if cloudTypesStr != "" && cloudTypesStr != " " {
-				parts := strings.Split(cloudTypesStr, ",")
-				for _, part := range parts {
-					trimmed := strings.TrimSpace(part)
-					if trimmed != "" {
-						cloudTypes = append(cloudTypes, trimmed)
-					}
+	}
+	
+	// 处理并发数
+	concurrency := 0
+	concStr := c.Query("conc")
+	if concStr != "" && concStr != " " {
+		concurrency = util.StringToInt(concStr)
+	}
+	
+	// 处理强制刷新
+	forceRefresh := false
+	refreshStr := c.Query("refresh")
+	if refreshStr != "" && refreshStr != " " && refreshStr == "true" {
+		forceRefresh = true
+	}
+	
+	// 处理结果类型和来源类型
+	resultType := c.Query("res")
+	if resultType == "" || resultType == " " {
+		resultType = "merge" // 直接设置为默认值merge
+	}
+	
+	sourceType := c.Query("src")
+	if sourceType == "" || sourceType == " " {
+		sourceType = "all" // 直接设置为默认值all
+	}
+	
+	// 处理plugins参数，支持逗号分隔
+	var plugins []string
+	// 检查请求中是否存在plugins参数
+	if c.Request.URL.Query().Has("plugins") {
+		pluginsStr := c.Query("plugins")
+		// 判断参数是否非空
+		if pluginsStr != "" && pluginsStr != " " {
+			parts := strings.Split(pluginsStr, ",")
+			for _, part := range parts {
+				trimmed := strings.TrimSpace(part)
+				if trimmed != "" {
+					plugins = append(plugins, trimmed)
 				}
 			}
-		} else {
-			// 如果请求中不存在cloud_types参数，设置为nil
-			cloudTypes = nil
 		}
-		
-		// 处理ext参数，JSON格式
-		var ext map[string]interface{}
-		extStr := c.Query("ext")
-		if extStr != "" && extStr != " " {
-			// 处理特殊情况：ext={}
-			if extStr == "{}" {
-				ext = make(map[string]interface{})
-			} else {
-				if err := jsonutil.Unmarshal([]byte(extStr), &ext); err != nil {
-					c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的ext参数格式: "+err.Error()))
-					return
+	} else {
+		// 如果请求中不存在plugins参数，设置为nil
+		plugins = nil
+	}
+	
+	// 处理cloud_types参数，支持逗号分隔
+	var cloudTypes []string
+	// 检查请求中是否存在cloud_types参数
+	if c.Request.URL.Query().Has("cloud_types") {
+		cloudTypesStr := c.Query("cloud_types")
+		// 判断参数是否非空
+		if cloudTypesStr != "" && cloudTypesStr != " " {
+			parts := strings.Split(cloudTypesStr, ",")
+			for _, part := range parts {
+				trimmed := strings.TrimSpace(part)
+				if trimmed != "" {
+					cloudTypes = append(cloudTypes, trimmed)
 				}
 			}
 		}
-		// 确保ext不为nil
-		if ext == nil {
+	} else {
+		// 如果请求中不存在cloud_types参数，设置为nil
+		cloudTypes = nil
+	}
+	
+	// 处理ext参数，JSON格式
+	var ext map[string]interface{}
+	extStr := c.Query("ext")
+	if extStr != "" && extStr != " " {
+		// 处理特殊情况：ext={}
+		if extStr == "{}" {
 			ext = make(map[string]interface{})
-		}
-		
-		// 处理filter参数，JSON格式
-		var filter *model.FilterConfig
-		filterStr := c.Query("filter")
-		if filterStr != "" && filterStr != " " {
-			filter = &model.FilterConfig{}
-			if err := jsonutil.Unmarshal([]byte(filterStr), filter); err != nil {
-				c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的filter参数格式: "+err.Error()))
-				return
+		} else {
+			if err := jsonutil.Unmarshal([]byte(extStr), &ext); err != nil {
+				return model.SearchRequest{}, errors.New("无效的ext参数格式: " + err.Error())
 			}
 		}
-
-		req = model.SearchRequest{
-			Keyword:      keyword,
-			Channels:     channels,
-			Concurrency:  concurrency,
-			ForceRefresh: forceRefresh,
-			ResultType:   resultType,
-			SourceType:   sourceType,
-			Plugins:      plugins,
-			CloudTypes:   cloudTypes, // 添加cloud_types到请求中
-			Ext:          ext,
-			Filter:       filter,
-		}
-	} else {
-		// POST方式：从请求体获取
-		data, err := c.GetRawData()
-		if err != nil {
-			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "读取请求数据失败: "+err.Error()))
-			return
+	}
+	// 确保ext不为nil
+	if ext == nil {
+		ext = make(map[string]interface{})
+	}
+	
+	// 处理filter参数，JSON格式
+	var filter *model.FilterConfig
+	filterStr := c.Query("filter")
+	if filterStr != "" && filterStr != " " {
+		filter = &model.FilterConfig{}
+		if err := jsonutil.Unmarshal([]byte(filterStr), filter); err != nil {
+			return model.SearchRequest{}, errors.New("无效的filter参数格式: " + err.Error())
 		}
+	}
 
-		if err := jsonutil.Unmarshal(data, &req); err != nil {
-			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的请求参数: "+err.Error()))
-			return
-		}
+	// 处理链接检测参数，未指定时使用服务端默认配置
+	var check *bool
+	if checkStr := c.Query("check"); checkStr == "true" || checkStr == "false" {
+		checkLinks := checkStr == "true"
+		check = &checkLinks
 	}
-	
+
+	// 处理诊断参数
+	debug := c.Query("debug") == "true"
+
+	// 处理分页参数
+	page := 0
+	if pageStr := c.Query("page"); pageStr != "" && pageStr != " " {
+		page = util.StringToInt(pageStr)
+	}
+	pageSize := 0
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" && pageSizeStr != " " {
+		pageSize = util.StringToInt(pageSizeStr)
+	}
+
+	return model.SearchRequest{
+		Keyword:      keyword,
+		Channels:     channels,
+		Concurrency:  concurrency,
+		ForceRefresh: forceRefresh,
+		ResultType:   resultType,
+		SourceType:   sourceType,
+		Plugins:      plugins,
+		CloudTypes:   cloudTypes, // 添加cloud_types到请求中
+		Ext:          ext,
+		Filter:       filter,
+		Page:         page,
+		PageSize:     pageSize,
+		Cursor:       strings.TrimSpace(c.Query("cursor")),
+		Check:        check,
+		Debug:        debug,
+	}, nil
+}
+
+// normalizeSearchRequest 检查并设置搜索请求的默认值
+func normalizeSearchRequest(req *model.SearchRequest) {
 	// 检查并设置默认值
 	if len(req.Channels) == 0 {
-		req.Channels = config.AppConfig.DefaultChannels
+		req.Channels = config.Get().DefaultChannels
+	}
+
+	// 去掉客户端传入的保留ext键，必须在计算缓存键之前处理
+	req.Ext = plugin.StripReservedExt(req.Ext)
+
+	// 未指定过滤配置时使用服务端默认配置（DEFAULT_FILTER）
+	if req.Filter == nil {
+		req.Filter = config.Get().DefaultFilter
 	}
 	
 	// 如果未指定结果类型，默认返回merge并转换为merged_by_type
@@ -181,6 +304,12 @@ func SearchHandler(c *gin.Context) {
 		req.ResultType = "merged_by_type"
 	}
 	
+	// 未指定是否检测链接时使用服务端默认配置
+	if req.Check == nil {
+		checkLinks := config.Get().SearchCheckEnabled
+		req.Check = &checkLinks
+	}
+
 	// 如果未指定数据来源类型，默认为全部
 	if req.SourceType == "" {
 		req.SourceType = "all"
@@ -197,28 +326,4 @@ func SearchHandler(c *gin.Context) {
 			req.Plugins = nil
 		}
 	}
-	
-	// 可选：启用调试输出（生产环境建议注释掉）
-	// fmt.Printf("🔧 [调试] 搜索参数: keyword=%s, channels=%v, concurrency=%d, refresh=%v, resultType=%s, sourceType=%s, plugins=%v, cloudTypes=%v, ext=%v\n", 
-	//	req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext)
-	
-	// 执行搜索
-	result, err := searchService.Search(req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext)
-	
-	if err != nil {
-		response := model.NewErrorResponse(500, "搜索失败: "+err.Error())
-		jsonData, _ := jsonutil.Marshal(response)
-		c.Data(http.StatusInternalServerError, "application/json", jsonData)
-		return
-	}
-
-	// 应用过滤器
-	if req.Filter != nil {
-		result = applyResultFilter(result, req.Filter, req.ResultType)
-	}
-
-	// 包装SearchResponse到标准响应格式中
-	response := model.NewSuccessResponse(result)
-	jsonData, _ := jsonutil.Marshal(response)
-	c.Data(http.StatusOK, "application/json", jsonData)
-} 
\ No newline at end of file
+}
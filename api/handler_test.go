@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"pansou/config"
+	"pansou/model"
+)
+
+func TestNormalizeSearchRequestStripsReservedExt(t *testing.T) {
+	setTestConfig(t, &config.Config{})
+
+	req := model.SearchRequest{
+		Keyword: "庆余年",
+		Ext: map[string]interface{}{
+			"title_en":         "Joy of Life",
+			"__search_context": "forged",
+			"__search_call":    map[string]interface{}{"mainCacheKey": "victim"},
+		},
+	}
+	normalizeSearchRequest(&req)
+
+	if len(req.Ext) != 1 || req.Ext["title_en"] != "Joy of Life" {
+		t.Errorf("req.Ext = %v, want only title_en", req.Ext)
+	}
+}
+
+// kw只有排除条件或字段过滤时返回400，不调用搜索服务
+func TestSearchRejectsQueryWithoutSearchTerm(t *testing.T) {
+	setTestConfig(t, &config.Config{})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/search", SearchHandler)
+	router.POST("/api/search", SearchHandler)
+	router.GET("/api/search/stream", SearchStreamHandler)
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"get exclusion", httptest.NewRequest(http.MethodGet, "/api/search?kw="+url.QueryEscape("-预告"), nil)},
+		{"get fields", httptest.NewRequest(http.MethodGet, "/api/search?kw="+url.QueryEscape("type:quark after:2024"), nil)},
+		{"post", httptest.NewRequest(http.MethodPost, "/api/search", strings.NewReader(`{"kw":"NOT 预告 source:tg"}`))},
+		{"stream", httptest.NewRequest(http.MethodGet, "/api/search/stream?kw="+url.QueryEscape(`-"枪版" -预告`), nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, tt.req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400, body = %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -2,12 +2,16 @@ package api
 
 import (
 	"fmt"
+	"math"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"pansou/config"
+	"pansou/model"
+	"pansou/service"
 	"pansou/util"
 )
 
@@ -15,8 +19,8 @@ import (
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key")
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -74,33 +78,35 @@ func LoggerMiddleware() gin.HandlerFunc {
 	}
 }
 
-// AuthMiddleware JWT认证中间件
+// AuthMiddleware 认证中间件，支持JWT和API密钥
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 如果未启用认证，直接放行
-		if !config.AppConfig.AuthEnabled {
+		if !config.Get().AuthEnabled {
 			c.Next()
 			return
 		}
 
-		// 定义公开接口（不需要认证）
-		publicPaths := []string{
-			"/api/auth/login",
-			"/api/auth/logout",
-			"/api/health", // 健康检查接口可选择是否需要认证
-		}
-
-		// 检查当前路径是否是公开接口
-		path := c.Request.URL.Path
-		for _, p := range publicPaths {
-			if strings.HasPrefix(path, p) {
-				c.Next()
-				return
-			}
+		// 公开接口（AUTH_PUBLIC_PATHS）不需要认证
+		if isPublicPath(c.Request.URL.Path) {
+			c.Next()
+			return
 		}
 
 		// 获取Authorization头
 		authHeader := c.GetHeader("Authorization")
+		const bearerPrefix = "Bearer "
+
+		// API密钥：X-API-Key头，或以psk_开头的Bearer令牌
+		apiKey := c.GetHeader("X-API-Key")
+		if apiKey == "" && strings.HasPrefix(authHeader, bearerPrefix) && service.IsAPIKey(strings.TrimPrefix(authHeader, bearerPrefix)) {
+			apiKey = strings.TrimPrefix(authHeader, bearerPrefix)
+		}
+		if apiKey != "" {
+			authenticateAPIKey(c, apiKey)
+			return
+		}
+
 		if authHeader == "" {
 			c.JSON(401, gin.H{
 				"error": "未授权：缺少认证令牌",
@@ -111,7 +117,6 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// 解析Bearer token
-		const bearerPrefix = "Bearer "
 		if !strings.HasPrefix(authHeader, bearerPrefix) {
 			c.JSON(401, gin.H{
 				"error": "未授权：令牌格式错误",
@@ -124,7 +129,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		tokenString := strings.TrimPrefix(authHeader, bearerPrefix)
 
 		// 验证token
-		claims, err := util.ValidateToken(tokenString, config.AppConfig.AuthJWTSecret)
+		claims, err := util.ValidateToken(tokenString, config.Get().AuthJWTSecret)
 		if err != nil {
 			c.JSON(401, gin.H{
 				"error": "未授权：令牌无效或已过期",
@@ -134,8 +139,140 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 已注销的令牌
+		if getAuthTokenStore().IsRevoked(claims.ID) {
+			c.JSON(401, gin.H{
+				"error": "未授权：令牌已注销",
+				"code":  "AUTH_TOKEN_REVOKED",
+			})
+			c.Abort()
+			return
+		}
+
 		// 将用户信息存入上下文，供后续处理使用
+		// 升级前签发的令牌不带角色，按当前配置确定
+		role := claims.Role
+		if role == "" {
+			role = userRole(claims.Username)
+		}
 		c.Set("username", claims.Username)
+		c.Set("role", role)
+		c.Next()
+	}
+}
+
+// isPublicPath 检查路径是否匹配AUTH_PUBLIC_PATHS中的前缀
+func isPublicPath(path string) bool {
+	for _, p := range config.Get().AuthPublicPaths {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticateAPIKey 校验API密钥的有效性、权限范围和请求限额
+func authenticateAPIKey(c *gin.Context, rawKey string) {
+	keys := getAPIKeyService()
+	key, err := keys.Authenticate(rawKey)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"error": "未授权：" + err.Error(),
+			"code":  "API_KEY_INVALID",
+		})
+		c.Abort()
+		return
+	}
+
+	if scope := requiredAPIKeyScope(c.Request.URL.Path); scope != "" && !hasAPIKeyScope(key, scope) {
+		c.JSON(403, gin.H{
+			"error": "禁止访问：API密钥缺少" + scope + "权限",
+			"code":  "API_KEY_SCOPE_DENIED",
+		})
+		c.Abort()
+		return
+	}
+
+	if retryAfter, err := keys.Consume(key.ID); err != nil {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(429, gin.H{
+			"error": err.Error(),
+			"code":  "API_KEY_QUOTA_EXCEEDED",
+		})
+		c.Abort()
+		return
+	}
+
+	c.Set("username", "apikey:"+key.Name)
+	c.Set("api_key_id", key.ID)
+	c.Next()
+}
+
+// requiredAPIKeyScope 根据请求路径确定API密钥需要的权限范围，返回空表示任意有效密钥均可访问
+func requiredAPIKeyScope(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/api/auth/"):
+		return ""
+	case strings.HasPrefix(path, "/api/admin/"), path == "/metrics":
+		return model.APIKeyScopeAdmin
+	case strings.HasPrefix(path, "/api/check/"):
+		return model.APIKeyScopeCheck
+	case strings.HasPrefix(path, "/api/"):
+		return model.APIKeyScopeSearch
+	default:
+		// 其余路由由插件注册
+		return model.APIKeyScopePluginWeb
+	}
+}
+
+// hasAPIKeyScope 检查API密钥是否拥有指定权限范围
+func hasAPIKeyScope(key model.APIKey, scope string) bool {
+	for _, s := range key.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRoleMiddleware 要求用户角色不低于role的中间件
+// 未启用认证和公开路径不检查角色；API密钥由权限范围控制，也不检查角色
+func RequireRoleMiddleware(role string) gin.HandlerFunc {
+	required := model.RoleLevel(role)
+	return func(c *gin.Context) {
+		if !config.Get().AuthEnabled || isPublicPath(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		if _, isAPIKey := c.Get("api_key_id"); isAPIKey {
+			c.Next()
+			return
+		}
+
+		if model.RoleLevel(c.GetString("role")) < required {
+			c.JSON(403, gin.H{
+				"error": "禁止访问：该接口需要" + role + "及以上角色",
+				"code":  "AUTH_ROLE_DENIED",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAuthMiddleware 要求启用认证的中间件
+// 用于修改服务状态的管理接口，未启用认证时拒绝访问；启用认证时令牌由AuthMiddleware校验
+func RequireAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.Get().AuthEnabled {
+			c.JSON(403, gin.H{
+				"error": "禁止访问：该接口需要启用认证（AUTH_ENABLED=true）",
+				"code":  "AUTH_REQUIRED",
+			})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
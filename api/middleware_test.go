@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+
+	"pansou/model"
+)
+
+func TestRequiredAPIKeyScope(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/auth/login", ""},
+		{"/api/auth/refresh", ""},
+		{"/api/admin/api-keys", model.APIKeyScopeAdmin},
+		{"/api/admin/config", model.APIKeyScopeAdmin},
+		{"/metrics", model.APIKeyScopeAdmin},
+		{"/api/check/links", model.APIKeyScopeCheck},
+		{"/api/search", model.APIKeyScopeSearch},
+		{"/api/search/stream", model.APIKeyScopeSearch},
+		{"/api/health", model.APIKeyScopeSearch},
+		{"/api/plugins", model.APIKeyScopeSearch},
+		{"/gying/login", model.APIKeyScopePluginWeb},
+		{"/metrics/extra", model.APIKeyScopePluginWeb},
+		{"/", model.APIKeyScopePluginWeb},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := requiredAPIKeyScope(tt.path); got != tt.want {
+				t.Errorf("requiredAPIKeyScope(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasAPIKeyScope(t *testing.T) {
+	key := model.APIKey{Scopes: []string{model.APIKeyScopeSearch, model.APIKeyScopeCheck}}
+	if !hasAPIKeyScope(key, model.APIKeyScopeCheck) {
+		t.Error("hasAPIKeyScope(check) = false, want true")
+	}
+	if hasAPIKeyScope(key, model.APIKeyScopeAdmin) {
+		t.Error("hasAPIKeyScope(admin) = true, want false")
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"pansou/config"
+	"pansou/model"
+	"pansou/plugin"
+	"pansou/service"
+)
+
+// PluginsHandler 列出所有已注册插件的元数据和当前启用状态，供前端构建插件选择器
+func PluginsHandler(c *gin.Context) {
+	enabled := make(map[string]bool)
+	if config.Get().AsyncPluginEnabled && searchService != nil && searchService.GetPluginManager() != nil {
+		for _, p := range searchService.GetPluginManager().GetPlugins() {
+			enabled[p.Name()] = true
+		}
+	}
+
+	registered := plugin.GetRegisteredPlugins()
+	plugins := make([]model.PluginInfo, 0, len(registered))
+	for _, p := range registered {
+		plugins = append(plugins, pluginInfoOf(p, enabled[p.Name()]))
+	}
+
+	// 启用的插件在前，同状态按等级和名称排序
+	sort.Slice(plugins, func(i, j int) bool {
+		if plugins[i].Enabled != plugins[j].Enabled {
+			return plugins[i].Enabled
+		}
+		if plugins[i].Priority != plugins[j].Priority {
+			return plugins[i].Priority < plugins[j].Priority
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	c.JSON(http.StatusOK, model.PluginsResponse{
+		Plugins: plugins,
+		Total:   len(plugins),
+		Enabled: len(enabled),
+	})
+}
+
+// PluginsStatusHandler 查看插件合成探测的最近记录，limit参数限制每个插件返回的记录数
+func PluginsStatusHandler(c *gin.Context) {
+	if searchService == nil {
+		c.JSON(http.StatusServiceUnavailable, model.NewErrorResponse(503, "搜索服务未初始化"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	c.JSON(http.StatusOK, searchService.PluginsStatus(limit))
+}
+
+// UpdatePluginHandler 运行时启用/禁用插件、覆盖插件等级和响应超时，修改会持久化到CACHE_PATH下的状态文件
+func UpdatePluginHandler(c *gin.Context) {
+	if searchService == nil {
+		c.JSON(http.StatusServiceUnavailable, model.NewErrorResponse(503, "搜索服务未初始化"))
+		return
+	}
+
+	var update model.PluginStateUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的请求参数: "+err.Error()))
+		return
+	}
+
+	name := c.Param("name")
+	if err := searchService.UpdatePluginState(name, update); err != nil {
+		if errors.Is(err, service.ErrPluginNotFound) {
+			c.JSON(http.StatusNotFound, model.NewErrorResponse(404, "插件不存在: "+name))
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	p, _ := plugin.GetPluginByName(name)
+	c.JSON(http.StatusOK, pluginInfoOf(p, searchService.GetPluginManager().IsEnabled(name)))
+}
+
+// pluginInfoOf 生成插件目录中的插件信息，等级和超时为当前生效的值
+func pluginInfoOf(p plugin.AsyncSearchPlugin, enabled bool) model.PluginInfo {
+	metadata := plugin.MetadataOf(p)
+	health := plugin.PluginHealthOf(p.Name())
+
+	var openedAt int64
+	if !health.OpenedAt.IsZero() {
+		openedAt = health.OpenedAt.UnixMilli()
+	}
+	return model.PluginInfo{
+		Name:          p.Name(),
+		DisplayName:   metadata.DisplayName,
+		Description:   metadata.Description,
+		Homepage:      metadata.Homepage,
+		Categories:    metadata.Categories,
+		CloudTypes:    metadata.CloudTypes,
+		ExtKeys:       metadata.ExtKeys,
+		RequiresLogin: metadata.RequiresLogin,
+		Enabled:       enabled,
+		Priority:      plugin.EffectivePriority(p),
+		TimeoutMs:     plugin.EffectiveResponseTimeout(p.Name()).Milliseconds(),
+		Health: model.PluginHealthInfo{
+			Score:               math.Round(health.Score*1000) / 1000,
+			Circuit:             health.Circuit,
+			ConsecutiveFailures: health.ConsecutiveFailures,
+			Successes:           health.Successes,
+			Failures:            health.Failures,
+			LastError:           health.LastError,
+			OpenedAt:            openedAt,
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pansou/config"
+	jsonutil "pansou/util/json"
+	"pansou/util/metrics"
+	"pansou/util/ratelimit"
+)
+
+// 限流桶，强制刷新搜索和链接检测会向外部站点发起大量请求，使用单独且更严格的限额
+const (
+	rateLimitBucketDefault = "default"
+	rateLimitBucketRefresh = "refresh"
+	rateLimitBucketCheck   = "check"
+)
+
+// rateLimitPruneInterval 清理空闲令牌桶的间隔
+const rateLimitPruneInterval = time.Minute
+
+var rateLimitRejections = metrics.NewCounterVec("pansou_rate_limit_rejections_total", "因客户端限流被拒绝的请求数", "bucket")
+
+func init() {
+	// 可信代理只在启动时设置，启动时校验格式
+	config.AddValidator(func(cfg *config.Config) error {
+		for _, proxy := range cfg.TrustedProxies {
+			if _, _, err := net.ParseCIDR(proxy); err == nil {
+				continue
+			}
+			if net.ParseIP(proxy) == nil {
+				return fmt.Errorf("TRUSTED_PROXIES无效: %s不是IP或CIDR", proxy)
+			}
+		}
+		return nil
+	})
+}
+
+var (
+	rateLimiters     map[string]*ratelimit.ShardedLimiter
+	rateLimitersOnce sync.Once
+)
+
+func getRateLimiters() map[string]*ratelimit.ShardedLimiter {
+	rateLimitersOnce.Do(func() {
+		cfg := config.Get()
+		rateLimiters = map[string]*ratelimit.ShardedLimiter{
+			rateLimitBucketDefault: ratelimit.NewShardedLimiter(float64(cfg.RateLimitPerMinute)/60, cfg.RateLimitBurst),
+			rateLimitBucketRefresh: ratelimit.NewShardedLimiter(float64(cfg.RateLimitRefreshPerMinute)/60, cfg.RateLimitRefreshBurst),
+			rateLimitBucketCheck:   ratelimit.NewShardedLimiter(float64(cfg.RateLimitCheckPerMinute)/60, cfg.RateLimitCheckBurst),
+		}
+
+		go func() {
+			ticker := time.NewTicker(rateLimitPruneInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				for _, limiter := range rateLimiters {
+					limiter.Prune()
+				}
+			}
+		}()
+	})
+	return rateLimiters
+}
+
+// RateLimitMiddleware 客户端限流中间件，需要放在AuthMiddleware之后
+// 按API密钥、用户或IP（未认证时）分别计数，超出限额时返回429和Retry-After
+func RateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.Get().RateLimitEnabled || c.Request.URL.Path == "/api/health" {
+			c.Next()
+			return
+		}
+
+		bucket := rateLimitBucket(c)
+		ok, retryAfter := getRateLimiters()[bucket].Take(rateLimitClient(c))
+		if !ok {
+			rateLimitRejections.Inc(bucket)
+			c.Header("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds())))))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": "请求过于频繁，请稍后再试",
+				"code":  "RATE_LIMITED",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitClient 返回请求方的限流键：API密钥、已登录用户，否则为客户端IP
+// 客户端IP只在连接来自TRUSTED_PROXIES时才取X-Forwarded-For，见SetupRouter
+func rateLimitClient(c *gin.Context) string {
+	if id := c.GetString("api_key_id"); id != "" {
+		return "key:" + id
+	}
+	if username := c.GetString("username"); username != "" {
+		return "user:" + username
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// rateLimitBucket 根据请求确定使用的限流桶
+func rateLimitBucket(c *gin.Context) string {
+	path := c.Request.URL.Path
+	switch {
+	case path == "/api/check/links":
+		return rateLimitBucketCheck
+	case strings.HasPrefix(path, "/api/search") && isRefreshSearch(c):
+		return rateLimitBucketRefresh
+	default:
+		return rateLimitBucketDefault
+	}
+}
+
+// isRefreshSearch 判断搜索请求是否强制刷新，POST请求读取请求体后放回，供处理函数继续读取
+func isRefreshSearch(c *gin.Context) bool {
+	if c.Request.Method != http.MethodPost {
+		return c.Query("refresh") == "true"
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	var req struct {
+		Refresh bool `json:"refresh"`
+	}
+	return jsonutil.Unmarshal(data, &req) == nil && req.Refresh
+}
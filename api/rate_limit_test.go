@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"pansou/config"
+	"pansou/util/ratelimit"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// setTestConfig 替换当前配置，测试结束时恢复
+func setTestConfig(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	previous := config.Get()
+	config.Set(cfg)
+	t.Cleanup(func() { config.Set(previous) })
+}
+
+func TestRateLimitBucket(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   string
+	}{
+		{"get search", http.MethodGet, "/api/search?kw=a", "", rateLimitBucketDefault},
+		{"get refresh search", http.MethodGet, "/api/search?kw=a&refresh=true", "", rateLimitBucketRefresh},
+		{"stream refresh search", http.MethodGet, "/api/search/stream?kw=a&refresh=true", "", rateLimitBucketRefresh},
+		{"post search", http.MethodPost, "/api/search", `{"kw":"a"}`, rateLimitBucketDefault},
+		{"post refresh search", http.MethodPost, "/api/search", `{"kw":"a","refresh":true}`, rateLimitBucketRefresh},
+		{"post invalid body", http.MethodPost, "/api/search", `{"refresh":`, rateLimitBucketDefault},
+		{"check links", http.MethodPost, "/api/check/links", `{"items":[]}`, rateLimitBucketCheck},
+		{"refresh on other path", http.MethodGet, "/api/plugins?refresh=true", "", rateLimitBucketDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+
+			if got := rateLimitBucket(c); got != tt.want {
+				t.Errorf("rateLimitBucket() = %q, want %q", got, tt.want)
+			}
+			// 读取过的请求体需要放回，供处理函数继续读取
+			body, _ := io.ReadAll(c.Request.Body)
+			if string(body) != tt.body {
+				t.Errorf("请求体 = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	setTestConfig(t, &config.Config{RateLimitEnabled: true})
+	rateLimitersOnce.Do(func() {
+		rateLimiters = map[string]*ratelimit.ShardedLimiter{
+			rateLimitBucketDefault: ratelimit.NewShardedLimiter(1.0/60, 2),
+			rateLimitBucketRefresh: ratelimit.NewShardedLimiter(1.0/60, 1),
+			rateLimitBucketCheck:   ratelimit.NewShardedLimiter(1.0/60, 1),
+		}
+	})
+
+	r := gin.New()
+	r.Use(RateLimitMiddleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/search", ok)
+	r.GET("/api/health", ok)
+
+	do := func(target, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := do("/api/search?kw=a", "192.0.2.1:1000"); w.Code != http.StatusOK {
+			t.Fatalf("请求#%d status = %d, want 200", i+1, w.Code)
+		}
+	}
+
+	w := do("/api/search?kw=a", "192.0.2.1:1000")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 || retryAfter > 60 {
+		t.Errorf("Retry-After = %q, want 1-60", w.Header().Get("Retry-After"))
+	}
+	var resp map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp["code"] != "RATE_LIMITED" {
+		t.Errorf("body = %s, want code RATE_LIMITED", w.Body.String())
+	}
+
+	// 强制刷新使用单独的限额
+	if w := do("/api/search?kw=a&refresh=true", "192.0.2.1:1000"); w.Code != http.StatusOK {
+		t.Errorf("refresh status = %d, want 200", w.Code)
+	}
+	// 其他客户端不受影响，健康检查不限流
+	if w := do("/api/search?kw=a", "192.0.2.2:1000"); w.Code != http.StatusOK {
+		t.Errorf("other client status = %d, want 200", w.Code)
+	}
+	if w := do("/api/health", "192.0.2.1:1000"); w.Code != http.StatusOK {
+		t.Errorf("health status = %d, want 200", w.Code)
+	}
+
+	config.Set(&config.Config{RateLimitEnabled: false})
+	if w := do("/api/search?kw=a", "192.0.2.1:1000"); w.Code != http.StatusOK {
+		t.Errorf("限流关闭时 status = %d, want 200", w.Code)
+	}
+}
+
+func TestRateLimitClient(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		keyID      string
+		username   string
+		want       string
+	}{
+		{"api key", nil, "192.0.2.1:1000", "k1", "alice", "key:k1"},
+		{"user", nil, "192.0.2.1:1000", "", "alice", "user:alice"},
+		{"untrusted proxy ignores forwarded", nil, "192.0.2.1:1000", "", "", "ip:192.0.2.1"},
+		{"trusted proxy uses forwarded", []string{"10.0.0.0/8"}, "10.0.0.1:1000", "", "", "ip:198.51.100.7"},
+		{"remote outside trusted proxies", []string{"10.0.0.0/8"}, "192.0.2.1:1000", "", "", "ip:192.0.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			if err := r.SetTrustedProxies(tt.trusted); err != nil {
+				t.Fatalf("SetTrustedProxies() error = %v", err)
+			}
+			var got string
+			r.GET("/", func(c *gin.Context) {
+				if tt.keyID != "" {
+					c.Set("api_key_id", tt.keyID)
+				}
+				if tt.username != "" {
+					c.Set("username", tt.username)
+				}
+				got = rateLimitClient(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "198.51.100.7")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("rateLimitClient() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrustedProxiesValidation(t *testing.T) {
+	tests := []struct {
+		proxies []string
+		wantErr bool
+	}{
+		{nil, false},
+		{[]string{"127.0.0.1", "10.0.0.0/8", "::1", "fd00::/8"}, false},
+		{[]string{"10.0.0.0/33"}, true},
+		{[]string{"proxy.local"}, true},
+	}
+	for _, tt := range tests {
+		setTestConfig(t, &config.Config{TrustedProxies: tt.proxies})
+		if err := config.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%v) error = %v, wantErr %v", tt.proxies, err, tt.wantErr)
+		}
+	}
+}
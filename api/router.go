@@ -1,8 +1,11 @@
 package api
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 	"pansou/config"
+	"pansou/model"
 	"pansou/plugin"
 	"pansou/service"
 	"pansou/util"
@@ -13,17 +16,29 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 	// 设置搜索服务
 	SetSearchService(searchService)
 	
+	// 搜索结果的链接检测与检测接口共用同一检测服务（共享缓存和限流）
+	if searchService != nil {
+		searchService.SetCheckService(getCheckService())
+	}
+	
 	// 设置为生产模式
 	gin.SetMode(gin.ReleaseMode)
 	
 	// 创建默认路由
 	r := gin.Default()
 	
+	// 只信任配置的反向代理传入的X-Forwarded-For，否则客户端可以伪造IP绕过按IP限流
+	if err := r.SetTrustedProxies(config.Get().TrustedProxies); err != nil {
+		fmt.Printf("[配置] TRUSTED_PROXIES无效，不信任任何代理: %v\n", err)
+		_ = r.SetTrustedProxies(nil)
+	}
+	
 	// 添加中间件
 	r.Use(CORSMiddleware())
 	r.Use(LoggerMiddleware())
 	r.Use(util.GzipMiddleware()) // 添加压缩中间件
 	r.Use(AuthMiddleware())      // 添加认证中间件
+	r.Use(RateLimitMiddleware()) // 添加客户端限流中间件（按认证后的身份计数）
 	
 	// 定义API路由组
 	api := r.Group("/api")
@@ -32,21 +47,45 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", LoginHandler)
+			auth.POST("/refresh", RefreshHandler)
 			auth.POST("/verify", VerifyHandler)
 			auth.POST("/logout", LogoutHandler)
 		}
 		
+		// 只读接口
+		viewer := api.Group("", RequireRoleMiddleware(model.RoleViewer))
+		{
+			viewer.GET("/check/providers", CheckProvidersHandler)
+			viewer.GET("/plugins", PluginsHandler)
+			viewer.GET("/plugins/status", PluginsStatusHandler)
+		}
+		
 		// 搜索接口 - 支持POST和GET两种方式
-		api.POST("/search", SearchHandler)
-		api.GET("/search", SearchHandler) // 添加GET方式支持
-		api.POST("/check/links", CheckHandler)
+		searcher := api.Group("", RequireRoleMiddleware(model.RoleSearcher))
+		{
+			searcher.POST("/search", SearchHandler)
+			searcher.GET("/search", SearchHandler) // 添加GET方式支持
+			searcher.GET("/search/stream", SearchStreamHandler) // 流式搜索（SSE）
+			searcher.POST("/check/links", CheckHandler)
+		}
+		
+		// 管理接口，API密钥管理仅限admin角色
+		admin := api.Group("/admin", RequireRoleMiddleware(model.RoleOperator))
+		{
+			admin.GET("/check/sweeper", CheckSweeperHandler)
+			admin.GET("/config", RequireAuthMiddleware(), RequireRoleMiddleware(model.RoleAdmin), ConfigHandler)
+			admin.PATCH("/plugins/:name", RequireAuthMiddleware(), UpdatePluginHandler)
+			admin.GET("/keys", RequireAuthMiddleware(), RequireRoleMiddleware(model.RoleAdmin), ListAPIKeysHandler)
+			admin.POST("/keys", RequireAuthMiddleware(), RequireRoleMiddleware(model.RoleAdmin), CreateAPIKeyHandler)
+			admin.DELETE("/keys/:id", RequireAuthMiddleware(), RequireRoleMiddleware(model.RoleAdmin), RevokeAPIKeyHandler)
+		}
 		
 		// 健康检查接口
 		api.GET("/health", func(c *gin.Context) {
 			// 根据配置决定是否返回插件信息
 			pluginCount := 0
 			pluginNames := []string{}
-			pluginsEnabled := config.AppConfig.AsyncPluginEnabled
+			pluginsEnabled := config.Get().AsyncPluginEnabled
 			
 			if pluginsEnabled && searchService != nil && searchService.GetPluginManager() != nil {
 				plugins := searchService.GetPluginManager().GetPlugins()
@@ -57,12 +96,12 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 			}
 			
 			// 获取频道信息
-			channels := config.AppConfig.DefaultChannels
+			channels := config.Get().DefaultChannels
 			channelsCount := len(channels)
 			
 			response := gin.H{
 				"status":         "ok",
-				"auth_enabled":   config.AppConfig.AuthEnabled, // 添加认证状态
+				"auth_enabled":   config.Get().AuthEnabled, // 添加认证状态
 				"plugins_enabled": pluginsEnabled,
 				"channels":        channels,
 				"channels_count":  channelsCount,
@@ -78,13 +117,16 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 		})
 	}
 	
+	// Prometheus指标
+	r.GET("/metrics", RequireRoleMiddleware(model.RoleOperator), MetricsHandler)
+	
 	// 注册插件的Web路由（如果插件实现了PluginWithWebHandler接口）
-	// 只有当插件功能启用且插件在启用列表中时才注册路由
-	if config.AppConfig.AsyncPluginEnabled && searchService != nil && searchService.GetPluginManager() != nil {
+	// 只有当插件功能启用且插件在启用列表中时才注册路由，插件管理页面需要operator角色
+	if config.Get().AsyncPluginEnabled && searchService != nil && searchService.GetPluginManager() != nil {
 		enabledPlugins := searchService.GetPluginManager().GetPlugins()
 		for _, p := range enabledPlugins {
 			if webPlugin, ok := p.(plugin.PluginWithWebHandler); ok {
-				webPlugin.RegisterWebRoutes(r.Group(""))
+				webPlugin.RegisterWebRoutes(r.Group("", RequireRoleMiddleware(model.RoleOperator)))
 			}
 		}
 	}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/util"
+)
+
+const testJWTSecret = "router-test-secret"
+
+// newTestRouter 启用认证并创建完整路由，请求日志不输出
+func newTestRouter(t *testing.T, publicPaths []string) *gin.Engine {
+	t.Helper()
+	setTestConfig(t, &config.Config{
+		AuthEnabled:     true,
+		AuthJWTSecret:   testJWTSecret,
+		AuthPublicPaths: publicPaths,
+		CachePath:       t.TempDir(),
+	})
+	previousWriter := gin.DefaultWriter
+	gin.DefaultWriter = io.Discard
+	t.Cleanup(func() { gin.DefaultWriter = previousWriter })
+	return SetupRouter(nil)
+}
+
+func testToken(t *testing.T, role string) string {
+	t.Helper()
+	token, err := util.GenerateToken("user-"+role, role, testJWTSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	return token
+}
+
+// SetupRouter中路由与角色的对应关系即访问策略
+func TestRouterRolePolicy(t *testing.T) {
+	router := newTestRouter(t, []string{"/api/auth/", "/api/health"})
+
+	// kw只有排除条件时搜索接口在调用搜索服务前返回400，用于确认请求通过了角色检查
+	search := "/api/search?kw=" + url.QueryEscape("-预告")
+
+	tests := []struct {
+		role   string
+		method string
+		path   string
+		denied bool
+	}{
+		{model.RoleViewer, http.MethodGet, "/api/check/providers", false},
+		{model.RoleViewer, http.MethodGet, search, true},
+		{model.RoleViewer, http.MethodGet, "/api/search/stream?kw=x", true},
+		{model.RoleViewer, http.MethodPost, "/api/check/links", true},
+
+		{model.RoleSearcher, http.MethodGet, search, false},
+		{model.RoleSearcher, http.MethodGet, "/api/admin/check/sweeper", true},
+		{model.RoleSearcher, http.MethodGet, "/api/admin/config", true},
+		{model.RoleSearcher, http.MethodPatch, "/api/admin/plugins/labi", true},
+		{model.RoleSearcher, http.MethodGet, "/metrics", true},
+
+		{model.RoleOperator, http.MethodGet, search, false},
+		{model.RoleOperator, http.MethodGet, "/metrics", false},
+		{model.RoleOperator, http.MethodGet, "/api/admin/config", true},
+		{model.RoleOperator, http.MethodGet, "/api/admin/keys", true},
+		{model.RoleOperator, http.MethodPost, "/api/admin/keys", true},
+		{model.RoleOperator, http.MethodDelete, "/api/admin/keys/abc", true},
+
+		{model.RoleAdmin, http.MethodGet, "/api/admin/config", false},
+		{model.RoleAdmin, http.MethodGet, "/api/admin/keys", false},
+		{model.RoleAdmin, http.MethodGet, "/metrics", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role+" "+tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+testToken(t, tt.role))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if denied := w.Code == http.StatusForbidden; denied != tt.denied {
+				t.Errorf("status = %d, body = %s, want denied = %v", w.Code, w.Body.String(), tt.denied)
+			}
+			if w.Code == http.StatusUnauthorized {
+				t.Errorf("status = 401, body = %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouterPublicPaths(t *testing.T) {
+	router := newTestRouter(t, []string{"/api/auth/", "/api/health", "/api/search", "/api/check/providers"})
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		// 自定义的公开路径不需要令牌，也不检查角色
+		{"/api/search?kw=" + url.QueryEscape("-预告"), http.StatusBadRequest},
+		{"/api/check/providers", http.StatusOK},
+		{"/api/health", http.StatusOK},
+		// 其余路径仍需认证
+		{"/api/plugins", http.StatusUnauthorized},
+		{"/api/admin/config", http.StatusUnauthorized},
+		{"/metrics", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, body = %s, want %d", w.Code, w.Body.String(), tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pansou/model"
+	jsonutil "pansou/util/json"
+	"pansou/util/query"
+)
+
+// SearchStreamHandler 流式搜索处理函数（Server-Sent Events）
+// 每个TG频道和插件返回结果时推送一次source事件，
+// 全部完成后推送merged事件（merged_by_type快照）和done事件（列出超时的数据源）
+func SearchStreamHandler(c *gin.Context) {
+	req, err := parseSearchQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+	normalizeSearchRequest(&req)
+
+	if err := query.Parse(req.Keyword).Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	compiledFilter, err := compileFilter(req.Filter, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的filter参数: "+err.Error()))
+		return
+	}
+
+	// 设置SSE响应头
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 禁用Nginx缓冲，确保事件及时送达
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	var writeMutex sync.Mutex
+	emit := func(event string, data interface{}) {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+
+		// 客户端已断开，不再写入
+		if c.Request.Context().Err() != nil {
+			return
+		}
+
+		jsonData, err := jsonutil.Marshal(data)
+		if err != nil {
+			return
+		}
+		c.SSEvent(event, string(jsonData))
+		c.Writer.Flush()
+	}
+
+	// 合并快照与普通搜索使用相同的过滤规则
+	var filter func(model.SearchResponse) model.SearchResponse
+	if compiledFilter != nil {
+		filter = func(response model.SearchResponse) model.SearchResponse {
+			return applyResultFilter(response, compiledFilter, "merged_by_type")
+		}
+	}
+
+	err = searchService.SearchStream(c.Request.Context(), req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.SourceType, req.Plugins, req.CloudTypes, req.Ext, req.CheckLinks(), filter, emit)
+	if err != nil {
+		emit(model.StreamEventError, model.NewErrorResponse(500, "搜索失败: "+err.Error()))
+	}
+}
@@ -0,0 +1,82 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pansou/model"
+	utiljson "pansou/util/json"
+)
+
+// aliyunChecker 阿里云盘链接检测
+type aliyunChecker struct{}
+
+func init() {
+	RegisterGlobalChecker(&aliyunChecker{})
+}
+
+func (c *aliyunChecker) DiskType() string {
+	return "aliyun"
+}
+
+func (c *aliyunChecker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "")
+}
+
+func (c *aliyunChecker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	shareID := extractAliyunShareID(normalized)
+	if shareID == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法解析分享地址"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	body, statusCode, err := doJSONRequest(ctx, "POST", "https://api.aliyundrive.com/adrive/v3/share_link/get_share_by_anonymous?share_id="+shareID, map[string]string{
+		"share_id": shareID,
+	}, map[string]string{
+		"content-type": "application/json",
+		"origin":       "https://www.alipan.com",
+		"referer":      "https://www.alipan.com/",
+		"x-canary":     "client=web,app=share,version=v2.3.1",
+	})
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	var parsed struct {
+		ShareName  string `json:"share_name"`
+		ShareTitle string `json:"share_title"`
+		Code       string `json:"code"`
+		Message    string `json:"message"`
+	}
+	_ = utiljson.Unmarshal(body, &parsed)
+
+	switch {
+	case statusCode == http.StatusOK && (parsed.ShareName != "" || parsed.ShareTitle != ""):
+		return BuildResult(item, normalized, StateOK, "链接有效"), nil
+	case strings.Contains(parsed.Code, "NotFound"), strings.Contains(parsed.Code, "Cancelled"):
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	default:
+		return BuildResult(item, normalized, StateUncertain, parsed.Message), nil
+	}
+}
+
+func extractAliyunShareID(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	pathParts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(pathParts) == 0 {
+		return ""
+	}
+
+	return pathParts[len(pathParts)-1]
+}
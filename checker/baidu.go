@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"pansou/model"
+	utiljson "pansou/util/json"
+)
+
+// baiduChecker 百度网盘链接检测
+type baiduChecker struct{}
+
+func init() {
+	RegisterGlobalChecker(&baiduChecker{})
+}
+
+func (c *baiduChecker) DiskType() string {
+	return "baidu"
+}
+
+func (c *baiduChecker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "pwd")
+}
+
+func (c *baiduChecker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	shareID, shortURL, password := extractBaiduShareInfo(normalized)
+	if shareID == "" || shortURL == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法解析分享地址"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
+	defer cancel()
+
+	var bdclnd string
+	if password != "" {
+		verifyURL := fmt.Sprintf("https://pan.baidu.com/share/verify?surl=%s&pwd=%s", url.QueryEscape(shortURL), url.QueryEscape(password))
+		body, _, err := doFormRequest(ctx, "POST", verifyURL, url.Values{
+			"pwd":       {password},
+			"vcode":     {""},
+			"vcode_str": {""},
+		}, map[string]string{
+			"referer":      normalized,
+			"content-type": "application/x-www-form-urlencoded",
+		})
+		if err != nil {
+			return BuildResult(item, normalized, StateUncertain, "验证失败"), err
+		}
+
+		var verifyResp struct {
+			Errno  int    `json:"errno"`
+			Errmsg string `json:"errmsg"`
+			Randsk string `json:"randsk"`
+		}
+		_ = utiljson.Unmarshal(body, &verifyResp)
+
+		switch verifyResp.Errno {
+		case 0:
+			bdclnd = verifyResp.Randsk
+		case -9, -12:
+			return BuildResult(item, normalized, StateLocked, "提取码错误或缺失"), nil
+		default:
+			return BuildResult(item, normalized, StateUncertain, verifyResp.Errmsg), nil
+		}
+	}
+
+	listURL := fmt.Sprintf("https://pan.baidu.com/share/list?web=1&page=1&num=20&order=time&desc=1&showempty=0&shorturl=%s&root=1&clienttype=0", url.QueryEscape(shortURL))
+	headers := map[string]string{
+		"accept":     "application/json, text/plain, */*",
+		"referer":    normalized,
+		"user-agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36",
+	}
+	if bdclnd != "" {
+		headers["cookie"] = fmt.Sprintf("BDCLND=%s", bdclnd)
+	}
+
+	body, _, err := doRequest(ctx, "GET", listURL, nil, headers)
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	var listResp struct {
+		Errno  int    `json:"errno"`
+		Errmsg string `json:"errmsg"`
+		List   []any  `json:"list"`
+	}
+	_ = utiljson.Unmarshal(body, &listResp)
+
+	switch listResp.Errno {
+	case 0:
+		if len(listResp.List) > 0 {
+			return BuildResult(item, normalized, StateOK, "链接有效"), nil
+		}
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	case -9, -12:
+		return BuildResult(item, normalized, StateLocked, "需要提取码"), nil
+	case -7, 105, 115, 117, 145:
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	default:
+		return BuildResult(item, normalized, StateUncertain, listResp.Errmsg), nil
+	}
+}
+
+func extractBaiduShareInfo(rawURL string) (string, string, string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", ""
+	}
+
+	queryPwd := parsed.Query().Get("pwd")
+
+	if strings.HasPrefix(parsed.Path, "/s/") {
+		shareID := strings.TrimPrefix(parsed.Path, "/s/")
+		shortURL := shareID
+		if strings.HasPrefix(shortURL, "1") && len(shortURL) > 1 {
+			shortURL = shortURL[1:]
+		}
+		return shareID, shortURL, queryPwd
+	}
+
+	if strings.HasPrefix(parsed.Path, "/share/init") {
+		shareID := parsed.Query().Get("surl")
+		shortURL := shareID
+		if strings.HasPrefix(shortURL, "1") && len(shortURL) > 1 {
+			shortURL = shortURL[1:]
+		}
+		return shareID, shortURL, queryPwd
+	}
+
+	return "", "", queryPwd
+}
@@ -0,0 +1,147 @@
+package checker
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pansou/model"
+)
+
+// 链接检测状态
+const (
+	StateOK          = "ok"
+	StateBad         = "bad"
+	StateLocked      = "locked"
+	StateUnsupported = "unsupported"
+	StateUncertain   = "uncertain"
+)
+
+// LinkChecker 网盘链接检测器接口
+// 每种网盘实现一个检测器，在init中通过RegisterGlobalChecker注册
+type LinkChecker interface {
+	// DiskType 返回检测器负责的网盘类型，与util.GetLinkType的返回值一致
+	DiskType() string
+	// Normalize 规范化分享链接，结果用作检测缓存键，无效链接返回空字符串
+	Normalize(rawURL, password string) string
+	// Check 检测链接状态；返回error表示检测过程失败（结果不会被缓存）
+	Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error)
+}
+
+// 全局检测器注册表
+var (
+	globalRegistry     = make(map[string]LinkChecker)
+	globalRegistryLock sync.RWMutex
+)
+
+// RegisterGlobalChecker 注册链接检测器到全局注册表，同一网盘类型后注册的覆盖先注册的
+func RegisterGlobalChecker(checker LinkChecker) {
+	if checker == nil {
+		return
+	}
+
+	diskType := checker.DiskType()
+	if diskType == "" {
+		return
+	}
+
+	globalRegistryLock.Lock()
+	defer globalRegistryLock.Unlock()
+
+	globalRegistry[diskType] = checker
+}
+
+// GetChecker 根据网盘类型获取已注册的检测器
+func GetChecker(diskType string) (LinkChecker, bool) {
+	globalRegistryLock.RLock()
+	defer globalRegistryLock.RUnlock()
+
+	checker, exists := globalRegistry[diskType]
+	return checker, exists
+}
+
+// GetRegisteredCheckers 获取所有已注册的检测器，按网盘类型排序
+func GetRegisteredCheckers() []LinkChecker {
+	globalRegistryLock.RLock()
+	defer globalRegistryLock.RUnlock()
+
+	checkers := make([]LinkChecker, 0, len(globalRegistry))
+	for _, checker := range globalRegistry {
+		checkers = append(checkers, checker)
+	}
+	sort.Slice(checkers, func(i, j int) bool {
+		return checkers[i].DiskType() < checkers[j].DiskType()
+	})
+
+	return checkers
+}
+
+// Normalize 使用对应检测器规范化链接，未注册的网盘类型使用默认规则
+func Normalize(diskType, rawURL, password string) string {
+	if checker, ok := GetChecker(diskType); ok {
+		return checker.Normalize(rawURL, password)
+	}
+	return NormalizeShareLink(rawURL, password, "")
+}
+
+// NormalizeShareLink 默认的链接规范化规则：去掉片段、域名转小写、查询参数排序
+// passwordParam非空时，提取码会以该参数名补充到查询参数中（链接中已有时不覆盖）
+func NormalizeShareLink(rawURL, password, passwordParam string) string {
+	base := strings.TrimSpace(rawURL)
+	if base == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+
+	parsed.Fragment = ""
+	parsed.Host = strings.ToLower(parsed.Host)
+
+	query := parsed.Query()
+	if password != "" && passwordParam != "" {
+		if query.Get(passwordParam) == "" {
+			query.Set(passwordParam, password)
+		}
+	}
+
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// BuildResult 构造检测结果，过期时间由状态决定
+func BuildResult(item model.CheckItem, normalized string, state string, summary string) model.CheckResult {
+	now := time.Now()
+	expiresAt := now.Add(TTLForState(state))
+
+	return model.CheckResult{
+		DiskType:      item.DiskType,
+		URL:           item.URL,
+		NormalizedURL: normalized,
+		State:         state,
+		CheckedAt:     now.UnixMilli(),
+		ExpiresAt:     expiresAt.UnixMilli(),
+		Summary:       summary,
+	}
+}
+
+// TTLForState 不同检测状态的缓存有效期
+func TTLForState(state string) time.Duration {
+	switch state {
+	case StateOK:
+		return 24 * time.Hour
+	case StateBad:
+		return 6 * time.Hour
+	case StateLocked:
+		return 12 * time.Hour
+	case StateUnsupported:
+		return 24 * time.Hour
+	default:
+		return 30 * time.Minute
+	}
+}
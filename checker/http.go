@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"pansou/util"
+	utiljson "pansou/util/json"
+)
+
+func doJSONRequest(ctx context.Context, method, targetURL string, payload any, headers map[string]string) ([]byte, int, error) {
+	var reader io.Reader
+	if payload != nil {
+		raw, err := utiljson.Marshal(payload)
+		if err != nil {
+			return nil, 0, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	return doRequest(ctx, method, targetURL, reader, headers)
+}
+
+func doFormRequest(ctx context.Context, method, targetURL string, form url.Values, headers map[string]string) ([]byte, int, error) {
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	if _, ok := headers["content-type"]; !ok {
+		headers["content-type"] = "application/x-www-form-urlencoded"
+	}
+	return doRequest(ctx, method, targetURL, strings.NewReader(form.Encode()), headers)
+}
+
+func doRequest(ctx context.Context, method, targetURL string, body io.Reader, headers map[string]string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, targetURL, body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header.Set("user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := util.GetHTTPClient().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	return raw, resp.StatusCode, nil
+}
+
+func containsAny(content string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(content, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+func coalesce(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func decompressResponseBody(raw []byte, acceptedEncoding string, contentEncoding string) ([]byte, error) {
+	encoding := strings.ToLower(contentEncoding)
+	if encoding == "" {
+		encoding = strings.ToLower(acceptedEncoding)
+	}
+
+	if strings.Contains(encoding, "gzip") {
+		reader, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return raw, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+
+	if strings.Contains(encoding, "deflate") {
+		reader, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return raw, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+
+	return raw, nil
+}
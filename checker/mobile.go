@@ -0,0 +1,125 @@
+package checker
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"pansou/model"
+	utiljson "pansou/util/json"
+)
+
+// mobileChecker 移动云盘链接检测
+type mobileChecker struct{}
+
+func init() {
+	RegisterGlobalChecker(&mobileChecker{})
+}
+
+func (c *mobileChecker) DiskType() string {
+	return "mobile"
+}
+
+func (c *mobileChecker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "")
+}
+
+func (c *mobileChecker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	shareID := extractMobileShareID(normalized)
+	if shareID == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法解析分享地址"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	requestPayload := map[string]any{
+		"getOutLinkInfoReq": map[string]any{
+			"account": "",
+			"linkID":  shareID,
+			"passwd":  item.Password,
+			"caSrt":   1,
+			"coSrt":   1,
+			"srtDr":   0,
+			"bNum":    1,
+			"pCaID":   "root",
+			"eNum":    200,
+		},
+		"commonAccountInfo": map[string]any{
+			"account":     "",
+			"accountType": 1,
+		},
+	}
+
+	encrypted, err := encryptMobilePayload(requestPayload)
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求加密失败"), err
+	}
+
+	requestBody, err := utiljson.Marshal(encrypted)
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求序列化失败"), err
+	}
+
+	body, _, err := doRequest(ctx, "POST", "https://share-kd-njs.yun.139.com/yun-share/richlifeApp/devapp/IOutLink/getOutLinkInfoV6", strings.NewReader(string(requestBody)), map[string]string{
+		"accept":        "application/json, text/plain, */*",
+		"content-type":  "application/json",
+		"hcy-cool-flag": "1",
+		"x-deviceinfo":  "||3|12.27.0|chrome|131.0.0.0|5c7c68368f048245e1ce47f1c0f8f2d0||windows 10|1536X695|zh-CN|||",
+	})
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	decrypted, err := decryptMobilePayload(string(body))
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "响应解密失败"), nil
+	}
+
+	var response map[string]any
+	if err := utiljson.Unmarshal([]byte(decrypted), &response); err != nil {
+		return BuildResult(item, normalized, StateUncertain, "响应解析失败"), nil
+	}
+
+	resultCode, _ := response["resultCode"].(string)
+	description, _ := response["desc"].(string)
+	data := response["data"]
+
+	switch {
+	case resultCode == "0" && data != nil:
+		return BuildResult(item, normalized, StateOK, "链接有效"), nil
+	case containsAny(strings.ToLower(description), []string{"提取码", "密码", "访问码"}):
+		return BuildResult(item, normalized, StateLocked, coalesce(description, "需要提取码")), nil
+	case description != "":
+		if containsAny(strings.ToLower(description), []string{"失效", "不存在", "过期", "取消"}) {
+			return BuildResult(item, normalized, StateBad, description), nil
+		}
+		return BuildResult(item, normalized, StateUncertain, description), nil
+	case resultCode != "":
+		return BuildResult(item, normalized, StateBad, "错误码: "+resultCode), nil
+	default:
+		return BuildResult(item, normalized, StateUncertain, "无法确认链接状态"), nil
+	}
+}
+
+func extractMobileShareID(rawURL string) string {
+	patterns := []string{
+		`https?://(?:www\.)?yun\.139\.com/shareweb/#/w/i/([^&/?#]+)`,
+		`https?://(?:www\.)?caiyun\.139\.com/w/i/([^&/?#]+)`,
+		`https?://(?:www\.)?caiyun\.139\.com/m/i\?([^&/?#]+)`,
+		`https?://caiyun\.feixin\.10086\.cn/([^&/?#]+)`,
+	}
+
+	for _, pattern := range patterns {
+		re := regexp.MustCompile(pattern)
+		matches := re.FindStringSubmatch(rawURL)
+		if len(matches) >= 2 {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
@@ -1,4 +1,4 @@
-package service
+package checker
 
 import (
 	"crypto/aes"
@@ -0,0 +1,144 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"pansou/model"
+	utiljson "pansou/util/json"
+)
+
+// pan115Checker 115网盘链接检测
+type pan115Checker struct{}
+
+func init() {
+	RegisterGlobalChecker(&pan115Checker{})
+}
+
+func (c *pan115Checker) DiskType() string {
+	return "115"
+}
+
+func (c *pan115Checker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "")
+}
+
+func (c *pan115Checker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	shareCode, password := extract115ShareInfo(normalized, item.Password)
+	if shareCode == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法解析分享地址"), nil
+	}
+	if password == "" {
+		return BuildResult(item, normalized, StateLocked, "115 需要提取码"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	apiURL := fmt.Sprintf("https://115cdn.com/webapi/share/snap?share_code=%s&offset=0&limit=20&receive_code=%s&cid=",
+		url.QueryEscape(shareCode), url.QueryEscape(password))
+
+	body, _, err := doRequest(ctx, "GET", apiURL, nil, map[string]string{
+		"priority":            "u=1, i",
+		"referer":             fmt.Sprintf("https://115cdn.com/s/%s?password=%s&", shareCode, password),
+		"x-requested-with":    "XMLHttpRequest",
+		"sec-ch-ua":           `"Chromium";v="142", "Google Chrome";v="142", "Not_A Brand";v="99"`,
+		"sec-ch-ua-mobile":    "?0",
+		"sec-ch-ua-platform":  `"Windows"`,
+		"sec-fetch-dest":      "empty",
+		"sec-fetch-mode":      "cors",
+		"sec-fetch-site":      "same-origin",
+	})
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	var response struct {
+		State bool   `json:"state"`
+		Error string `json:"error"`
+		Errno int    `json:"errno"`
+		Data  struct {
+			List       []any `json:"list"`
+			Count      int   `json:"count"`
+			ShareState int `json:"share_state"`
+			ShareInfo  struct {
+				SnapID       string `json:"snap_id"`
+				ShareTitle   string `json:"share_title"`
+				ShareState   int    `json:"share_state"`
+				ForbidReason string `json:"forbid_reason"`
+			} `json:"shareinfo"`
+		} `json:"data"`
+	}
+	if err := utiljson.Unmarshal(body, &response); err != nil {
+		return BuildResult(item, normalized, StateUncertain, "响应解析失败"), nil
+	}
+
+	if response.State && response.Errno == 0 {
+		if len(response.Data.List) > 0 || response.Data.Count > 0 || response.Data.ShareInfo.SnapID != "" || response.Data.ShareInfo.ShareTitle != "" {
+			return BuildResult(item, normalized, StateOK, "链接有效"), nil
+		}
+
+		shareState := response.Data.ShareState
+		if shareState == 0 {
+			shareState = response.Data.ShareInfo.ShareState
+		}
+
+		if shareState == 1 {
+			return BuildResult(item, normalized, StateOK, "链接有效"), nil
+		}
+
+		reason := strings.TrimSpace(response.Data.ShareInfo.ForbidReason)
+		if reason == "" {
+			reason = fmt.Sprintf("链接状态异常(share_state=%d)", shareState)
+		}
+		if containsAny(strings.ToLower(reason), []string{"密码", "提取码"}) {
+			return BuildResult(item, normalized, StateLocked, reason), nil
+		}
+		return BuildResult(item, normalized, StateBad, reason), nil
+	}
+
+	if containsAny(strings.ToLower(response.Error), []string{"密码", "提取码", "receive_code"}) {
+		return BuildResult(item, normalized, StateLocked, coalesce(response.Error, "需要提取码")), nil
+	}
+
+	if containsAny(strings.ToLower(response.Error), []string{"参数错误", "不存在", "失效", "share_code", "forbid", "forbidden", "违规", "删除", "取消"}) {
+		return BuildResult(item, normalized, StateBad, coalesce(response.Error, "链接失效")), nil
+	}
+
+	if response.Error == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法确认链接状态"), nil
+	}
+
+	return BuildResult(item, normalized, StateBad, response.Error), nil
+}
+
+func extract115ShareInfo(rawURL string, fallbackPassword string) (string, string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fallbackPassword
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) == 0 {
+		return "", fallbackPassword
+	}
+
+	shareCode := parts[len(parts)-1]
+	password := parsed.Query().Get("password")
+	if password == "" {
+		password = fallbackPassword
+	}
+
+	if password == "" && parsed.Fragment != "" && strings.Contains(parsed.Fragment, "password=") {
+		if values, err := url.ParseQuery(parsed.Fragment); err == nil {
+			password = values.Get("password")
+		}
+	}
+
+	return shareCode, password
+}
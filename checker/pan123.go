@@ -0,0 +1,100 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"pansou/model"
+	utiljson "pansou/util/json"
+)
+
+// pan123Checker 123网盘链接检测
+type pan123Checker struct{}
+
+func init() {
+	RegisterGlobalChecker(&pan123Checker{})
+}
+
+func (c *pan123Checker) DiskType() string {
+	return "123"
+}
+
+func (c *pan123Checker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "")
+}
+
+func (c *pan123Checker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	shareKey := extract123ShareKey(normalized)
+	if shareKey == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法解析分享地址"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 8*time.Second)
+	defer cancel()
+
+	apiURL := fmt.Sprintf("https://www.123pan.com/api/share/info?shareKey=%s", url.QueryEscape(shareKey))
+	body, statusCode, err := doRequest(ctx, "GET", apiURL, nil, nil)
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	if statusCode == http.StatusForbidden {
+		return BuildResult(item, normalized, StateOK, "链接有效"), nil
+	}
+
+	var response struct {
+		Code int `json:"code"`
+		Data struct {
+			HasPwd bool `json:"HasPwd"`
+		} `json:"data"`
+		Message string `json:"message"`
+	}
+	if err := utiljson.Unmarshal(body, &response); err != nil {
+		return BuildResult(item, normalized, StateUncertain, "响应解析失败"), nil
+	}
+
+	switch {
+	case response.Code == 0:
+		return BuildResult(item, normalized, StateOK, "链接有效"), nil
+	case response.Data.HasPwd:
+		return BuildResult(item, normalized, StateLocked, "需要提取码"), nil
+	case response.Message != "":
+		return BuildResult(item, normalized, StateBad, response.Message), nil
+	default:
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	}
+}
+
+func extract123ShareKey(rawURL string) string {
+	patterns := []string{
+		`https?://(?:www\.)?(?:123684|123685|123912|123pan|123592|123865)\.com/s/([a-zA-Z0-9-]+)`,
+		`https?://(?:www\.)?123pan\.cn/s/([a-zA-Z0-9-]+)`,
+	}
+
+	for _, pattern := range patterns {
+		re := regexp.MustCompile(pattern)
+		matches := re.FindStringSubmatch(rawURL)
+		if len(matches) >= 2 {
+			return matches[1]
+		}
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,122 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"pansou/model"
+	utiljson "pansou/util/json"
+)
+
+// quarkChecker 夸克网盘链接检测
+type quarkChecker struct{}
+
+func init() {
+	RegisterGlobalChecker(&quarkChecker{})
+}
+
+func (c *quarkChecker) DiskType() string {
+	return "quark"
+}
+
+func (c *quarkChecker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "pwd")
+}
+
+func (c *quarkChecker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	resourceID, password := extractQuarkShareIDAndPassword(normalized)
+	if resourceID == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法解析分享地址"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tokenBody, _, err := doJSONRequest(ctx, "POST", "https://drive-h.quark.cn/1/clouddrive/share/sharepage/token", map[string]any{
+		"pwd_id":                            resourceID,
+		"passcode":                          password,
+		"support_visit_limit_private_share": true,
+	}, map[string]string{
+		"content-type": "application/json",
+		"origin":       "https://pan.quark.cn",
+		"referer":      "https://pan.quark.cn/",
+	})
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	var tokenResp struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Stoken string `json:"stoken"`
+		} `json:"data"`
+	}
+	_ = utiljson.Unmarshal(tokenBody, &tokenResp)
+
+	switch tokenResp.Code {
+	case 0:
+	case 41008:
+		return BuildResult(item, normalized, StateLocked, "需要提取码"), nil
+	case 41004, 41010, 41011:
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	default:
+		if containsAny(strings.ToLower(tokenResp.Message), []string{"不存在", "失效", "违规", "过期", "取消"}) {
+			return BuildResult(item, normalized, StateBad, tokenResp.Message), nil
+		}
+		if containsAny(strings.ToLower(tokenResp.Message), []string{"提取码", "密码"}) {
+			return BuildResult(item, normalized, StateLocked, tokenResp.Message), nil
+		}
+		return BuildResult(item, normalized, StateUncertain, tokenResp.Message), nil
+	}
+
+	if tokenResp.Data.Stoken == "" {
+		return BuildResult(item, normalized, StateUncertain, "访问令牌缺失"), nil
+	}
+
+	detailURL := fmt.Sprintf("https://drive-pc.quark.cn/1/clouddrive/share/sharepage/detail?pwd_id=%s&stoken=%s&ver=2&pr=ucpro", url.QueryEscape(resourceID), url.QueryEscape(tokenResp.Data.Stoken))
+	detailBody, _, err := doRequest(ctx, "GET", detailURL, nil, map[string]string{
+		"accept":        "application/json, text/plain, */*",
+		"origin":        "https://pan.quark.cn",
+		"referer":       "https://pan.quark.cn/",
+		"cache-control": "no-cache",
+	})
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "详情请求失败"), err
+	}
+
+	var detailResp struct {
+		Code int `json:"code"`
+		Data struct {
+			List []any `json:"list"`
+		} `json:"data"`
+	}
+	_ = utiljson.Unmarshal(detailBody, &detailResp)
+
+	if detailResp.Code == 0 && len(detailResp.Data.List) > 0 {
+		return BuildResult(item, normalized, StateOK, "链接有效"), nil
+	}
+
+	return BuildResult(item, normalized, StateUncertain, "无法确认链接状态"), nil
+}
+
+func extractQuarkShareIDAndPassword(rawURL string) (string, string) {
+	re := regexp.MustCompile(`/s/([A-Za-z0-9]+)`)
+	matches := re.FindStringSubmatch(rawURL)
+	if len(matches) < 2 {
+		return "", ""
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return matches[1], ""
+	}
+
+	return matches[1], parsed.Query().Get("pwd")
+}
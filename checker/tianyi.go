@@ -0,0 +1,150 @@
+package checker
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"pansou/model"
+)
+
+// tianyiChecker 天翼云盘链接检测
+type tianyiChecker struct{}
+
+func init() {
+	RegisterGlobalChecker(&tianyiChecker{})
+}
+
+func (c *tianyiChecker) DiskType() string {
+	return "tianyi"
+}
+
+func (c *tianyiChecker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "")
+}
+
+func (c *tianyiChecker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	shareCode, password, referer := extractTianyiShareInfo(normalized, item.Password)
+	if shareCode == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法解析分享地址"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	noCache := fmt.Sprintf("%f", rand.New(rand.NewSource(time.Now().UnixNano())).Float64())
+	shareCodeParam := shareCode
+	if password != "" {
+		shareCodeParam = fmt.Sprintf("%s（访问码：%s）", shareCode, password)
+	}
+
+	apiURL := "https://cloud.189.cn/api/open/share/getShareInfoByCodeV2.action"
+	targetURL, err := url.Parse(apiURL)
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求地址构造失败"), err
+	}
+
+	query := targetURL.Query()
+	query.Set("noCache", noCache)
+	query.Set("shareCode", shareCodeParam)
+	targetURL.RawQuery = query.Encode()
+
+	body, statusCode, err := doRequest(ctx, "GET", targetURL.String(), nil, map[string]string{
+		"referer":   referer,
+		"sign-type": "1",
+	})
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	bodyText := strings.TrimSpace(string(body))
+
+	var shareResponse struct {
+		XMLName        xml.Name `xml:"shareVO"`
+		NeedAccessCode int      `xml:"needAccessCode"`
+		ShareID        int64    `xml:"shareId"`
+		FileName       string   `xml:"fileName"`
+		AccessCode     string   `xml:"accessCode"`
+	}
+	if err := xml.Unmarshal(body, &shareResponse); err == nil && shareResponse.XMLName.Local == "shareVO" {
+		switch {
+		case shareResponse.ShareID > 0:
+			return BuildResult(item, normalized, StateOK, "链接有效"), nil
+		case shareResponse.FileName != "":
+			return BuildResult(item, normalized, StateOK, "链接有效"), nil
+		case shareResponse.NeedAccessCode == 1:
+			return BuildResult(item, normalized, StateOK, "链接有效"), nil
+		}
+	}
+
+	var errorResponse struct {
+		XMLName xml.Name `xml:"error"`
+		Code    string   `xml:"code"`
+		Message string   `xml:"message"`
+	}
+	if err := xml.Unmarshal(body, &errorResponse); err == nil && errorResponse.XMLName.Local == "error" {
+		message := coalesce(errorResponse.Message, errorResponse.Code)
+		messageLower := strings.ToLower(message)
+
+		switch {
+		case containsAny(messageLower, []string{"accesscode", "访问码", "提取码", "密码"}):
+			return BuildResult(item, normalized, StateLocked, message), nil
+		case containsAny(messageLower, []string{"shareinfonotfound", "sharenotfound", "filenotfound", "shareexpirederror", "shareauditnotpass", "不存在", "失效", "取消", "过期"}):
+			return BuildResult(item, normalized, StateBad, message), nil
+		}
+		return BuildResult(item, normalized, StateBad, message), nil
+	}
+
+	switch {
+	case statusCode == http.StatusOK && strings.Contains(bodyText, "<shareVO>"):
+		if strings.Contains(bodyText, "<shareId>") || strings.Contains(bodyText, "<fileName>") {
+			return BuildResult(item, normalized, StateOK, "链接有效"), nil
+		}
+		if strings.Contains(bodyText, "<needAccessCode>1</needAccessCode>") {
+			return BuildResult(item, normalized, StateOK, "链接有效"), nil
+		}
+		return BuildResult(item, normalized, StateUncertain, "无法确认链接状态"), nil
+	case containsAny(strings.ToLower(bodyText), []string{"erroraccesscode", "needaccesscode", "访问码", "提取码", "密码"}):
+		return BuildResult(item, normalized, StateLocked, "需要访问码"), nil
+	case containsAny(strings.ToLower(bodyText), []string{"shareinfonotfound", "sharenotfound", "filenotfound", "shareexpirederror", "shareauditnotpass", "不存在", "失效", "取消", "过期"}):
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	default:
+		return BuildResult(item, normalized, StateUncertain, "无法确认链接状态"), nil
+	}
+}
+
+func extractTianyiShareInfo(rawURL string, fallbackPassword string) (string, string, string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fallbackPassword, rawURL
+	}
+
+	shareCode := parsed.Query().Get("code")
+	if shareCode == "" && strings.HasPrefix(parsed.Path, "/t/") {
+		shareCode = strings.TrimPrefix(parsed.Path, "/t/")
+	}
+	if shareCode == "" && strings.HasPrefix(parsed.Fragment, "/t/") {
+		shareCode = strings.TrimPrefix(parsed.Fragment, "/t/")
+	}
+
+	if index := strings.Index(shareCode, "/"); index >= 0 {
+		shareCode = shareCode[:index]
+	}
+
+	password := fallbackPassword
+	re := regexp.MustCompile(`（访问码[：:]\s*([a-zA-Z0-9]+)）`)
+	matches := re.FindStringSubmatch(rawURL)
+	if len(matches) >= 2 && matches[1] != "" {
+		password = matches[1]
+	}
+
+	return shareCode, password, rawURL
+}
@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"pansou/model"
+)
+
+// ucChecker UC网盘链接检测
+type ucChecker struct{}
+
+func init() {
+	RegisterGlobalChecker(&ucChecker{})
+}
+
+func (c *ucChecker) DiskType() string {
+	return "uc"
+}
+
+func (c *ucChecker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "pwd")
+}
+
+func (c *ucChecker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	ctx, cancel := context.WithTimeout(ctx, 8*time.Second)
+	defer cancel()
+
+	body, statusCode, err := doRequest(ctx, "GET", normalized, nil, map[string]string{
+		"user-agent": "Mozilla/5.0 (Linux; Android 10; Mobile) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36",
+	})
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	if statusCode == http.StatusNotFound {
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	}
+
+	pageText := strings.ToLower(string(body))
+	switch {
+	case containsAny(pageText, []string{"失效", "不存在", "违规", "删除", "已过期", "被取消"}):
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	case containsAny(pageText, []string{"提取码", "访问码", "请输入密码"}):
+		return BuildResult(item, normalized, StateLocked, "需要提取码"), nil
+	case containsAny(pageText, []string{"文件", "分享", "drive.uc.cn"}):
+		return BuildResult(item, normalized, StateOK, "链接有效"), nil
+	default:
+		return BuildResult(item, normalized, StateUncertain, "无法确认链接状态"), nil
+	}
+}
@@ -0,0 +1,200 @@
+package checker
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"pansou/model"
+	utiljson "pansou/util/json"
+)
+
+// xunleiChecker 迅雷网盘链接检测
+type xunleiChecker struct{}
+
+func init() {
+	RegisterGlobalChecker(&xunleiChecker{})
+}
+
+func (c *xunleiChecker) DiskType() string {
+	return "xunlei"
+}
+
+func (c *xunleiChecker) Normalize(rawURL, password string) string {
+	return NormalizeShareLink(rawURL, password, "")
+}
+
+func (c *xunleiChecker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	normalized := c.Normalize(item.URL, item.Password)
+
+	shareID, password := extractXunleiShareInfo(normalized)
+	if shareID == "" {
+		return BuildResult(item, normalized, StateUncertain, "无法解析分享地址"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
+	defer cancel()
+
+	captchaToken, _ := fetchXunleiCaptchaToken(ctx)
+
+	apiURL := fmt.Sprintf("https://api-pan.xunlei.com/drive/v1/share?share_id=%s&pass_code=%s&limit=100&pass_code_token=&page_token=&thumbnail_size=SIZE_SMALL",
+		url.QueryEscape(shareID), url.QueryEscape(password))
+
+	headers := map[string]string{
+		"accept":          "*/*",
+		"content-type":    "application/json",
+		"origin":          "https://pan.xunlei.com",
+		"referer":         "https://pan.xunlei.com/",
+		"user-agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36",
+		"accept-encoding": "gzip, deflate",
+		"x-client-id":     "ZUBzD9J_XPXfn7f7",
+		"x-device-id":     "5505bd0cab8c9469b98e5891d9fb3e0d",
+	}
+	if captchaToken != "" {
+		headers["x-captcha-token"] = captchaToken
+	}
+
+	body, statusCode, err := doRequest(ctx, "GET", apiURL, nil, headers)
+	if err != nil {
+		return BuildResult(item, normalized, StateUncertain, "请求失败"), err
+	}
+
+	body, _ = decompressResponseBody(body, headers["accept-encoding"], "")
+
+	if statusCode == http.StatusNotFound || statusCode == http.StatusForbidden {
+		return BuildResult(item, normalized, StateBad, "链接失效"), nil
+	}
+
+	var response struct {
+		ErrorCode       int    `json:"error_code"`
+		Error           string `json:"error"`
+		ErrorMsg        string `json:"error_description"`
+		ShareID         string `json:"share_id"`
+		PassCode        string `json:"pass_code"`
+		FileCount       int    `json:"file_count"`
+		ShareName       string `json:"share_name"`
+		ShareStatus     string `json:"share_status"`
+		ShareStatusText string `json:"share_status_text"`
+	}
+	if err := utiljson.Unmarshal(body, &response); err != nil {
+		return BuildResult(item, normalized, StateUncertain, "响应解析失败"), nil
+	}
+
+	switch {
+	case response.ShareStatus == "OK":
+		return BuildResult(item, normalized, StateOK, "链接有效"), nil
+	case response.ShareID != "", response.ShareName != "", response.FileCount > 0:
+		return BuildResult(item, normalized, StateOK, "链接有效"), nil
+	case containsAny(strings.ToLower(response.Error), []string{"pass_code"}), containsAny(strings.ToLower(response.ErrorMsg), []string{"pass_code", "提取码", "密码"}):
+		return BuildResult(item, normalized, StateLocked, coalesce(response.ErrorMsg, "需要提取码")), nil
+	case containsAny(strings.ToLower(response.ShareStatus), []string{"pass_code"}), containsAny(strings.ToLower(response.ShareStatusText), []string{"pass_code", "提取码", "密码"}):
+		return BuildResult(item, normalized, StateLocked, coalesce(response.ShareStatusText, "需要提取码")), nil
+	case response.ShareStatus != "" && response.ShareStatus != "OK":
+		summary := coalesce(response.ShareStatusText, fmt.Sprintf("分享状态: %s", response.ShareStatus))
+		if containsAny(strings.ToLower(summary), []string{"不存在", "失效", "过期", "not found", "deleted"}) {
+			return BuildResult(item, normalized, StateBad, summary), nil
+		}
+		return BuildResult(item, normalized, StateBad, summary), nil
+	case response.ErrorCode != 0 || response.Error != "" || response.ErrorMsg != "":
+		if containsAny(strings.ToLower(response.ErrorMsg), []string{"参数错误", "share_status", "不存在", "失效", "过期", "not found"}) {
+			return BuildResult(item, normalized, StateBad, coalesce(response.ErrorMsg, "链接失效")), nil
+		}
+		if containsAny(strings.ToLower(response.Error), []string{"参数错误", "share_status", "不存在", "失效", "过期", "not found"}) {
+			return BuildResult(item, normalized, StateBad, coalesce(response.ErrorMsg, response.Error)), nil
+		}
+		if containsAny(strings.ToLower(response.ErrorMsg), []string{"not found", "不存在", "失效", "过期"}) {
+			return BuildResult(item, normalized, StateBad, coalesce(response.ErrorMsg, "链接失效")), nil
+		}
+		return BuildResult(item, normalized, StateUncertain, coalesce(response.ErrorMsg, response.Error)), nil
+	default:
+		return BuildResult(item, normalized, StateUncertain, "无法确认链接状态"), nil
+	}
+}
+
+func fetchXunleiCaptchaToken(ctx context.Context) (string, error) {
+	deviceID := "5505bd0cab8c9469b98e5891d9fb3e0d"
+	clientID := "ZUBzD9J_XPXfn7f7"
+	clientVersion := "1.10.0.2633"
+	packageName := "com.xunlei.browser"
+	timestamp, signature := buildXunleiCaptchaSignature(clientID, clientVersion, packageName, deviceID)
+
+	requestBody := map[string]any{
+		"action":        "get:/drive/v1/share",
+		"captcha_token": "",
+		"client_id":     clientID,
+		"device_id":     deviceID,
+		"meta": map[string]any{
+			"timestamp":      timestamp,
+			"captcha_sign":   signature,
+			"client_version": clientVersion,
+			"package_name":   packageName,
+		},
+		"redirect_uri": "xlaccsdk01://xunlei.com/callback?state=harbor",
+	}
+
+	body, _, err := doJSONRequest(ctx, "POST", "https://xluser-ssl.xunlei.com/v1/shield/captcha/init", requestBody, map[string]string{
+		"accept":           "application/json;charset=UTF-8",
+		"content-type":     "application/json",
+		"x-device-id":      deviceID,
+		"x-client-id":      clientID,
+		"x-client-version": clientVersion,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		CaptchaToken string `json:"captcha_token"`
+		URL          string `json:"url"`
+	}
+	if err := utiljson.Unmarshal(body, &response); err != nil {
+		return "", err
+	}
+	if response.URL != "" {
+		return "", fmt.Errorf("xunlei captcha required")
+	}
+	return response.CaptchaToken, nil
+}
+
+func buildXunleiCaptchaSignature(clientID, clientVersion, packageName, deviceID string) (string, string) {
+	timestamp := fmt.Sprint(time.Now().UnixMilli())
+	content := fmt.Sprint(clientID, clientVersion, packageName, deviceID, timestamp)
+	parts := []string{
+		"uWRwO7gPfdPB/0NfPtfQO+71",
+		"F93x+qPluYy6jdgNpq+lwdH1ap6WOM+nfz8/V",
+		"0HbpxvpXFsBK5CoTKam",
+		"dQhzbhzFRcawnsZqRETT9AuPAJ+wTQso82mRv",
+		"SAH98AmLZLRa6DB2u68sGhyiDh15guJpXhBzI",
+		"unqfo7Z64Rie9RNHMOB",
+		"7yxUdFADp3DOBvXdz0DPuKNVT35wqa5z0DEyEvf",
+		"RBG",
+		"ThTWPG5eC0UBqlbQ+04nZAptqGCdpv9o55A",
+	}
+
+	for _, part := range parts {
+		sum := md5.Sum([]byte(content + part))
+		content = fmt.Sprintf("%x", sum)
+	}
+
+	return timestamp, "1." + content
+}
+
+func extractXunleiShareInfo(rawURL string) (string, string) {
+	re := regexp.MustCompile(`pan\.xunlei\.com/s/([^?/#]+)`)
+	matches := re.FindStringSubmatch(rawURL)
+	if len(matches) < 2 {
+		return "", ""
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return matches[1], ""
+	}
+
+	return matches[1], parsed.Query().Get("pwd")
+}
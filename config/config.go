@@ -1,13 +1,20 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"pansou/model"
 )
 
 // Config 应用配置结构
@@ -48,23 +55,84 @@ type Config struct {
 	HTTPIdleTimeout  time.Duration // 空闲超时
 	HTTPMaxConns     int           // 最大连接数
 	// 认证相关配置
-	AuthEnabled     bool              // 是否启用认证
-	AuthUsers       map[string]string // 用户名:密码映射
-	AuthTokenExpiry time.Duration     // Token有效期
-	AuthJWTSecret   string            // JWT签名密钥
+	AuthEnabled           bool              // 是否启用认证
+	AuthUsers             map[string]string // 用户名:密码映射，密码可以是bcrypt或argon2id哈希
+	AuthTokenExpiry       time.Duration     // 登录会话（刷新令牌）有效期
+	AuthAccessTokenExpiry time.Duration     // 访问令牌有效期
+	AuthJWTSecret         string            // JWT签名密钥
+	AuthUserRoles         map[string]string // 用户名:角色映射
+	AuthDefaultRole       string            // 未在AuthUserRoles中配置的用户的角色
+	AuthPublicPaths       []string          // 不需要认证的路径前缀
+	// 链接检测相关配置
+	CheckConcurrency   int           // 同时进行的链接检测数
+	CheckTimeout       time.Duration // 单次批量检测的总时限，超时未完成的链接标记为uncertain
+	CheckRatePerSecond float64       // 每种网盘每秒最多发起的检测数
+	CheckRateBurst     int           // 每种网盘允许的突发检测数
+	CheckSweepInterval time.Duration // 检测缓存后台复检的间隔，为0时不复检
+	CheckSweepWindow   time.Duration // 复检在该时长内即将过期的有效链接
+	CheckSweepBatch    int           // 每轮最多复检的链接数
+	// 搜索结果链接检测相关配置
+	SearchCheckEnabled bool          // 搜索请求未指定check参数时是否检测链接有效性
+	SearchCheckTopN    int           // 每种网盘类型检测的前N个链接
+	SearchCheckTimeout time.Duration // 搜索中链接检测的时间预算
+	SearchCheckDropBad bool          // 是否移除已知失效的链接（否则排到该类型末尾）
+	// TG频道搜索相关配置
+	TGSearchMaxPages int           // 每个频道最多抓取的搜索结果页数
+	TGSearchTimeout  time.Duration // 每个频道抓取全部页的时间预算
+	// 插件熔断相关配置
+	PluginBreakerThreshold int           // 连续失败（出错或超时）多少次后熔断，为0时不熔断
+	PluginBreakerCooldown  time.Duration // 熔断后跳过插件的冷却时间
+	// 插件合成探测相关配置
+	PluginProbeInterval time.Duration // 定期探测已启用插件的间隔，为0时不探测
+	PluginProbeKeyword  string        // 探测使用的关键词
+	PluginProbeHistory  int           // 每个插件保留的最近探测记录数
+	// 客户端限流相关配置
+	RateLimitEnabled          bool     // 是否按客户端（API密钥、用户或IP）限流
+	RateLimitPerMinute        int      // 每个客户端每分钟的请求数
+	RateLimitBurst            int      // 每个客户端允许的突发请求数
+	RateLimitRefreshPerMinute int      // 每个客户端每分钟的强制刷新搜索数
+	RateLimitRefreshBurst     int      // 每个客户端允许的突发强制刷新搜索数
+	RateLimitCheckPerMinute   int      // 每个客户端每分钟的链接检测请求数
+	RateLimitCheckBurst       int      // 每个客户端允许的突发链接检测请求数
+	TrustedProxies            []string // 可信反向代理的IP或CIDR，只有来自这些地址的请求才按X-Forwarded-For确定客户端IP
+	// 配置文件相关配置
+	Plugins       map[string]PluginConfig // 配置文件中各插件的设置
+	DefaultFilter *model.FilterConfig     // 搜索请求未指定filter时使用的默认过滤配置
+
+}
 
+// 全局配置实例，热加载时整体替换，通过Get读取
+var current atomic.Pointer[Config]
+
+// Get 返回当前生效的配置
+// 返回的对象不会再被修改，热加载后再次调用Get才能读到新配置
+func Get() *Config {
+	return current.Load()
 }
 
-// 全局配置实例
-var AppConfig *Config
+// Set 替换当前配置
+func Set(cfg *Config) {
+	current.Store(cfg)
+}
 
 // 初始化配置
 func Init() {
+	Set(load())
+	if configLoadedAt.IsZero() {
+		configLoadedAt = time.Now()
+	}
+
+	// 应用GC配置
+	applyGCSettings()
+}
+
+// load 从环境变量（包括配置文件提供的值）构建配置
+func load() *Config {
 	proxyURL := getProxyURL()
 	pluginTimeoutSeconds := getPluginTimeout()
 	asyncResponseTimeoutSeconds := getAsyncResponseTimeout()
 	
-	AppConfig = &Config{
+	cfg := &Config{
 		DefaultChannels:    getDefaultChannels(),
 		DefaultConcurrency: getDefaultConcurrency(),
 		Port:               getPort(),
@@ -101,15 +169,61 @@ func Init() {
 		HTTPIdleTimeout:  getHTTPIdleTimeout(),
 		HTTPMaxConns:     getHTTPMaxConns(),
 		// 认证相关配置
-		AuthEnabled:     getAuthEnabled(),
-		AuthUsers:       getAuthUsers(),
-		AuthTokenExpiry: getAuthTokenExpiry(),
-		AuthJWTSecret:   getAuthJWTSecret(),
+		AuthEnabled:           getAuthEnabled(),
+		AuthUsers:             getAuthUsers(),
+		AuthTokenExpiry:       getAuthTokenExpiry(),
+		AuthAccessTokenExpiry: getAuthAccessTokenExpiry(),
+		AuthJWTSecret:         getAuthJWTSecret(),
+		AuthUserRoles:         getAuthUserRoles(),
+		AuthDefaultRole:       getAuthDefaultRole(),
+		AuthPublicPaths:       getAuthPublicPaths(),
+		// 链接检测相关配置
+		CheckConcurrency:   getCheckConcurrency(),
+		CheckTimeout:       getCheckTimeout(),
+		CheckRatePerSecond: getCheckRatePerSecond(),
+		CheckRateBurst:     getCheckRateBurst(),
+		CheckSweepInterval: getCheckSweepInterval(),
+		CheckSweepWindow:   getCheckSweepWindow(),
+		CheckSweepBatch:    getCheckSweepBatch(),
+		// 搜索结果链接检测相关配置
+		SearchCheckEnabled: getSearchCheckEnabled(),
+		SearchCheckTopN:    getSearchCheckTopN(),
+		SearchCheckTimeout: getSearchCheckTimeout(),
+		SearchCheckDropBad: getSearchCheckDropBad(),
+		// TG频道搜索相关配置
+		TGSearchMaxPages: getTGSearchMaxPages(),
+		TGSearchTimeout:  getTGSearchTimeout(),
+		// 插件熔断相关配置
+		PluginBreakerThreshold: getPluginBreakerThreshold(),
+		PluginBreakerCooldown:  getPluginBreakerCooldown(),
+		// 插件合成探测相关配置
+		PluginProbeInterval: getPluginProbeInterval(),
+		PluginProbeKeyword:  getPluginProbeKeyword(),
+		PluginProbeHistory:  getPluginProbeHistory(),
+		// 客户端限流相关配置
+		RateLimitEnabled:          getRateLimitEnabled(),
+		RateLimitPerMinute:        getRateLimitPerMinute(),
+		RateLimitBurst:            getRateLimitBurst(),
+		RateLimitRefreshPerMinute: getRateLimitRefreshPerMinute(),
+		RateLimitRefreshBurst:     getRateLimitRefreshBurst(),
+		RateLimitCheckPerMinute:   getRateLimitCheckPerMinute(),
+		RateLimitCheckBurst:       getRateLimitCheckBurst(),
+		TrustedProxies:            getTrustedProxies(),
+		// 配置文件相关配置
+		Plugins:       getFilePlugins(),
+		DefaultFilter: getDefaultFilter(),
 
 	}
-	
-	// 应用GC配置
-	applyGCSettings()
+
+	// 访问令牌的有效期不超过登录会话
+	if cfg.AuthAccessTokenExpiry > cfg.AuthTokenExpiry {
+		cfg.AuthAccessTokenExpiry = cfg.AuthTokenExpiry
+	}
+	// 未设置JWT密钥时使用保存在缓存目录中的随机密钥，重启后已签发的令牌仍然有效
+	if cfg.AuthEnabled && cfg.AuthJWTSecret == "" {
+		cfg.AuthJWTSecret = loadOrCreateJWTSecret(cfg.CachePath)
+	}
+	return cfg
 }
 
 // 从环境变量获取默认频道列表，如果未设置则使用默认值
@@ -162,7 +276,7 @@ func getDefaultConcurrency() int {
 // 更新默认并发数（根据实际插件数或0调用）
 // pluginCount: 如果插件被禁用则为0，否则为实际插件数
 func UpdateDefaultConcurrency(pluginCount int) {
-	if AppConfig == nil {
+	if Get() == nil {
 		return
 	}
 	
@@ -172,8 +286,12 @@ func UpdateDefaultConcurrency(pluginCount int) {
 		return
 	}
 	
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
 	// 计算频道数
-	channelCount := len(AppConfig.DefaultChannels)
+	cfg := *Get()
+	channelCount := len(cfg.DefaultChannels)
 	
 	// 计算并发数 = 频道数 + 插件数（插件禁用时为0）+ 10
 	concurrency := channelCount + pluginCount + 10
@@ -181,8 +299,9 @@ func UpdateDefaultConcurrency(pluginCount int) {
 		concurrency = 1 // 确保至少为1
 	}
 	
-	// 更新配置
-	AppConfig.DefaultConcurrency = concurrency
+	// 更新配置，复制后整体替换
+	cfg.DefaultConcurrency = concurrency
+	Set(&cfg)
 }
 
 // 从环境变量获取服务端口，如果未设置则使用默认值
@@ -535,6 +654,7 @@ func getAuthEnabled() bool {
 }
 
 // 从环境变量获取用户配置，格式：user1:pass1,user2:pass2
+// 密码可以是明文、bcrypt哈希或argon2id哈希（其中的逗号不作为用户分隔符）
 func getAuthUsers() map[string]string {
 	usersEnv := os.Getenv("AUTH_USERS")
 	if usersEnv == "" {
@@ -542,7 +662,15 @@ func getAuthUsers() map[string]string {
 	}
 	
 	users := make(map[string]string)
-	pairs := strings.Split(usersEnv, ",")
+	var pairs []string
+	for _, part := range strings.Split(usersEnv, ",") {
+		// 不含冒号的片段属于上一个用户的argon2id参数（m=...,t=...,p=...）
+		if !strings.Contains(part, ":") && len(pairs) > 0 {
+			pairs[len(pairs)-1] += "," + part
+			continue
+		}
+		pairs = append(pairs, part)
+	}
 	for _, pair := range pairs {
 		parts := strings.SplitN(pair, ":", 2)
 		if len(parts) == 2 {
@@ -556,7 +684,7 @@ func getAuthUsers() map[string]string {
 	return users
 }
 
-// 从环境变量获取Token有效期（小时），如果未设置则使用默认值
+// 从环境变量获取登录会话（刷新令牌）有效期（小时），如果未设置则使用默认值
 func getAuthTokenExpiry() time.Duration {
 	expiryEnv := os.Getenv("AUTH_TOKEN_EXPIRY")
 	if expiryEnv == "" {
@@ -569,29 +697,399 @@ func getAuthTokenExpiry() time.Duration {
 	return time.Duration(expiry) * time.Hour
 }
 
-// 从环境变量获取JWT密钥，如果未设置则生成随机密钥
+// 从环境变量获取访问令牌有效期（分钟），如果未设置则使用默认值
+func getAuthAccessTokenExpiry() time.Duration {
+	expiryEnv := os.Getenv("AUTH_ACCESS_TOKEN_EXPIRY")
+	if expiryEnv == "" {
+		return 15 * time.Minute // 默认15分钟
+	}
+	expiry, err := strconv.Atoi(expiryEnv)
+	if err != nil || expiry <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(expiry) * time.Minute
+}
+
+// 从环境变量获取JWT密钥，未设置时返回空，由loadOrCreateJWTSecret生成
 func getAuthJWTSecret() string {
-	secret := os.Getenv("AUTH_JWT_SECRET")
-	if secret == "" {
-		// 生成随机密钥（32字节）
-		import_crypto := "crypto/rand"
-		import_encoding := "encoding/base64"
-		_ = import_crypto
-		_ = import_encoding
-		// 注意：实际使用时应该使用crypto/rand生成随机密钥
-		// 这里为了简化，使用时间戳作为临时密钥
-		secret = "pansou-default-secret-" + strconv.FormatInt(time.Now().Unix(), 10)
+	return os.Getenv("AUTH_JWT_SECRET")
+}
+
+// 从环境变量获取用户角色，格式：user1:admin,user2:searcher，角色无效的条目被忽略
+func getAuthUserRoles() map[string]string {
+	rolesEnv := os.Getenv("AUTH_USER_ROLES")
+	if rolesEnv == "" {
+		return nil
+	}
+
+	roles := make(map[string]string)
+	for _, pair := range strings.Split(rolesEnv, ",") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		username := strings.TrimSpace(parts[0])
+		role := strings.ToLower(strings.TrimSpace(parts[1]))
+		if username != "" && model.RoleLevel(role) > 0 {
+			roles[username] = role
+		}
+	}
+	return roles
+}
+
+// 从环境变量获取默认角色，未设置或无效时为admin，与引入角色之前所有用户拥有全部权限的行为一致
+func getAuthDefaultRole() string {
+	role := strings.ToLower(strings.TrimSpace(os.Getenv("AUTH_DEFAULT_ROLE")))
+	if model.RoleLevel(role) == 0 {
+		return model.RoleAdmin
+	}
+	return role
+}
+
+// 从环境变量获取公开路径前缀列表，如果未设置则使用默认值
+func getAuthPublicPaths() []string {
+	pathsEnv := os.Getenv("AUTH_PUBLIC_PATHS")
+	if pathsEnv == "" {
+		return []string{"/api/auth/login", "/api/auth/logout", "/api/auth/refresh", "/api/health"}
+	}
+
+	var paths []string
+	for _, path := range strings.Split(pathsEnv, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// loadOrCreateJWTSecret 读取缓存目录中的JWT密钥，不存在时用crypto/rand生成32字节密钥并保存
+func loadOrCreateJWTSecret(cachePath string) string {
+	path := filepath.Join(cachePath, "jwt_secret")
+	if data, err := os.ReadFile(path); err == nil {
+		if secret := strings.TrimSpace(string(data)); secret != "" {
+			return secret
+		}
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		fmt.Printf("[认证] 生成JWT密钥失败: %v\n", err)
+		return ""
+	}
+	secret := base64.RawURLEncoding.EncodeToString(buf)
+
+	if err := os.MkdirAll(cachePath, 0o755); err == nil {
+		err = os.WriteFile(path, []byte(secret), 0o600)
+		if err != nil {
+			fmt.Printf("[认证] 保存JWT密钥失败，重启后已签发的令牌将失效: %v\n", err)
+		}
 	}
 	return secret
 }
 
+// 从环境变量获取链接检测并发数，如果未设置则使用默认值
+func getCheckConcurrency() int {
+	concurrencyEnv := os.Getenv("CHECK_CONCURRENCY")
+	if concurrencyEnv == "" {
+		return 8 // 默认8
+	}
+	concurrency, err := strconv.Atoi(concurrencyEnv)
+	if err != nil || concurrency <= 0 {
+		return 8
+	}
+	return concurrency
+}
+
+// 从环境变量获取批量链接检测总时限（秒），如果未设置则使用默认值
+func getCheckTimeout() time.Duration {
+	timeoutEnv := os.Getenv("CHECK_TIMEOUT")
+	if timeoutEnv == "" {
+		return 15 * time.Second // 默认15秒
+	}
+	timeout, err := strconv.Atoi(timeoutEnv)
+	if err != nil || timeout <= 0 {
+		return 15 * time.Second
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// 从环境变量获取每种网盘每秒检测数，如果未设置则使用默认值
+func getCheckRatePerSecond() float64 {
+	rateEnv := os.Getenv("CHECK_RATE_LIMIT")
+	if rateEnv == "" {
+		return 3 // 默认每秒3次
+	}
+	rate, err := strconv.ParseFloat(rateEnv, 64)
+	if err != nil || rate <= 0 {
+		return 3
+	}
+	return rate
+}
+
+// 从环境变量获取每种网盘允许的突发检测数，如果未设置则使用默认值
+func getCheckRateBurst() int {
+	burstEnv := os.Getenv("CHECK_RATE_BURST")
+	if burstEnv == "" {
+		return 5 // 默认5
+	}
+	burst, err := strconv.Atoi(burstEnv)
+	if err != nil || burst <= 0 {
+		return 5
+	}
+	return burst
+}
+
+// 从环境变量获取检测缓存复检间隔（分钟），设置为0时禁用复检
+func getCheckSweepInterval() time.Duration {
+	intervalEnv := os.Getenv("CHECK_SWEEP_INTERVAL")
+	if intervalEnv == "" {
+		return 10 * time.Minute // 默认10分钟
+	}
+	interval, err := strconv.Atoi(intervalEnv)
+	if err != nil || interval < 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(interval) * time.Minute
+}
+
+// 从环境变量获取复检窗口（分钟），如果未设置则使用默认值
+func getCheckSweepWindow() time.Duration {
+	windowEnv := os.Getenv("CHECK_SWEEP_WINDOW")
+	if windowEnv == "" {
+		return 120 * time.Minute // 默认2小时
+	}
+	window, err := strconv.Atoi(windowEnv)
+	if err != nil || window <= 0 {
+		return 120 * time.Minute
+	}
+	return time.Duration(window) * time.Minute
+}
+
+// 从环境变量获取每轮最多复检的链接数，如果未设置则使用默认值
+func getCheckSweepBatch() int {
+	batchEnv := os.Getenv("CHECK_SWEEP_BATCH")
+	if batchEnv == "" {
+		return 200 // 默认200
+	}
+	batch, err := strconv.Atoi(batchEnv)
+	if err != nil || batch <= 0 {
+		return 200
+	}
+	return batch
+}
+
+// 从环境变量获取搜索时是否默认检测链接有效性
+func getSearchCheckEnabled() bool {
+	enabled := os.Getenv("SEARCH_CHECK")
+	return enabled == "true" || enabled == "1"
+}
+
+// 从环境变量获取搜索时每种网盘类型检测的链接数，如果未设置则使用默认值
+func getSearchCheckTopN() int {
+	topNEnv := os.Getenv("SEARCH_CHECK_TOP_N")
+	if topNEnv == "" {
+		return 5 // 默认5
+	}
+	topN, err := strconv.Atoi(topNEnv)
+	if err != nil || topN <= 0 {
+		return 5
+	}
+	return topN
+}
+
+// 从环境变量获取搜索中链接检测的时间预算（毫秒），如果未设置则使用默认值
+func getSearchCheckTimeout() time.Duration {
+	timeoutEnv := os.Getenv("SEARCH_CHECK_TIMEOUT")
+	if timeoutEnv == "" {
+		return 3000 * time.Millisecond // 默认3秒
+	}
+	timeout, err := strconv.Atoi(timeoutEnv)
+	if err != nil || timeout <= 0 {
+		return 3000 * time.Millisecond
+	}
+	return time.Duration(timeout) * time.Millisecond
+}
+
+// 从环境变量获取是否移除已知失效的链接，默认移除
+func getSearchCheckDropBad() bool {
+	dropEnv := os.Getenv("SEARCH_CHECK_DROP_BAD")
+	return dropEnv != "false" && dropEnv != "0"
+}
+
+// 从环境变量获取每个TG频道最多抓取的搜索结果页数，如果未设置则使用默认值
+func getTGSearchMaxPages() int {
+	pagesEnv := os.Getenv("TG_SEARCH_MAX_PAGES")
+	if pagesEnv == "" {
+		return 1 // 默认只抓取第一页，与未分页时的请求量相同
+	}
+	pages, err := strconv.Atoi(pagesEnv)
+	if err != nil || pages <= 0 {
+		return 1
+	}
+	return pages
+}
+
+// 从环境变量获取每个TG频道抓取全部页的时间预算（毫秒），如果未设置则使用默认值
+func getTGSearchTimeout() time.Duration {
+	timeoutEnv := os.Getenv("TG_SEARCH_TIMEOUT")
+	if timeoutEnv == "" {
+		return 4000 * time.Millisecond // 默认4秒，与单页请求的超时相同
+	}
+	timeout, err := strconv.Atoi(timeoutEnv)
+	if err != nil || timeout <= 0 {
+		return 4000 * time.Millisecond
+	}
+	return time.Duration(timeout) * time.Millisecond
+}
+
+// 从环境变量获取插件熔断的连续失败阈值，如果未设置则使用默认值
+func getPluginBreakerThreshold() int {
+	thresholdEnv := os.Getenv("PLUGIN_BREAKER_THRESHOLD")
+	if thresholdEnv == "" {
+		return 5 // 默认连续失败5次后熔断
+	}
+	threshold, err := strconv.Atoi(thresholdEnv)
+	if err != nil || threshold < 0 {
+		return 5
+	}
+	return threshold
+}
+
+// 从环境变量获取插件熔断的冷却时间（秒），如果未设置则使用默认值
+func getPluginBreakerCooldown() time.Duration {
+	cooldownEnv := os.Getenv("PLUGIN_BREAKER_COOLDOWN")
+	if cooldownEnv == "" {
+		return 60 * time.Second // 默认60秒
+	}
+	cooldown, err := strconv.Atoi(cooldownEnv)
+	if err != nil || cooldown <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(cooldown) * time.Second
+}
+
+// 从环境变量获取插件探测间隔（分钟），如果未设置则不探测
+// 探测会对上游站点发起真实搜索，需要显式开启
+func getPluginProbeInterval() time.Duration {
+	intervalEnv := os.Getenv("PLUGIN_PROBE_INTERVAL")
+	if intervalEnv == "" {
+		return 0 // 默认不探测
+	}
+	interval, err := strconv.Atoi(intervalEnv)
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return time.Duration(interval) * time.Minute
+}
+
+// 从环境变量获取插件探测关键词，如果未设置则使用默认值
+func getPluginProbeKeyword() string {
+	keyword := strings.TrimSpace(os.Getenv("PLUGIN_PROBE_KEYWORD"))
+	if keyword == "" {
+		return "速度与激情"
+	}
+	return keyword
+}
+
+// 从环境变量获取每个插件保留的探测记录数，如果未设置则使用默认值
+func getPluginProbeHistory() int {
+	historyEnv := os.Getenv("PLUGIN_PROBE_HISTORY")
+	if historyEnv == "" {
+		return 20 // 默认保留20次
+	}
+	history, err := strconv.Atoi(historyEnv)
+	if err != nil || history <= 0 {
+		return 20
+	}
+	return history
+}
+
+// 从环境变量获取客户端限流开关，如果未设置则默认关闭
+func getRateLimitEnabled() bool {
+	enabled := os.Getenv("RATE_LIMIT_ENABLED")
+	return enabled == "true" || enabled == "1"
+}
+
+// 从环境变量获取每个客户端每分钟的请求数，如果未设置则使用默认值
+func getRateLimitPerMinute() int {
+	return getRateLimitEnv("RATE_LIMIT_PER_MINUTE", 120) // 默认每分钟120次
+}
+
+// 从环境变量获取每个客户端允许的突发请求数，如果未设置则使用默认值
+func getRateLimitBurst() int {
+	return getRateLimitEnv("RATE_LIMIT_BURST", 30)
+}
+
+// 从环境变量获取每个客户端每分钟的强制刷新搜索数，如果未设置则使用默认值
+func getRateLimitRefreshPerMinute() int {
+	return getRateLimitEnv("RATE_LIMIT_REFRESH_PER_MINUTE", 6) // 强制刷新会请求全部插件和频道，默认每分钟6次
+}
+
+// 从环境变量获取每个客户端允许的突发强制刷新搜索数，如果未设置则使用默认值
+func getRateLimitRefreshBurst() int {
+	return getRateLimitEnv("RATE_LIMIT_REFRESH_BURST", 2)
+}
+
+// 从环境变量获取每个客户端每分钟的链接检测请求数，如果未设置则使用默认值
+func getRateLimitCheckPerMinute() int {
+	return getRateLimitEnv("RATE_LIMIT_CHECK_PER_MINUTE", 20) // 默认每分钟20次
+}
+
+// 从环境变量获取每个客户端允许的突发链接检测请求数，如果未设置则使用默认值
+func getRateLimitCheckBurst() int {
+	return getRateLimitEnv("RATE_LIMIT_CHECK_BURST", 5)
+}
+
+// 从环境变量获取可信反向代理列表，如果未设置则不信任任何代理，客户端IP取连接的对端地址
+func getTrustedProxies() []string {
+	proxiesEnv := os.Getenv("TRUSTED_PROXIES")
+	if proxiesEnv == "" {
+		return nil
+	}
+
+	var proxies []string
+	for _, proxy := range strings.Split(proxiesEnv, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// 从环境变量获取默认过滤配置（JSON格式，与搜索请求的filter参数相同），未设置或无效时不过滤
+func getDefaultFilter() *model.FilterConfig {
+	filterEnv := os.Getenv("DEFAULT_FILTER")
+	if filterEnv == "" {
+		return nil
+	}
+	var filter model.FilterConfig
+	if err := json.Unmarshal([]byte(filterEnv), &filter); err != nil {
+		fmt.Printf("[配置] DEFAULT_FILTER解析失败，已忽略: %v\n", err)
+		return nil
+	}
+	return &filter
+}
+
+// getRateLimitEnv 读取正整数形式的限流配置，未设置或无效时使用默认值
+func getRateLimitEnv(name string, defaultValue int) int {
+	valueEnv := os.Getenv(name)
+	if valueEnv == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueEnv)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
 // 应用GC设置
 func applyGCSettings() {
 	// 设置GC百分比
-	debug.SetGCPercent(AppConfig.GCPercent)
+	debug.SetGCPercent(Get().GCPercent)
 	
 	// 如果启用内存优化
-	if AppConfig.OptimizeMemory {
+	if Get().OptimizeMemory {
 		// 释放操作系统内存
 		debug.FreeOSMemory()
 	}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// 插件探测会请求上游站点，未设置时应默认关闭
+func TestGetPluginProbeInterval(t *testing.T) {
+	tests := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"15", 15 * time.Minute},
+		{"-1", 0},
+		{"abc", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("PLUGIN_PROBE_INTERVAL", tt.env)
+			if got := getPluginProbeInterval(); got != tt.want {
+				t.Errorf("getPluginProbeInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
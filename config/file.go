@@ -0,0 +1,473 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 配置文件为YAML格式，顶层键与环境变量同名（大小写均可，如channels或CHANNELS），
+// 列表按逗号拼接，映射按key:value拼接（DEFAULT_FILTER按JSON编码）；plugins段为各插件的设置。
+// 配置文件中的值以环境变量的形式提供给Init和直接读取环境变量的模块，进程启动时已存在的环境变量优先。
+
+// reloadableFields 可以热加载的配置字段，其余字段修改后需要重启生效
+var reloadableFields = []string{
+	"DefaultChannels",
+	"EnabledPlugins",
+	"PluginTimeoutSeconds",
+	"PluginTimeout",
+	"AsyncResponseTimeout",
+	"AsyncResponseTimeoutDur",
+	"AuthUsers",
+	"AuthTokenExpiry",
+	"AuthAccessTokenExpiry",
+	"AuthUserRoles",
+	"AuthDefaultRole",
+	"AuthPublicPaths",
+	"SearchCheckEnabled",
+	"SearchCheckTopN",
+	"SearchCheckTimeout",
+	"SearchCheckDropBad",
+	"TGSearchMaxPages",
+	"TGSearchTimeout",
+	"PluginBreakerThreshold",
+	"PluginBreakerCooldown",
+	"Plugins",
+	"DefaultFilter",
+}
+
+// 插件段中由搜索服务处理的键，其余键以<插件名>_<键>的环境变量提供给插件
+const (
+	pluginKeyEnabled   = "enabled"
+	pluginKeyPriority  = "priority"
+	pluginKeyTimeoutMs = "timeout_ms"
+)
+
+// configFileWatchInterval 检查配置文件是否变化的间隔
+const configFileWatchInterval = 5 * time.Second
+
+// PluginConfig 配置文件中单个插件的设置，零值表示使用插件默认值
+// 通过管理接口修改过的设置优先于配置文件
+type PluginConfig struct {
+	Priority  int   `json:"priority,omitempty"`   // 优先级等级（1-4）
+	TimeoutMs int64 `json:"timeout_ms,omitempty"` // 异步响应超时（毫秒）
+}
+
+// configFileContent 解析后的配置文件
+type configFileContent struct {
+	values  map[string]string // 环境变量名 → 值
+	plugins map[string]PluginConfig
+}
+
+var (
+	configFilePath    string
+	configFileModTime time.Time
+	configFileSize    int64
+	currentFile       configFileContent
+	fileEnv           = make(map[string]bool) // 由配置文件设置的环境变量
+	configLoadedAt    time.Time
+	reloadMu          sync.Mutex
+	reloadHooks       []func(old, cfg *Config)
+	validators        []func(cfg *Config) error
+)
+
+// LoadFile 读取配置文件，需要在Init之前调用
+func LoadFile(path string) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	configFilePath = path
+	configFileModTime, configFileSize = statConfigFile(path)
+	content, err := parseConfigFile(path)
+	if err != nil {
+		return err
+	}
+	applyConfigFile(content)
+	configLoadedAt = time.Now()
+	return nil
+}
+
+// ConfigFile 返回当前使用的配置文件路径，未使用配置文件时返回空
+func ConfigFile() string {
+	return configFilePath
+}
+
+// LoadedAt 返回配置最近一次加载的时间
+func LoadedAt() time.Time {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	return configLoadedAt
+}
+
+// ReloadableFields 返回可以热加载的配置字段
+func ReloadableFields() []string {
+	return append([]string(nil), reloadableFields...)
+}
+
+// OnReload 注册热加载后的回调，回调在新配置生效后执行
+func OnReload(hook func(old, cfg *Config)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reloadHooks = append(reloadHooks, hook)
+}
+
+// AddValidator 注册配置校验，启动和热加载时执行，热加载校验失败时保持当前配置
+func AddValidator(validate func(cfg *Config) error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	validators = append(validators, validate)
+}
+
+// Validate 校验当前配置
+func Validate() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	return validate(Get())
+}
+
+func validate(cfg *Config) error {
+	for _, v := range validators {
+		if err := v(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload 重新读取配置文件并热加载配置
+// 新的配置对象只替换可热加载的字段，其余字段沿用当前值；配置整体原子替换，不修改正在被读取的旧对象
+func Reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if configFilePath == "" {
+		return errors.New("未使用配置文件")
+	}
+
+	configFileModTime, configFileSize = statConfigFile(configFilePath)
+	content, err := parseConfigFile(configFilePath)
+	if err != nil {
+		return err
+	}
+
+	previous := currentFile
+	applyConfigFile(content)
+
+	old := Get()
+	next := *old
+	loaded := load()
+	nextValue := reflect.ValueOf(&next).Elem()
+	loadedValue := reflect.ValueOf(loaded).Elem()
+	for _, name := range reloadableFields {
+		nextValue.FieldByName(name).Set(loadedValue.FieldByName(name))
+	}
+	if next.AuthAccessTokenExpiry > next.AuthTokenExpiry {
+		next.AuthAccessTokenExpiry = next.AuthTokenExpiry
+	}
+
+	if err := validate(&next); err != nil {
+		applyConfigFile(previous)
+		return err
+	}
+
+	Set(&next)
+	configLoadedAt = time.Now()
+	for _, hook := range reloadHooks {
+		hook(old, &next)
+	}
+	return nil
+}
+
+// Watch 收到SIGHUP或配置文件变化时热加载配置，未使用配置文件时不做任何操作
+func Watch() {
+	if configFilePath == "" {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		ticker := time.NewTicker(configFileWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-hup:
+				reloadAndLog("SIGHUP")
+			case <-ticker.C:
+				if configFileChanged() {
+					reloadAndLog("配置文件变化")
+				}
+			}
+		}
+	}()
+}
+
+func reloadAndLog(reason string) {
+	if err := Reload(); err != nil {
+		fmt.Printf("[配置] 热加载失败（%s），保持当前配置: %v\n", reason, err)
+		return
+	}
+	fmt.Printf("[配置] 已热加载配置（%s）: %s\n", reason, configFilePath)
+}
+
+func configFileChanged() bool {
+	modTime, size := statConfigFile(configFilePath)
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	return !modTime.Equal(configFileModTime) || size != configFileSize
+}
+
+func statConfigFile(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, -1
+	}
+	return info.ModTime(), info.Size()
+}
+
+// applyConfigFile 将配置文件的值设置为环境变量，调用方需持有reloadMu
+// 进程启动时已存在的环境变量不被覆盖；从配置文件中删除的项同时删除对应的环境变量
+func applyConfigFile(content configFileContent) {
+	owned := make(map[string]bool, len(content.values))
+	for name, value := range content.values {
+		if _, exists := os.LookupEnv(name); exists && !fileEnv[name] {
+			continue
+		}
+		_ = os.Setenv(name, value)
+		owned[name] = true
+	}
+	for name := range fileEnv {
+		if !owned[name] {
+			_ = os.Unsetenv(name)
+		}
+	}
+	fileEnv = owned
+	currentFile = content
+}
+
+// getFilePlugins 返回配置文件中的插件设置
+func getFilePlugins() map[string]PluginConfig {
+	if len(currentFile.plugins) == 0 {
+		return nil
+	}
+	plugins := make(map[string]PluginConfig, len(currentFile.plugins))
+	for name, plugin := range currentFile.plugins {
+		plugins[name] = plugin
+	}
+	return plugins
+}
+
+// parseConfigFile 解析YAML配置文件
+func parseConfigFile(path string) (configFileContent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return configFileContent{}, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return configFileContent{}, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	content := configFileContent{
+		values:  make(map[string]string),
+		plugins: make(map[string]PluginConfig),
+	}
+	var pluginsSection interface{}
+	for key, value := range raw {
+		name := envName(key)
+		if name == "PLUGINS" {
+			pluginsSection = value
+			continue
+		}
+		str, err := fileValueString(name, value)
+		if err != nil {
+			return configFileContent{}, err
+		}
+		content.values[name] = str
+	}
+
+	if pluginsSection != nil {
+		if err := parsePluginSections(pluginsSection, &content); err != nil {
+			return configFileContent{}, err
+		}
+	}
+	return content, nil
+}
+
+// parsePluginSections 解析plugins段
+// enabled合并到ENABLED_PLUGINS，priority和timeout_ms作为插件设置，其余键转换为<插件名>_<键>的环境变量
+func parsePluginSections(section interface{}, content *configFileContent) error {
+	sections, ok := section.(map[string]interface{})
+	if !ok {
+		return errors.New("plugins必须是以插件名为键的映射")
+	}
+
+	enabled := make(map[string]bool)
+	for name, value := range sections {
+		name = strings.ToLower(strings.TrimSpace(name))
+		options, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("plugins.%s必须是映射", name)
+		}
+
+		var plugin PluginConfig
+		for key, option := range options {
+			switch strings.ToLower(key) {
+			case pluginKeyEnabled:
+				b, ok := option.(bool)
+				if !ok {
+					return fmt.Errorf("plugins.%s.enabled必须是布尔值", name)
+				}
+				enabled[name] = b
+			case pluginKeyPriority:
+				n, ok := option.(int)
+				if !ok || n < 0 || n > 4 {
+					return fmt.Errorf("plugins.%s.priority必须为1-4", name)
+				}
+				plugin.Priority = n
+			case pluginKeyTimeoutMs:
+				n, ok := option.(int)
+				if !ok || n < 0 {
+					return fmt.Errorf("plugins.%s.timeout_ms必须是非负整数", name)
+				}
+				plugin.TimeoutMs = int64(n)
+			default:
+				envKey := envName(name + "_" + key)
+				str, err := fileValueString(envKey, option)
+				if err != nil {
+					return err
+				}
+				content.values[envKey] = str
+			}
+		}
+		if plugin != (PluginConfig{}) {
+			content.plugins[name] = plugin
+		}
+	}
+
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	// 在ENABLED_PLUGINS的基础上添加或移除插件
+	var list []string
+	if existing := content.values["ENABLED_PLUGINS"]; existing != "" {
+		for _, name := range strings.Split(existing, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				if b, set := enabled[name]; !set || b {
+					list = append(list, name)
+				}
+				delete(enabled, name)
+			}
+		}
+	}
+	added := make([]string, 0, len(enabled))
+	for name, b := range enabled {
+		if b {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	content.values["ENABLED_PLUGINS"] = strings.Join(append(list, added...), ",")
+	return nil
+}
+
+// envName 将配置文件的键转换为环境变量名
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(key), "-", "_"))
+}
+
+// fileValueString 将配置文件的值转换为环境变量格式
+func fileValueString(name string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			str, err := fileValueString(name, item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, str)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		if name == "DEFAULT_FILTER" {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", name, err)
+			}
+			return string(data), nil
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			str, err := fileValueString(name, v[key])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, key+":"+str)
+		}
+		return strings.Join(pairs, ","), nil
+	default:
+		return "", fmt.Errorf("配置项%s的值类型不受支持", name)
+	}
+}
+
+// Redacted 返回当前生效配置的副本，密码、密钥和代理认证信息已脱敏，时长以字符串表示
+func Redacted() map[string]interface{} {
+	cfg := Get()
+	value := reflect.ValueOf(cfg).Elem()
+	result := make(map[string]interface{}, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		v := value.Field(i).Interface()
+		if d, ok := v.(time.Duration); ok {
+			v = d.String()
+		}
+		result[field.Name] = v
+	}
+
+	if cfg.AuthJWTSecret != "" {
+		result["AuthJWTSecret"] = redactedValue
+	}
+	users := make(map[string]string, len(cfg.AuthUsers))
+	for username := range cfg.AuthUsers {
+		users[username] = redactedValue
+	}
+	result["AuthUsers"] = users
+	for _, name := range []string{"ProxyURL", "HTTPProxyURL", "HTTPSProxyURL"} {
+		if raw, _ := result[name].(string); raw != "" {
+			if u, err := url.Parse(raw); err == nil {
+				result[name] = u.Redacted()
+			} else {
+				result[name] = redactedValue
+			}
+		}
+	}
+	return result
+}
+
+const redactedValue = "******"
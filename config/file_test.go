@@ -0,0 +1,280 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useConfigFile 写入配置文件并在测试结束时恢复包级状态和配置文件设置的环境变量
+func useConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	t.Setenv("CACHE_PATH", t.TempDir())
+
+	path := filepath.Join(t.TempDir(), "pansou.yaml")
+	writeConfigFile(t, path, content)
+
+	previous := Get()
+	t.Cleanup(func() {
+		reloadMu.Lock()
+		applyConfigFile(configFileContent{})
+		configFilePath = ""
+		reloadHooks = nil
+		validators = nil
+		reloadMu.Unlock()
+		Set(previous)
+	})
+	return path
+}
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+}
+
+func TestParseConfigFile(t *testing.T) {
+	path := useConfigFile(t, `
+channels: [tgsearchers6, yunpanx]
+concurrency: 20
+cache-enabled: false
+AUTH_USERS:
+  admin: secret
+default_filter:
+  exclude: [预告]
+plugins:
+  gying:
+    priority: 2
+    timeout_ms: 3000
+    username: user
+`)
+
+	content, err := parseConfigFile(path)
+	if err != nil {
+		t.Fatalf("parseConfigFile() error = %v", err)
+	}
+
+	want := map[string]string{
+		"CHANNELS":       "tgsearchers6,yunpanx",
+		"CONCURRENCY":    "20",
+		"CACHE_ENABLED":  "false",
+		"AUTH_USERS":     "admin:secret",
+		"DEFAULT_FILTER": `{"exclude":["预告"]}`,
+		"GYING_USERNAME": "user",
+	}
+	if !reflect.DeepEqual(content.values, want) {
+		t.Errorf("values = %v, want %v", content.values, want)
+	}
+	wantPlugins := map[string]PluginConfig{"gying": {Priority: 2, TimeoutMs: 3000}}
+	if !reflect.DeepEqual(content.plugins, wantPlugins) {
+		t.Errorf("plugins = %v, want %v", content.plugins, wantPlugins)
+	}
+}
+
+func TestParseConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid yaml", "channels: [a"},
+		{"plugins not a map", "plugins: [a, b]"},
+		{"plugin not a map", "plugins:\n  gying: true"},
+		{"enabled not bool", "plugins:\n  gying:\n    enabled: yes please"},
+		{"priority out of range", "plugins:\n  gying:\n    priority: 5"},
+		{"negative timeout", "plugins:\n  gying:\n    timeout_ms: -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pansou.yaml")
+			writeConfigFile(t, path, tt.content)
+			if _, err := parseConfigFile(path); err == nil {
+				t.Errorf("parseConfigFile(%q) error = nil, want error", tt.content)
+			}
+		})
+	}
+
+	if _, err := parseConfigFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("parseConfigFile(missing) error = nil, want error")
+	}
+}
+
+func TestParsePluginSectionsEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		sections map[string]interface{}
+		want     string
+	}{
+		{
+			name:     "adds enabled plugins sorted",
+			sections: map[string]interface{}{"susu": map[string]interface{}{"enabled": true}, "labi": map[string]interface{}{"enabled": true}},
+			want:     "labi,susu",
+		},
+		{
+			name:     "appends to existing list",
+			existing: "pansearch, hdr4k",
+			sections: map[string]interface{}{"labi": map[string]interface{}{"enabled": true}},
+			want:     "pansearch,hdr4k,labi",
+		},
+		{
+			name:     "removes disabled plugins",
+			existing: "pansearch,hdr4k",
+			sections: map[string]interface{}{"hdr4k": map[string]interface{}{"enabled": false}},
+			want:     "pansearch",
+		},
+		{
+			name:     "enabled plugin already listed",
+			existing: "pansearch",
+			sections: map[string]interface{}{"PanSearch": map[string]interface{}{"enabled": true}},
+			want:     "pansearch",
+		},
+		{
+			name:     "disabled plugin not listed",
+			sections: map[string]interface{}{"labi": map[string]interface{}{"enabled": false}},
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := configFileContent{values: map[string]string{}, plugins: map[string]PluginConfig{}}
+			if tt.existing != "" {
+				content.values["ENABLED_PLUGINS"] = tt.existing
+			}
+			if err := parsePluginSections(tt.sections, &content); err != nil {
+				t.Fatalf("parsePluginSections() error = %v", err)
+			}
+			if got := content.values["ENABLED_PLUGINS"]; got != tt.want {
+				t.Errorf("ENABLED_PLUGINS = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	content := configFileContent{values: map[string]string{}, plugins: map[string]PluginConfig{}}
+	sections := map[string]interface{}{"labi": map[string]interface{}{"priority": 1}}
+	if err := parsePluginSections(sections, &content); err != nil {
+		t.Fatalf("parsePluginSections() error = %v", err)
+	}
+	if _, ok := content.values["ENABLED_PLUGINS"]; ok {
+		t.Error("未设置enabled时不应修改ENABLED_PLUGINS")
+	}
+}
+
+func TestEnvOverridesConfigFile(t *testing.T) {
+	t.Setenv("CHANNELS", "fromenv")
+	path := useConfigFile(t, "channels: [fromfile]\nconcurrency: 33\n")
+
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	Init()
+
+	cfg := Get()
+	if !reflect.DeepEqual(cfg.DefaultChannels, []string{"fromenv"}) {
+		t.Errorf("DefaultChannels = %v, want [fromenv]", cfg.DefaultChannels)
+	}
+	if cfg.DefaultConcurrency != 33 {
+		t.Errorf("DefaultConcurrency = %d, want 33", cfg.DefaultConcurrency)
+	}
+
+	// 热加载时环境变量同样优先
+	writeConfigFile(t, path, "channels: [changed]\nconcurrency: 33\n")
+	if err := Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := Get().DefaultChannels; !reflect.DeepEqual(got, []string{"fromenv"}) {
+		t.Errorf("reloaded DefaultChannels = %v, want [fromenv]", got)
+	}
+	if got := os.Getenv("CHANNELS"); got != "fromenv" {
+		t.Errorf("CHANNELS = %q, want fromenv", got)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := useConfigFile(t, "channels: [first]\nplugin_timeout: 10\n")
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	Init()
+	old := Get()
+
+	var hookOld, hookNew *Config
+	OnReload(func(o, cfg *Config) { hookOld, hookNew = o, cfg })
+
+	writeConfigFile(t, path, "channels: [second, third]\nplugin_timeout: 20\ncache_max_size: 1\n")
+	if err := Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	cfg := Get()
+	if cfg == old {
+		t.Fatal("Reload() 应替换配置对象")
+	}
+	if !reflect.DeepEqual(cfg.DefaultChannels, []string{"second", "third"}) {
+		t.Errorf("DefaultChannels = %v, want [second third]", cfg.DefaultChannels)
+	}
+	if cfg.PluginTimeoutSeconds != 20 {
+		t.Errorf("PluginTimeoutSeconds = %d, want 20", cfg.PluginTimeoutSeconds)
+	}
+	// 不可热加载的字段保持不变
+	if cfg.CacheMaxSizeMB != old.CacheMaxSizeMB {
+		t.Errorf("CacheMaxSizeMB = %d, want %d", cfg.CacheMaxSizeMB, old.CacheMaxSizeMB)
+	}
+	// 旧配置对象不被修改
+	if !reflect.DeepEqual(old.DefaultChannels, []string{"first"}) {
+		t.Errorf("old DefaultChannels = %v, want [first]", old.DefaultChannels)
+	}
+	if hookOld != old || hookNew != cfg {
+		t.Error("OnReload回调未收到新旧配置")
+	}
+}
+
+func TestReloadRollsBackOnValidationFailure(t *testing.T) {
+	path := useConfigFile(t, "channels: [good]\n")
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	Init()
+	old := Get()
+
+	errBad := errors.New("bad channel")
+	AddValidator(func(cfg *Config) error {
+		for _, channel := range cfg.DefaultChannels {
+			if strings.Contains(channel, "bad") {
+				return errBad
+			}
+		}
+		return nil
+	})
+	hookCalled := false
+	OnReload(func(_, _ *Config) { hookCalled = true })
+
+	writeConfigFile(t, path, "channels: [bad]\nconcurrency: 7\n")
+	if err := Reload(); !errors.Is(err, errBad) {
+		t.Fatalf("Reload() error = %v, want %v", err, errBad)
+	}
+
+	if Get() != old {
+		t.Error("校验失败时应保持当前配置")
+	}
+	if got := os.Getenv("CHANNELS"); got != "good" {
+		t.Errorf("CHANNELS = %q, want good", got)
+	}
+	if _, exists := os.LookupEnv("CONCURRENCY"); exists {
+		t.Error("校验失败时应移除新配置文件设置的环境变量")
+	}
+	if hookCalled {
+		t.Error("校验失败时不应执行OnReload回调")
+	}
+
+	writeConfigFile(t, path, "- not a map\n")
+	if err := Reload(); err == nil {
+		t.Error("Reload() 解析失败时应返回错误")
+	}
+	if Get() != old {
+		t.Error("解析失败时应保持当前配置")
+	}
+}
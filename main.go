@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
@@ -121,6 +122,26 @@ import (
 var globalCacheWriteManager *cache.DelayedBatchWriteManager
 
 func main() {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML配置文件路径，环境变量优先于配置文件")
+	flag.Parse()
+
+	// 生成AUTH_USERS使用的密码哈希：pansou hash-password <密码>
+	if flag.NArg() == 2 && flag.Arg(0) == "hash-password" {
+		hash, err := util.HashPassword(flag.Arg(1))
+		if err != nil {
+			log.Fatalf("生成密码哈希失败: %v", err)
+		}
+		fmt.Println(hash)
+		return
+	}
+
+	// 读取配置文件
+	if *configFile != "" {
+		if err := config.LoadFile(*configFile); err != nil {
+			log.Fatalf("加载配置文件失败: %v", err)
+		}
+	}
+
 	// 初始化应用
 	initApp()
 
@@ -132,6 +153,9 @@ func main() {
 func initApp() {
 	// 初始化配置
 	config.Init()
+	if err := config.Validate(); err != nil {
+		log.Fatalf("配置无效: %v", err)
+	}
 
 	// 初始化HTTP客户端
 	util.InitHTTPClient()
@@ -169,13 +193,13 @@ func startServer() {
 	pluginManager := plugin.NewPluginManager()
 
 	// 注册全局插件（根据配置过滤）
-	if config.AppConfig.AsyncPluginEnabled {
-		pluginManager.RegisterGlobalPluginsWithFilter(config.AppConfig.EnabledPlugins)
+	if config.Get().AsyncPluginEnabled {
+		pluginManager.RegisterGlobalPluginsWithFilter(config.Get().EnabledPlugins)
 	}
 
 	// 更新默认并发数（如果插件被禁用则使用0）
 	pluginCount := 0
-	if config.AppConfig.AsyncPluginEnabled {
+	if config.Get().AsyncPluginEnabled {
 		pluginCount = len(pluginManager.GetPlugins())
 	}
 	config.UpdateDefaultConcurrency(pluginCount)
@@ -187,7 +211,10 @@ func startServer() {
 	router := api.SetupRouter(searchService)
 
 	// 获取端口配置
-	port := config.AppConfig.Port
+	port := config.Get().Port
+
+	// 收到SIGHUP或配置文件变化时热加载配置
+	config.Watch()
 
 	// 输出服务信息
 	printServiceInfo(port, pluginManager)
@@ -196,9 +223,9 @@ func startServer() {
 	srv := &http.Server{
 		Addr:         ":" + port,
 		Handler:      router,
-		ReadTimeout:  config.AppConfig.HTTPReadTimeout,
-		WriteTimeout: config.AppConfig.HTTPWriteTimeout,
-		IdleTimeout:  config.AppConfig.HTTPIdleTimeout,
+		ReadTimeout:  config.Get().HTTPReadTimeout,
+		WriteTimeout: config.Get().HTTPWriteTimeout,
+		IdleTimeout:  config.Get().HTTPIdleTimeout,
 	}
 
 	// 创建通道来接收操作系统信号
@@ -208,7 +235,7 @@ func startServer() {
 	// 在单独的goroutine中启动服务器
 	go func() {
 		// 如果设置了最大连接数，使用限制监听器
-		if config.AppConfig.HTTPMaxConns > 0 {
+		if config.Get().HTTPMaxConns > 0 {
 			// 创建监听器
 			listener, err := net.Listen("tcp", srv.Addr)
 			if err != nil {
@@ -216,7 +243,7 @@ func startServer() {
 			}
 
 			// 创建限制连接数的监听器
-			limitListener := netutil.LimitListener(listener, config.AppConfig.HTTPMaxConns)
+			limitListener := netutil.LimitListener(listener, config.Get().HTTPMaxConns)
 
 			// 使用限制监听器启动服务器
 			if err := srv.Serve(limitListener); err != nil && err != http.ErrServerClosed {
@@ -270,24 +297,24 @@ func printServiceInfo(port string, pluginManager *plugin.PluginManager) {
 
 	// 输出代理信息
 	hasProxy := false
-	if config.AppConfig.ProxyURL != "" {
+	if config.Get().ProxyURL != "" {
 		proxyType := "代理"
-		if strings.HasPrefix(config.AppConfig.ProxyURL, "socks5://") {
+		if strings.HasPrefix(config.Get().ProxyURL, "socks5://") {
 			proxyType = "SOCKS5代理"
-		} else if strings.HasPrefix(config.AppConfig.ProxyURL, "http://") {
+		} else if strings.HasPrefix(config.Get().ProxyURL, "http://") {
 			proxyType = "HTTP代理"
-		} else if strings.HasPrefix(config.AppConfig.ProxyURL, "https://") {
+		} else if strings.HasPrefix(config.Get().ProxyURL, "https://") {
 			proxyType = "HTTPS代理"
 		}
-		fmt.Printf("使用%s (PROXY): %s\n", proxyType, config.AppConfig.ProxyURL)
+		fmt.Printf("使用%s (PROXY): %s\n", proxyType, config.Get().ProxyURL)
 		hasProxy = true
 	}
-	if config.AppConfig.HTTPProxyURL != "" {
-		fmt.Printf("使用HTTP代理 (HTTP_PROXY/http_proxy): %s\n", config.AppConfig.HTTPProxyURL)
+	if config.Get().HTTPProxyURL != "" {
+		fmt.Printf("使用HTTP代理 (HTTP_PROXY/http_proxy): %s\n", config.Get().HTTPProxyURL)
 		hasProxy = true
 	}
-	if config.AppConfig.HTTPSProxyURL != "" {
-		fmt.Printf("使用HTTPS代理 (HTTPS_PROXY/https_proxy): %s\n", config.AppConfig.HTTPSProxyURL)
+	if config.Get().HTTPSProxyURL != "" {
+		fmt.Printf("使用HTTPS代理 (HTTPS_PROXY/https_proxy): %s\n", config.Get().HTTPSProxyURL)
 		hasProxy = true
 	}
 	if !hasProxy {
@@ -296,38 +323,38 @@ func printServiceInfo(port string, pluginManager *plugin.PluginManager) {
 
 	// 输出并发信息
 	if os.Getenv("CONCURRENCY") != "" {
-		fmt.Printf("默认并发数: %d (由环境变量CONCURRENCY指定)\n", config.AppConfig.DefaultConcurrency)
+		fmt.Printf("默认并发数: %d (由环境变量CONCURRENCY指定)\n", config.Get().DefaultConcurrency)
 	} else {
-		channelCount := len(config.AppConfig.DefaultChannels)
+		channelCount := len(config.Get().DefaultChannels)
 		pluginCount := 0
 		// 只有插件启用时才计算插件数
-		if config.AppConfig.AsyncPluginEnabled && pluginManager != nil {
+		if config.Get().AsyncPluginEnabled && pluginManager != nil {
 			pluginCount = len(pluginManager.GetPlugins())
 		}
 		fmt.Printf("默认并发数: %d (= 频道数%d + 插件数%d + 10)\n",
-			config.AppConfig.DefaultConcurrency, channelCount, pluginCount)
+			config.Get().DefaultConcurrency, channelCount, pluginCount)
 	}
 
 	// 输出缓存信息
-	if config.AppConfig.CacheEnabled {
+	if config.Get().CacheEnabled {
 		fmt.Printf("缓存已启用: 路径=%s, 最大大小=%dMB, TTL=%d分钟\n",
-			config.AppConfig.CachePath,
-			config.AppConfig.CacheMaxSizeMB,
-			config.AppConfig.CacheTTLMinutes)
+			config.Get().CachePath,
+			config.Get().CacheMaxSizeMB,
+			config.Get().CacheTTLMinutes)
 	} else {
 		fmt.Println("缓存已禁用")
 	}
 
 	// 输出压缩信息
-	if config.AppConfig.EnableCompression {
+	if config.Get().EnableCompression {
 		fmt.Printf("响应压缩已启用: 最小压缩大小=%d字节\n",
-			config.AppConfig.MinSizeToCompress)
+			config.Get().MinSizeToCompress)
 	}
 
 	// 输出GC配置信息
 	fmt.Printf("GC配置: 触发阈值=%d%%, 内存优化=%v\n",
-		config.AppConfig.GCPercent,
-		config.AppConfig.OptimizeMemory)
+		config.Get().GCPercent,
+		config.Get().OptimizeMemory)
 
 	// 输出HTTP服务器配置信息
 	readTimeoutMsg := ""
@@ -353,13 +380,13 @@ func printServiceInfo(port string, pluginManager *plugin.PluginManager) {
 	}
 
 	fmt.Printf("HTTP服务器配置: 读取超时=%v %s, 写入超时=%v %s, 空闲超时=%v, 最大连接数=%d %s\n",
-		config.AppConfig.HTTPReadTimeout, readTimeoutMsg,
-		config.AppConfig.HTTPWriteTimeout, writeTimeoutMsg,
-		config.AppConfig.HTTPIdleTimeout,
-		config.AppConfig.HTTPMaxConns, maxConnsMsg)
+		config.Get().HTTPReadTimeout, readTimeoutMsg,
+		config.Get().HTTPWriteTimeout, writeTimeoutMsg,
+		config.Get().HTTPIdleTimeout,
+		config.Get().HTTPMaxConns, maxConnsMsg)
 
 	// 输出异步插件配置信息
-	if config.AppConfig.AsyncPluginEnabled {
+	if config.Get().AsyncPluginEnabled {
 		// 检查工作者数量是否由环境变量指定
 		workersMsg := ""
 		if os.Getenv("ASYNC_MAX_BACKGROUND_WORKERS") != "" {
@@ -378,16 +405,16 @@ func printServiceInfo(port string, pluginManager *plugin.PluginManager) {
 		}
 
 		fmt.Printf("异步插件已启用: 响应超时=%d秒, 最大工作者=%d %s, 最大任务=%d %s, 缓存TTL=%d小时\n",
-			config.AppConfig.AsyncResponseTimeout,
-			config.AppConfig.AsyncMaxBackgroundWorkers, workersMsg,
-			config.AppConfig.AsyncMaxBackgroundTasks, tasksMsg,
-			config.AppConfig.AsyncCacheTTLHours)
+			config.Get().AsyncResponseTimeout,
+			config.Get().AsyncMaxBackgroundWorkers, workersMsg,
+			config.Get().AsyncMaxBackgroundTasks, tasksMsg,
+			config.Get().AsyncCacheTTLHours)
 	} else {
 		fmt.Println("异步插件已禁用")
 	}
 
 	// 只有当插件功能启用时才输出插件信息
-	if config.AppConfig.AsyncPluginEnabled {
+	if config.Get().AsyncPluginEnabled {
 		plugins := pluginManager.GetPlugins()
 		if len(plugins) > 0 {
 			// 根据新逻辑，只有指定了具体插件才会加载插件
@@ -396,21 +423,21 @@ func printServiceInfo(port string, pluginManager *plugin.PluginManager) {
 			// 按优先级排序（优先级数字越小越靠前）
 			sort.Slice(plugins, func(i, j int) bool {
 				// 优先级相同时按名称排序
-				if plugins[i].Priority() == plugins[j].Priority() {
+				if plugin.EffectivePriority(plugins[i]) == plugin.EffectivePriority(plugins[j]) {
 					return plugins[i].Name() < plugins[j].Name()
 				}
-				return plugins[i].Priority() < plugins[j].Priority()
+				return plugin.EffectivePriority(plugins[i]) < plugin.EffectivePriority(plugins[j])
 			})
 
 			for _, p := range plugins {
-				fmt.Printf("  - %s (优先级: %d)\n", p.Name(), p.Priority())
+				fmt.Printf("  - %s (优先级: %d)\n", p.Name(), plugin.EffectivePriority(p))
 			}
 		} else {
 			// 区分不同的情况
-			if config.AppConfig.EnabledPlugins == nil {
+			if config.Get().EnabledPlugins == nil {
 				fmt.Println("未设置插件列表 (ENABLED_PLUGINS)，未加载任何插件")
-			} else if len(config.AppConfig.EnabledPlugins) > 0 {
-				fmt.Printf("未找到指定的插件: %s\n", strings.Join(config.AppConfig.EnabledPlugins, ", "))
+			} else if len(config.Get().EnabledPlugins) > 0 {
+				fmt.Printf("未找到指定的插件: %s\n", strings.Join(config.Get().EnabledPlugins, ", "))
 			} else {
 				fmt.Println("插件列表为空 (ENABLED_PLUGINS=\"\")，未加载任何插件")
 			}
@@ -0,0 +1,48 @@
+package model
+
+// API密钥的权限范围
+const (
+	APIKeyScopeSearch    = "search"     // 搜索、插件目录等只读接口
+	APIKeyScopeCheck     = "check"      // 链接检测接口
+	APIKeyScopeAdmin     = "admin"      // 管理接口和/metrics
+	APIKeyScopePluginWeb = "plugin-web" // 插件注册的Web路由
+)
+
+// APIKeyScopes 所有可用的权限范围
+var APIKeyScopes = []string{APIKeyScopeSearch, APIKeyScopeCheck, APIKeyScopeAdmin, APIKeyScopePluginWeb}
+
+// APIKey API密钥的元数据，不包含密钥本身
+type APIKey struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`                 // 密钥开头的若干字符，便于识别
+	Scopes     []string `json:"scopes"`                 // 权限范围
+	PerMinute  int      `json:"per_minute,omitempty"`   // 每分钟请求数上限，0表示不限
+	PerDay     int      `json:"per_day,omitempty"`      // 每天请求数上限，0表示不限
+	CreatedAt  int64    `json:"created_at"`             // 创建时间（毫秒时间戳）
+	ExpiresAt  int64    `json:"expires_at,omitempty"`   // 过期时间（毫秒时间戳），0表示永不过期
+	RevokedAt  int64    `json:"revoked_at,omitempty"`   // 吊销时间（毫秒时间戳）
+	LastUsedAt int64    `json:"last_used_at,omitempty"` // 最近一次使用时间（毫秒时间戳）
+	UsedToday  int      `json:"used_today"`             // 当天已使用的请求数
+}
+
+// APIKeyCreateRequest 创建API密钥的请求
+type APIKeyCreateRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty"` // 有效天数，0表示永不过期
+	PerMinute     int      `json:"per_minute,omitempty"`
+	PerDay        int      `json:"per_day,omitempty"`
+}
+
+// APIKeyCreateResponse 创建API密钥的响应，明文密钥只在创建时返回一次
+type APIKeyCreateResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeysResponse API密钥列表响应
+type APIKeysResponse struct {
+	Keys  []APIKey `json:"keys"`
+	Total int      `json:"total"`
+}
@@ -0,0 +1,22 @@
+package model
+
+// 用户角色，按权限从低到高排列，高级角色拥有低级角色的全部权限
+const (
+	RoleViewer   = "viewer"   // 插件目录、插件状态、检测支持列表等只读接口
+	RoleSearcher = "searcher" // 搜索和链接检测
+	RoleOperator = "operator" // 插件管理页面、插件启停、检测后台状态和运行指标
+	RoleAdmin    = "admin"    // API密钥管理
+)
+
+// Roles 所有角色，按权限从低到高排列
+var Roles = []string{RoleViewer, RoleSearcher, RoleOperator, RoleAdmin}
+
+// RoleLevel 返回角色的权限级别，未知角色返回0
+func RoleLevel(role string) int {
+	for i, r := range Roles {
+		if r == role {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -25,3 +25,40 @@ type CheckResult struct {
 type CheckResponse struct {
 	Results []CheckResult `json:"results"`
 }
+
+type CheckProvider struct {
+	DiskType string `json:"disk_type"`
+}
+
+type CheckProvidersResponse struct {
+	Providers []CheckProvider `json:"providers"`
+	Total     int             `json:"total"`
+}
+
+// CheckTransition 复检发现的链接状态变化
+type CheckTransition struct {
+	DiskType string `json:"disk_type"`
+	URL      string `json:"url"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Summary  string `json:"summary,omitempty"`
+	At       int64  `json:"at"`
+}
+
+// CheckSweeperStatus 检测缓存后台复检的进度
+type CheckSweeperStatus struct {
+	Enabled           bool              `json:"enabled"`
+	Running           bool              `json:"running"`
+	IntervalSeconds   int64             `json:"interval_seconds"`
+	WindowSeconds     int64             `json:"window_seconds"`
+	LastStartedAt     int64             `json:"last_started_at,omitempty"`
+	LastFinishedAt    int64             `json:"last_finished_at,omitempty"`
+	Candidates        int               `json:"candidates"`  // 本轮待复检的链接数
+	Rechecked         int               `json:"rechecked"`   // 本轮已复检的链接数
+	Transitions       int               `json:"transitions"` // 本轮状态变化的链接数
+	TotalRechecked    int64             `json:"total_rechecked"`
+	TotalTransitions  int64             `json:"total_transitions"`
+	TotalDeaths       int64             `json:"total_deaths"`
+	RecentTransitions []CheckTransition `json:"recent_transitions"`
+	RecentDeaths      []CheckTransition `json:"recent_deaths"` // 最近由有效变为失效的链接
+}
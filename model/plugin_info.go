@@ -0,0 +1,81 @@
+package model
+
+// PluginInfo 插件目录中的一个插件
+type PluginInfo struct {
+	Name          string           `json:"name"`
+	DisplayName   string           `json:"display_name"`
+	Description   string           `json:"description,omitempty"`
+	Homepage      string           `json:"homepage,omitempty"`
+	Categories    []string         `json:"categories"`     // 内容分类：video、anime、magnet、adult、books
+	CloudTypes    []string         `json:"cloud_types"`    // 返回的网盘类型，为空表示不限
+	ExtKeys       []string         `json:"ext_keys"`       // 接受的ext参数名
+	RequiresLogin bool             `json:"requires_login"` // 是否需要登录后才能搜索
+	Enabled       bool             `json:"enabled"`        // 当前是否参与搜索
+	Priority      int              `json:"priority"`       // 插件等级，1最高
+	TimeoutMs     int64            `json:"timeout_ms"`     // 异步响应超时（毫秒）
+	Health        PluginHealthInfo `json:"health"`         // 健康状态
+}
+
+// PluginHealthInfo 插件的健康分和熔断状态
+type PluginHealthInfo struct {
+	Score               float64 `json:"score"`                // 健康分（0-1），按最近搜索的成功率平滑计算，影响结果排序
+	Circuit             string  `json:"circuit"`              // 熔断器状态：closed、open、half-open
+	ConsecutiveFailures int     `json:"consecutive_failures"` // 连续失败（出错或超时）次数
+	Successes           int64   `json:"successes"`
+	Failures            int64   `json:"failures"`
+	LastError           string  `json:"last_error,omitempty"`
+	OpenedAt            int64   `json:"opened_at,omitempty"` // 最近一次熔断的时间（毫秒时间戳）
+}
+
+// PluginsResponse 插件目录响应
+type PluginsResponse struct {
+	Plugins []PluginInfo `json:"plugins"`
+	Total   int          `json:"total"`
+	Enabled int          `json:"enabled"` // 当前参与搜索的插件数
+}
+
+// PluginStateUpdate 运行时修改插件状态的请求，未设置的字段保持不变
+type PluginStateUpdate struct {
+	Enabled   *bool  `json:"enabled,omitempty"`    // 是否参与搜索
+	Priority  *int   `json:"priority,omitempty"`   // 覆盖插件等级（1-4），0表示恢复默认
+	TimeoutMs *int64 `json:"timeout_ms,omitempty"` // 覆盖异步响应超时（毫秒），0表示恢复默认
+}
+
+// 插件探测状态
+const (
+	PluginProbeStatusOK      = "ok"      // 返回了带链接的结果
+	PluginProbeStatusEmpty   = "empty"   // 未报错但没有返回任何链接，常见于网站改版导致解析失败
+	PluginProbeStatusError   = "error"   // 返回错误
+	PluginProbeStatusTimeout = "timeout" // 未在响应时限内返回最终结果
+)
+
+// PluginProbeRun 插件的一次合成探测记录
+type PluginProbeRun struct {
+	At          int64  `json:"at"`     // 探测开始时间（毫秒时间戳）
+	Status      string `json:"status"` // ok、empty、error、timeout
+	Success     bool   `json:"success"`
+	ResultCount int    `json:"result_count"`
+	LinkCount   int    `json:"link_count"`
+	LatencyMs   int64  `json:"latency_ms"`
+	Error       string `json:"error,omitempty"`
+}
+
+// PluginProbeHistory 单个插件的探测历史
+type PluginProbeHistory struct {
+	Name        string           `json:"name"`
+	Enabled     bool             `json:"enabled"`               // 当前是否参与搜索
+	LastStatus  string           `json:"last_status,omitempty"` // 最近一次探测的状态
+	SuccessRate float64          `json:"success_rate"`          // 返回的记录中探测成功的比例
+	Runs        []PluginProbeRun `json:"runs"`                  // 最近的探测记录，最新的在前
+}
+
+// PluginsStatusResponse 插件探测状态响应
+type PluginsStatusResponse struct {
+	Enabled         bool                 `json:"enabled"`
+	Running         bool                 `json:"running"`
+	Keyword         string               `json:"keyword,omitempty"`
+	IntervalSeconds int64                `json:"interval_seconds"`
+	LastStartedAt   int64                `json:"last_started_at,omitempty"`
+	LastFinishedAt  int64                `json:"last_finished_at,omitempty"`
+	Plugins         []PluginProbeHistory `json:"plugins"`
+}
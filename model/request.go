@@ -2,8 +2,29 @@ package model
 
 // FilterConfig 过滤配置
 type FilterConfig struct {
-	Include []string `json:"include,omitempty"` // 包含关键词列表（OR关系）
-	Exclude []string `json:"exclude,omitempty"` // 排除关键词列表（AND关系）
+	Include  []string     `json:"include,omitempty"`   // 包含关键词列表（OR关系）
+	Exclude  []string     `json:"exclude,omitempty"`   // 排除关键词列表（AND关系）
+	Rules    []FilterRule `json:"rules,omitempty"`     // 过滤规则列表，全部满足才保留（AND关系）
+	MinLinks int          `json:"min_links,omitempty"` // results中每条结果过滤后至少保留的链接数
+}
+
+// 过滤规则支持的字段
+const (
+	FilterFieldTitle    = "title"    // 标题：results的title和链接的work_title，merged_by_type的note
+	FilterFieldContent  = "content"  // 内容：results的content，merged_by_type的note
+	FilterFieldTags     = "tags"     // 标签：results的tags，任一标签匹配即可
+	FilterFieldSource   = "source"   // 来源：tg:频道名 或 plugin:插件名
+	FilterFieldPassword = "password" // 提取码：存在提取码（且匹配pattern）时视为匹配
+	FilterFieldDatetime = "datetime" // 发布时间：在after和before范围内时视为匹配
+)
+
+// FilterRule 过滤规则
+type FilterRule struct {
+	Field   string `json:"field,omitempty"`   // 匹配字段，默认为title
+	Pattern string `json:"pattern,omitempty"` // 正则表达式，不区分大小写
+	After   string `json:"after,omitempty"`   // datetime字段的下限：YYYY-MM-DD、RFC3339或相对时长（如30d、12h）
+	Before  string `json:"before,omitempty"`  // datetime字段的上限，格式同after
+	Exclude bool   `json:"exclude,omitempty"` // 为true时排除匹配该规则的结果
 }
 
 // SearchRequest 搜索请求参数
@@ -18,4 +39,19 @@ type SearchRequest struct {
 	Ext          map[string]interface{} `json:"ext"`                         // 扩展参数，用于传递给插件的自定义参数
 	CloudTypes   []string               `json:"cloud_types"`                 // 指定返回的网盘类型列表，不指定则返回所有类型
 	Filter       *FilterConfig          `json:"filter,omitempty"`            // 过滤配置，用于过滤返回结果
+	Page         int                    `json:"page,omitempty"`              // 页码（从1开始），指定page、page_size或cursor时启用分页
+	PageSize     int                    `json:"page_size,omitempty"`         // 每页数量
+	Cursor       string                 `json:"cursor,omitempty"`            // 上一页返回的next_cursor，优先于page
+	Check        *bool                  `json:"check,omitempty"`             // 是否检测链接有效性，不指定则使用服务端默认配置
+	Debug        bool                   `json:"debug,omitempty"`             // 是否返回各数据源的诊断信息
+}
+
+// IsPaged 是否请求分页
+func (r SearchRequest) IsPaged() bool {
+	return r.Page > 0 || r.PageSize > 0 || r.Cursor != ""
+}
+
+// CheckLinks 是否检测链接有效性
+func (r SearchRequest) CheckLinks() bool {
+	return r.Check != nil && *r.Check
 } 
\ No newline at end of file
@@ -32,6 +32,7 @@ type MergedLink struct {
 	Datetime time.Time `json:"datetime" sonic:"datetime"`
 	Source   string    `json:"source,omitempty" sonic:"source,omitempty"` // 数据来源：tg:频道名 或 plugin:插件名
 	Images   []string  `json:"images,omitempty" sonic:"images,omitempty"`   // TG消息中的图片链接
+	State    string    `json:"state,omitempty" sonic:"state,omitempty"`     // 链接检测状态：ok、bad、locked、uncertain，未检测时为空
 }
 
 // MergedLinks 按网盘类型分组的合并链接
@@ -42,6 +43,37 @@ type SearchResponse struct {
 	Total        int           `json:"total" sonic:"total"`
 	Results      []SearchResult `json:"results,omitempty" sonic:"results,omitempty"`
 	MergedByType MergedLinks   `json:"merged_by_type,omitempty" sonic:"merged_by_type,omitempty"`
+	Diagnostics  []SourceDiagnostic `json:"diagnostics,omitempty" sonic:"diagnostics,omitempty"` // 各数据源的诊断信息，仅debug请求返回
+}
+
+// 数据源诊断状态
+const (
+	SourceStatusOK       = "ok"        // 正常返回
+	SourceStatusError    = "error"     // 返回错误
+	SourceStatusTimeout  = "timeout"   // 未在响应时限内返回
+	SourceStatusCacheHit = "cache-hit" // 命中整体搜索缓存，未实际请求
+	SourceStatusPartial  = "partial"   // 插件在内部响应超时前只返回了部分结果
+	SourceStatusCircuitOpen = "circuit-open" // 插件连续失败后熔断，本次搜索未调用
+)
+
+// SourceDiagnostic 单个数据源（TG频道或插件）本次搜索的诊断信息
+type SourceDiagnostic struct {
+	Source      string `json:"source" sonic:"source"`             // 数据源：tg:频道名 或 plugin:插件名
+	Status      string `json:"status" sonic:"status"`             // ok、error、timeout、cache-hit、partial、circuit-open
+	LatencyMs   int64  `json:"latency_ms" sonic:"latency_ms"`     // 数据源耗时（毫秒）
+	ResultCount int    `json:"result_count" sonic:"result_count"` // 返回的结果数
+	LinkCount   int    `json:"link_count" sonic:"link_count"`     // 过滤去重后合并链接中归属该数据源的链接数
+	Error       string `json:"error,omitempty" sonic:"error,omitempty"`
+}
+
+// SearchPageResponse 分页搜索响应
+// 分页单位：results类型按结果分页，merged_by_type类型按网盘类型名排序后逐条链接分页，all类型两者使用相同的偏移量
+type SearchPageResponse struct {
+	SearchResponse
+	Page       int    `json:"page" sonic:"page"`
+	PageSize   int    `json:"page_size" sonic:"page_size"`
+	HasMore    bool   `json:"has_more" sonic:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty" sonic:"next_cursor,omitempty"`
 }
 
 // Response API通用响应
@@ -66,4 +98,11 @@ func NewErrorResponse(code int, message string) Response {
 		Code:    code,
 		Message: message,
 	}
-}
\ No newline at end of file
+}
+// ConfigResponse 当前生效的配置，密码和密钥已脱敏
+type ConfigResponse struct {
+	ConfigFile string                 `json:"config_file,omitempty"` // 配置文件路径，未使用配置文件时为空
+	LoadedAt   int64                  `json:"loaded_at"`             // 最近一次加载配置的时间（毫秒时间戳）
+	Reloadable []string               `json:"reloadable"`            // 可以热加载的配置字段
+	Config     map[string]interface{} `json:"config"`
+}
@@ -0,0 +1,33 @@
+package model
+
+// 流式搜索（SSE）事件名称
+const (
+	StreamEventSource = "source" // 单个TG频道或插件返回结果
+	StreamEventMerged = "merged" // 全部数据源处理完成后的merged_by_type快照
+	StreamEventDone   = "done"   // 搜索结束
+	StreamEventError  = "error"  // 搜索失败
+)
+
+// StreamSourceEvent 单个数据源返回结果事件
+type StreamSourceEvent struct {
+	Source    string         `json:"source" sonic:"source"`                   // 数据来源：tg:频道名 或 plugin:插件名
+	Keyword   string         `json:"keyword" sonic:"keyword"`                 // 发送给数据源的关键词，查询有OR分支时区分各分支
+	Count     int            `json:"count" sonic:"count"`                     // 结果数量
+	Results   []SearchResult `json:"results" sonic:"results"`                 // 该数据源的搜索结果
+	Error     string         `json:"error,omitempty" sonic:"error,omitempty"` // 错误或超时信息
+	ElapsedMs int64          `json:"elapsed_ms" sonic:"elapsed_ms"`           // 从搜索开始到该数据源返回的耗时（毫秒）
+}
+
+// StreamMergedEvent 合并结果快照事件
+type StreamMergedEvent struct {
+	Total        int         `json:"total" sonic:"total"`
+	MergedByType MergedLinks `json:"merged_by_type" sonic:"merged_by_type"`
+}
+
+// StreamDoneEvent 搜索完成事件
+type StreamDoneEvent struct {
+	Total     int      `json:"total" sonic:"total"`
+	TimedOut  []string `json:"timed_out" sonic:"timed_out"` // 超时的数据源（插件会在后台继续处理并写入缓存）
+	Failed    []string `json:"failed" sonic:"failed"`       // 返回错误的数据源
+	ElapsedMs int64    `json:"elapsed_ms" sonic:"elapsed_ms"`
+}
@@ -1,7 +1,6 @@
 package ahhhhfs
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -157,7 +156,7 @@ func (p *AhhhhfsAsyncPlugin) searchImpl(client *http.Client, keyword string, ext
 	searchURL := fmt.Sprintf("https://www.ahhhhfs.com/?cat=&s=%s", url.QueryEscape(keyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -346,7 +345,7 @@ func (p *AhhhhfsAsyncPlugin) fetchDetailLinks(client *http.Client, detailURL, ar
 	atomic.AddInt64(&cacheMisses, 1)
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DetailTimeout)
 	defer cancel()
 	
 	// 创建请求
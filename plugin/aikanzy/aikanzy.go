@@ -1,7 +1,6 @@
 package aikanzy
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -138,7 +137,7 @@ func (p *AikanzyAsyncPlugin) doSearch(client *http.Client, keyword string, ext m
 	searchURL := fmt.Sprintf(searchURLTemplate, encodedKeyword)
 	
 	// 创建一个带有超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout*time.Second)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, defaultTimeout*time.Second)
 	defer cancel()
 	
 	// 创建请求
@@ -343,7 +342,7 @@ func (p *AikanzyAsyncPlugin) fetchDetailsWithLinks(items []ArticleItem, client *
 // fetchDetailPageLinks 抓取详情页的网盘链接
 func (p *AikanzyAsyncPlugin) fetchDetailPageLinks(detailURL string, client *http.Client) []model.Link {
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout*time.Second)
 	defer cancel()
 	
 	// 创建请求
@@ -1,7 +1,6 @@
 package alupan
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -118,7 +117,7 @@ func (p *AlupanPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	}
 
 	searchURL := fmt.Sprintf("https://www.aliupan.com/?s=%s", url.QueryEscape(keyword))
-	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, searchTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -249,7 +248,7 @@ func (p *AlupanPlugin) fetchDetailLinks(client *http.Client, detailURL, articleI
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
@@ -1,7 +1,6 @@
 package ash
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -75,7 +74,7 @@ func (p *AshPlugin) searchImpl(client *http.Client, keyword string, ext map[stri
 	searchURL := fmt.Sprintf("https://so.allsharehub.com/s/%s.html", url.QueryEscape(keyword))
 	
 	// 创建带超时的上下文（减少超时时间，提高响应速度）
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, 15*time.Second)
 	defer cancel()
 	
 	// 创建请求
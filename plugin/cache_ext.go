@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ============================================================
+// 缓存键中的ext参数：只有插件声明会影响结果的ext参数才参与缓存键
+// ============================================================
+
+// PluginWithCacheExtKeys 声明影响搜索结果的ext参数的插件接口
+// 未实现该接口的插件不受ext影响，请求中的ext参数不会拆分其缓存
+type PluginWithCacheExtKeys interface {
+	// CacheExtKeys 返回影响搜索结果的ext参数名
+	CacheExtKeys() []string
+}
+
+// CacheExtKeysOf 获取插件声明的ext参数，未声明时返回nil
+func CacheExtKeysOf(p AsyncSearchPlugin) []string {
+	if declared, ok := p.(PluginWithCacheExtKeys); ok {
+		return declared.CacheExtKeys()
+	}
+	return nil
+}
+
+// ExtCacheSignature 根据指定的ext参数生成规范化签名，用于拼接缓存键
+// 参数按名称排序，值为nil或空字符串的参数视为未设置；JSON数字与整数格式化结果一致（2.0与2相同）
+func ExtCacheSignature(ext map[string]interface{}, keys []string) string {
+	if len(ext) == 0 || len(keys) == 0 {
+		return ""
+	}
+
+	sortedKeys := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k != "" && !seen[k] {
+			seen[k] = true
+			sortedKeys = append(sortedKeys, k)
+		}
+	}
+	sort.Strings(sortedKeys)
+
+	parts := make([]string, 0, len(sortedKeys))
+	for _, k := range sortedKeys {
+		v, ok := ext[k]
+		if !ok || v == nil {
+			continue
+		}
+		if s, isString := v.(string); isString && strings.TrimSpace(s) == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(parts, "&")
+}
+
+// pluginCacheKey 生成插件内存缓存键：插件名、关键词和插件声明的ext参数
+func (p *BaseAsyncPlugin) pluginCacheKey(keyword string, ext map[string]interface{}) string {
+	key := fmt.Sprintf("%s:%s", p.name, keyword)
+	if signature := ExtCacheSignature(ext, p.cacheExtKeys); signature != "" {
+		key += "|" + signature
+	}
+	return key
+}
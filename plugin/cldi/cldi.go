@@ -1,7 +1,6 @@
 package cldi
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -124,7 +123,7 @@ func (p *CldiPlugin) searchPage(client *http.Client, keyword string, page int) (
 	searchURL := fmt.Sprintf("https://wvmzbxki.1122132.xyz/search-%s-0-2-%d.html", url.QueryEscape(keyword), page)
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 	
 	// 创建请求
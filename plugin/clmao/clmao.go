@@ -1,7 +1,6 @@
 package clmao
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -79,6 +78,20 @@ func (p *ClmaoPlugin) Description() string {
 	return "磁力猫 - 磁力链接搜索引擎"
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *ClmaoPlugin) CacheExtKeys() []string {
+	return []string{"search"}
+}
+
+// Metadata 插件元数据
+func (p *ClmaoPlugin) Metadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Homepage:   BaseURL,
+		Categories: []string{plugin.CategoryMagnet},
+		CloudTypes: []string{"magnet"},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *ClmaoPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -165,7 +178,7 @@ func (p *ClmaoPlugin) searchPage(client *http.Client, keyword string, page int)
 	searchURL := fmt.Sprintf(SearchURL, encodedKeyword, page)
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), TimeoutSeconds*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, TimeoutSeconds*time.Second)
 	defer cancel()
 	
 	// 创建请求
@@ -1,7 +1,6 @@
 package cyg
 
 import (
-	"context"
 	"fmt"
 	"html"
 	"io"
@@ -84,6 +83,11 @@ func init() {
 	plugin.RegisterGlobalPlugin(p)
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *CygPlugin) CacheExtKeys() []string {
+	return []string{"per_page", "page", "order_by", "order"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *CygPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -129,7 +133,7 @@ func (p *CygPlugin) searchImpl(client *http.Client, keyword string, ext map[stri
 // fetchSearchResults 获取搜索结果列表
 func (p *CygPlugin) fetchSearchResults(client *http.Client, searchURL string) ([]CygPost, error) {
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	// 创建请求对象
@@ -220,7 +224,7 @@ func (p *CygPlugin) getDownloadLinks(client *http.Client, postID int) ([]model.L
 	downloadURL := fmt.Sprintf("https://cyg.app/wp-json/acg-studio/v1/download?id=%d", postID)
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	// 创建请求对象
@@ -1,7 +1,6 @@
 package daishudj
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -123,7 +122,7 @@ func (p *DaishuPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	}
 
 	searchURL := fmt.Sprintf("https://www.daishuduanju.com/?s=%s", url.QueryEscape(keyword))
-	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, searchTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -223,7 +222,7 @@ func (p *DaishuPlugin) fetchDetailLinks(client *http.Client, detailURL, postID s
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
@@ -1,7 +1,6 @@
 package ddys
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"log"
@@ -113,7 +112,7 @@ func (p *DdysPlugin) executeSearch(client *http.Client, keyword string) ([]model
 	searchURL := fmt.Sprintf("%s%s", BaseURL, fmt.Sprintf(SearchPath, url.QueryEscape(keyword)))
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -410,7 +409,7 @@ func (p *DdysPlugin) fetchDetailPageLinks(client *http.Client, detailURL string)
 	}
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
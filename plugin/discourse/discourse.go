@@ -144,6 +144,11 @@ func NewDiscourseAsyncPlugin() *DiscourseAsyncPlugin {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *DiscourseAsyncPlugin) CacheExtKeys() []string {
+	return []string{"max_pages", "page"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *DiscourseAsyncPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -1,7 +1,6 @@
 package djgou
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -114,7 +113,7 @@ func (p *DjgouPlugin) searchImpl(client *http.Client, keyword string, ext map[st
 	searchURL := fmt.Sprintf("%s/search.php?q=%s&page=1", SiteURL, url.QueryEscape(keyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -335,7 +334,7 @@ type DetailCacheData struct {
 // fetchDetailPage 获取详情页信息
 func (p *DjgouPlugin) fetchDetailPage(client *http.Client, detailURL string) ([]model.Link, string) {
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DetailTimeout)
 	defer cancel()
 	
 	// 创建请求
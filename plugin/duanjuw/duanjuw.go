@@ -1,7 +1,6 @@
 package duanjuw
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -100,7 +99,7 @@ func (p *DuanjuwPlugin) searchImpl(client *http.Client, keyword string, ext map[
 	}
 
 	searchURL := fmt.Sprintf(duanjuwSearchURL, url.QueryEscape(keyword))
-	ctx, cancel := context.WithTimeout(context.Background(), duanjuwSearchTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, duanjuwSearchTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -232,7 +231,7 @@ func (p *DuanjuwPlugin) fetchDetailInfo(client *http.Client, detailURL string) (
 		duanjuwDetailCache.Delete(detailURL)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), duanjuwDetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, duanjuwDetailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
@@ -1,7 +1,6 @@
 package duoduo
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -155,7 +154,7 @@ func (p *DuoduoAsyncPlugin) searchImpl(client *http.Client, keyword string, ext
 	searchURL := fmt.Sprintf("https://tv.yydsys.top/index.php/vod/search/wd/%s.html", url.QueryEscape(keyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -412,7 +411,7 @@ func (p *DuoduoAsyncPlugin) fetchDetailLinksAndImages(client *http.Client, itemI
 	detailURL := fmt.Sprintf("https://tv.yydsys.top/index.php/vod/detail/id/%s.html", itemID)
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DetailTimeout)
 	defer cancel()
 
 	// 创建请求
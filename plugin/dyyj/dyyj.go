@@ -1,7 +1,6 @@
 package dyyj
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"log"
@@ -208,7 +207,7 @@ func (p *DyyjPlugin) executeSearch(client *http.Client, keyword string) ([]model
 	}
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, RequestTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -787,7 +786,7 @@ func (p *DyyjPlugin) fetchDetailPageLinks(client *http.Client, detailURL string)
 	}
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, RequestTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
@@ -7,7 +7,6 @@ import (
 	"regexp"
 	"strings"
 	"time"
-	"context"
 	"sync"
 	"sync/atomic"
 
@@ -139,7 +138,7 @@ func (p *ErxiaoAsyncPlugin) searchImpl(client *http.Client, keyword string, ext
 	searchURL := fmt.Sprintf("https://erxiaofn.click/index.php/vod/search/wd/%s.html", url.QueryEscape(keyword))
 
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 
 	// 3. 创建请求
@@ -363,7 +362,7 @@ func (p *ErxiaoAsyncPlugin) fetchDetailLinksAndImages(client *http.Client, itemI
 	detailURL := fmt.Sprintf("https://erxiaofn.click/index.php/vod/detail/id/%s.html", itemID)
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DetailTimeout)
 	defer cancel()
 
 	// 创建请求
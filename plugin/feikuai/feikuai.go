@@ -1,7 +1,6 @@
 package feikuai
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -100,7 +99,7 @@ func (p *FeikuaiPlugin) searchImpl(client *http.Client, keyword string, ext map[
 	searchURL := fmt.Sprintf(SearchAPIURL, url.QueryEscape(keyword))
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 创建请求
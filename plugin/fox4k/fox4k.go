@@ -1,7 +1,6 @@
 package fox4k
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"math/rand"
@@ -352,7 +351,7 @@ func (p *Fox4kPlugin) searchPage(client *http.Client, encodedKeyword string, pag
 	debugPrintf("🔧 [Fox4k DEBUG] 构建的URL: %s\n", searchURL)
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -691,7 +690,7 @@ func (p *Fox4kPlugin) getDetailInfo(id string, client *http.Client) *detailPageR
 	detailURL := fmt.Sprintf(DetailURL, id)
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DefaultTimeout)
 	defer cancel()
 	
 	// 创建请求
@@ -1,7 +1,6 @@
 package gaoqing888
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -321,7 +320,7 @@ func fetchDocument(client *http.Client, requestURL string, timeout time.Duration
 func fetchBody(client *http.Client, requestURL string, timeout time.Duration, referer string) ([]byte, error) {
 	var lastErr error
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := plugin.ClientTimeoutContext(client, timeout)
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 		if err != nil {
 			cancel()
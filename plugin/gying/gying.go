@@ -729,6 +729,17 @@ func (p *GyingPlugin) updateBaseURL(rawBaseURL string) (string, error) {
 	return baseURL, nil
 }
 
+// Metadata 插件元数据
+func (p *GyingPlugin) Metadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName:   "观影",
+		Description:   "观影 - 登录后检索影视资源并聚合网盘链接",
+		Homepage:      DefaultGyingBaseURL,
+		Categories:    []string{plugin.CategoryVideo},
+		RequiresLogin: true,
+	}
+}
+
 // Initialize 实现 InitializablePlugin 接口，延迟初始化插件
 func (p *GyingPlugin) Initialize() error {
 	if p.initialized {
@@ -1559,7 +1570,7 @@ func (p *GyingPlugin) applyProxyToScraper(scraper *cloudscraper.Scraper) error {
 	if scraper == nil {
 		return fmt.Errorf("scraper 实例为空")
 	}
-	if config.AppConfig == nil || strings.TrimSpace(config.AppConfig.ProxyURL) == "" {
+	if config.Get() == nil || strings.TrimSpace(config.Get().ProxyURL) == "" {
 		return nil
 	}
 
@@ -1588,7 +1599,7 @@ func (p *GyingPlugin) applyProxyToScraper(scraper *cloudscraper.Scraper) error {
 		return fmt.Errorf("底层 transport 无效")
 	}
 
-	proxyURL, err := url.Parse(config.AppConfig.ProxyURL)
+	proxyURL, err := url.Parse(config.Get().ProxyURL)
 	if err != nil {
 		return fmt.Errorf("解析代理地址失败: %w", err)
 	}
@@ -1607,7 +1618,7 @@ func (p *GyingPlugin) applyProxyToScraper(scraper *cloudscraper.Scraper) error {
 	}
 
 	if DebugLog {
-		fmt.Printf("[Gying] 已应用代理到scraper: %s\n", config.AppConfig.ProxyURL)
+		fmt.Printf("[Gying] 已应用代理到scraper: %s\n", config.Get().ProxyURL)
 	}
 
 	return nil
@@ -1,7 +1,6 @@
 package haisou
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -89,6 +88,11 @@ func init() {
 	plugin.RegisterGlobalPlugin(p)
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *HaisouPlugin) CacheExtKeys() []string {
+	return []string{"pages_per_type"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *HaisouPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -319,7 +323,7 @@ func (p *HaisouPlugin) fetchSearchPage(client *http.Client, keyword string, page
 	}
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	// 创建请求对象
@@ -381,7 +385,7 @@ func (p *HaisouPlugin) fetchShareLink(client *http.Client, hsid string, platform
 	}
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 15*time.Second)
 	defer cancel()
 
 	// 创建请求对象
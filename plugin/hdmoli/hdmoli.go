@@ -1,7 +1,6 @@
 package hdmoli
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"log"
@@ -113,7 +112,7 @@ func (p *HdmoliPlugin) executeSearch(client *http.Client, keyword string) ([]mod
 	searchURL := fmt.Sprintf("%s%s", BaseURL, fmt.Sprintf(SearchPath, url.QueryEscape(keyword)))
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -533,7 +532,7 @@ func (p *HdmoliPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 	}
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
@@ -106,6 +106,11 @@ func NewHdr4kAsyncPlugin() *Hdr4kAsyncPlugin {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *Hdr4kAsyncPlugin) CacheExtKeys() []string {
+	return []string{"title_en"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *Hdr4kAsyncPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
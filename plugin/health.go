@@ -0,0 +1,186 @@
+package plugin
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"pansou/config"
+)
+
+// ============================================================
+// 插件熔断与健康评分：连续失败的插件暂停调用，冷却后用一次真实搜索探测
+// ============================================================
+
+// 熔断器状态
+const (
+	CircuitClosed   = "closed"    // 正常调用
+	CircuitOpen     = "open"      // 冷却中，跳过该插件
+	CircuitHalfOpen = "half-open" // 冷却结束，放行一次探测搜索
+)
+
+// 健康分的平滑系数，每次搜索结果占新健康分的比例
+const healthScoreAlpha = 0.2
+
+// breakerNow 熔断器使用的当前时间，测试中替换为可控的时钟
+var breakerNow = time.Now
+
+// PluginHealth 插件健康状态
+type PluginHealth struct {
+	Name                string
+	Score               float64 // 健康分（0-1），按搜索成功率指数平滑
+	Circuit             string  // 熔断器状态，取值见Circuit*常量
+	ConsecutiveFailures int     // 连续失败次数
+	Successes           int64
+	Failures            int64
+	LastError           string
+	OpenedAt            time.Time // 最近一次熔断的时间
+}
+
+// pluginBreaker 单个插件的熔断器
+type pluginBreaker struct {
+	mu                  sync.Mutex
+	circuit             string
+	consecutiveFailures int
+	openedAt            time.Time
+	probeStartedAt      time.Time // 半开状态下探测搜索的开始时间，零值表示未在探测
+	score               float64
+	successes           int64
+	failures            int64
+	lastError           string
+}
+
+// 插件熔断器，按插件名索引
+var pluginBreakers sync.Map
+
+// breakerOf 获取插件的熔断器，不存在时创建
+func breakerOf(name string) *pluginBreaker {
+	if breaker, ok := pluginBreakers.Load(name); ok {
+		return breaker.(*pluginBreaker)
+	}
+	breaker, _ := pluginBreakers.LoadOrStore(name, &pluginBreaker{circuit: CircuitClosed, score: 1})
+	return breaker.(*pluginBreaker)
+}
+
+// breakerSettings 熔断阈值和冷却时间，阈值为0表示不熔断
+func breakerSettings() (int, time.Duration) {
+	if config.Get() == nil {
+		return 0, 0
+	}
+	return config.Get().PluginBreakerThreshold, config.Get().PluginBreakerCooldown
+}
+
+// AllowPluginRequest 检查熔断器是否允许本次搜索调用插件
+// 冷却结束后转为半开状态，只放行一次探测搜索；探测在冷却时间内没有结果时允许重新探测
+func AllowPluginRequest(name string) bool {
+	threshold, cooldown := breakerSettings()
+	if threshold <= 0 {
+		return true
+	}
+
+	breaker := breakerOf(name)
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	now := breakerNow()
+	switch breaker.circuit {
+	case CircuitOpen:
+		if now.Sub(breaker.openedAt) < cooldown {
+			return false
+		}
+		breaker.circuit = CircuitHalfOpen
+		breaker.probeStartedAt = now
+		return true
+	case CircuitHalfOpen:
+		if !breaker.probeStartedAt.IsZero() && now.Sub(breaker.probeStartedAt) < cooldown {
+			return false
+		}
+		breaker.probeStartedAt = now
+		return true
+	}
+	return true
+}
+
+// RecordPluginSuccess 记录插件的一次成功搜索，半开状态下探测成功后恢复正常调用
+func RecordPluginSuccess(name string) {
+	breaker := breakerOf(name)
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.successes++
+	breaker.score = breaker.score*(1-healthScoreAlpha) + healthScoreAlpha
+	breaker.consecutiveFailures = 0
+	breaker.circuit = CircuitClosed
+	breaker.probeStartedAt = time.Time{}
+}
+
+// RecordPluginFailure 记录插件的一次失败（出错或超时），连续失败达到阈值或探测失败时熔断
+func RecordPluginFailure(name string, err error) {
+	threshold, _ := breakerSettings()
+
+	breaker := breakerOf(name)
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.failures++
+	breaker.score = breaker.score * (1 - healthScoreAlpha)
+	breaker.consecutiveFailures++
+	if err != nil {
+		breaker.lastError = err.Error()
+	}
+
+	if threshold <= 0 {
+		return
+	}
+	if breaker.circuit == CircuitHalfOpen || breaker.consecutiveFailures >= threshold {
+		breaker.circuit = CircuitOpen
+		breaker.openedAt = breakerNow()
+		breaker.probeStartedAt = time.Time{}
+	}
+}
+
+// PluginHealthOf 获取插件当前的健康状态
+func PluginHealthOf(name string) PluginHealth {
+	value, ok := pluginBreakers.Load(name)
+	if !ok {
+		// 尚未调用过的插件视为健康
+		return PluginHealth{Name: name, Score: 1, Circuit: CircuitClosed}
+	}
+
+	breaker := value.(*pluginBreaker)
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	return PluginHealth{
+		Name:                name,
+		Score:               breaker.score,
+		Circuit:             breaker.circuit,
+		ConsecutiveFailures: breaker.consecutiveFailures,
+		Successes:           breaker.successes,
+		Failures:            breaker.failures,
+		LastError:           breaker.lastError,
+		OpenedAt:            breaker.openedAt,
+	}
+}
+
+// PluginHealthScore 获取插件的健康分，熔断中的插件为0
+func PluginHealthScore(name string) float64 {
+	health := PluginHealthOf(name)
+	if health.Circuit == CircuitOpen {
+		return 0
+	}
+	return health.Score
+}
+
+// GetPluginHealths 获取所有已调用过的插件的健康状态，按插件名排序
+func GetPluginHealths() []PluginHealth {
+	var healths []PluginHealth
+	pluginBreakers.Range(func(key, _ interface{}) bool {
+		healths = append(healths, PluginHealthOf(key.(string)))
+		return true
+	})
+	sort.Slice(healths, func(i, j int) bool {
+		return healths[i].Name < healths[j].Name
+	})
+	return healths
+}
@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"pansou/config"
+)
+
+// useBreakerClock 设置熔断配置并替换熔断器时钟，返回推进时钟的函数
+func useBreakerClock(t *testing.T, threshold int, cooldown time.Duration) func(time.Duration) {
+	t.Helper()
+	previous := config.Get()
+	config.Set(&config.Config{PluginBreakerThreshold: threshold, PluginBreakerCooldown: cooldown})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breakerNow = func() time.Time { return now }
+	t.Cleanup(func() {
+		breakerNow = time.Now
+		config.Set(previous)
+	})
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	const cooldown = time.Minute
+	errSearch := errors.New("search failed")
+
+	// 每一步执行一个操作后检查熔断器状态
+	type step struct {
+		action      string // fail、succeed、allow、advance
+		advance     time.Duration
+		wantAllow   bool
+		wantCircuit string
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"below threshold stays closed", []step{
+			{action: "fail", wantCircuit: CircuitClosed},
+			{action: "fail", wantCircuit: CircuitClosed},
+			{action: "allow", wantAllow: true, wantCircuit: CircuitClosed},
+		}},
+		{"success resets consecutive failures", []step{
+			{action: "fail", wantCircuit: CircuitClosed},
+			{action: "fail", wantCircuit: CircuitClosed},
+			{action: "succeed", wantCircuit: CircuitClosed},
+			{action: "fail", wantCircuit: CircuitClosed},
+			{action: "fail", wantCircuit: CircuitClosed},
+		}},
+		{"closed open half-open closed", []step{
+			{action: "fail", wantCircuit: CircuitClosed},
+			{action: "fail", wantCircuit: CircuitClosed},
+			{action: "fail", wantCircuit: CircuitOpen},
+			{action: "allow", wantAllow: false, wantCircuit: CircuitOpen},
+			{action: "advance", advance: cooldown - time.Second, wantCircuit: CircuitOpen},
+			{action: "allow", wantAllow: false, wantCircuit: CircuitOpen},
+			{action: "advance", advance: time.Second, wantCircuit: CircuitOpen},
+			// 冷却结束后只放行一次探测
+			{action: "allow", wantAllow: true, wantCircuit: CircuitHalfOpen},
+			{action: "allow", wantAllow: false, wantCircuit: CircuitHalfOpen},
+			{action: "succeed", wantCircuit: CircuitClosed},
+			{action: "allow", wantAllow: true, wantCircuit: CircuitClosed},
+		}},
+		{"failed probe reopens", []step{
+			{action: "fail"}, {action: "fail"}, {action: "fail", wantCircuit: CircuitOpen},
+			{action: "advance", advance: cooldown, wantCircuit: CircuitOpen},
+			{action: "allow", wantAllow: true, wantCircuit: CircuitHalfOpen},
+			// 探测失败立即重新熔断，不需要再次达到阈值
+			{action: "fail", wantCircuit: CircuitOpen},
+			{action: "allow", wantAllow: false, wantCircuit: CircuitOpen},
+			{action: "advance", advance: cooldown, wantCircuit: CircuitOpen},
+			{action: "allow", wantAllow: true, wantCircuit: CircuitHalfOpen},
+		}},
+		{"probe without result can be retried after cooldown", []step{
+			{action: "fail"}, {action: "fail"}, {action: "fail", wantCircuit: CircuitOpen},
+			{action: "advance", advance: cooldown, wantCircuit: CircuitOpen},
+			{action: "allow", wantAllow: true, wantCircuit: CircuitHalfOpen},
+			{action: "advance", advance: cooldown / 2, wantCircuit: CircuitHalfOpen},
+			{action: "allow", wantAllow: false, wantCircuit: CircuitHalfOpen},
+			{action: "advance", advance: cooldown / 2, wantCircuit: CircuitHalfOpen},
+			{action: "allow", wantAllow: true, wantCircuit: CircuitHalfOpen},
+		}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			advance := useBreakerClock(t, 3, cooldown)
+			name := fmt.Sprintf("breaker-test-%d", i)
+			t.Cleanup(func() { pluginBreakers.Delete(name) })
+
+			for n, s := range tt.steps {
+				switch s.action {
+				case "fail":
+					RecordPluginFailure(name, errSearch)
+				case "succeed":
+					RecordPluginSuccess(name)
+				case "advance":
+					advance(s.advance)
+				case "allow":
+					if got := AllowPluginRequest(name); got != s.wantAllow {
+						t.Fatalf("step %d: AllowPluginRequest() = %v, want %v", n, got, s.wantAllow)
+					}
+				}
+				if s.wantCircuit != "" {
+					if got := PluginHealthOf(name).Circuit; got != s.wantCircuit {
+						t.Fatalf("step %d (%s): circuit = %s, want %s", n, s.action, got, s.wantCircuit)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	useBreakerClock(t, 0, time.Minute)
+	const name = "breaker-test-disabled"
+	t.Cleanup(func() { pluginBreakers.Delete(name) })
+
+	for i := 0; i < 10; i++ {
+		RecordPluginFailure(name, errors.New("failed"))
+	}
+	if !AllowPluginRequest(name) || PluginHealthOf(name).Circuit != CircuitClosed {
+		t.Error("阈值为0时不应熔断")
+	}
+}
+
+func TestPluginHealthScore(t *testing.T) {
+	useBreakerClock(t, 2, time.Minute)
+	const name = "breaker-test-score"
+	t.Cleanup(func() { pluginBreakers.Delete(name) })
+
+	if got := PluginHealthScore(name); got != 1 {
+		t.Errorf("未调用过的插件健康分 = %v, want 1", got)
+	}
+
+	RecordPluginFailure(name, errors.New("timeout"))
+	health := PluginHealthOf(name)
+	if math.Abs(health.Score-0.8) > 1e-9 || health.LastError != "timeout" || health.Failures != 1 {
+		t.Errorf("一次失败后 = %+v, want score 0.8", health)
+	}
+	RecordPluginSuccess(name)
+	if got := PluginHealthScore(name); math.Abs(got-0.84) > 1e-9 {
+		t.Errorf("成功后健康分 = %v, want 0.84", got)
+	}
+
+	// 熔断中的插件健康分为0
+	RecordPluginFailure(name, nil)
+	RecordPluginFailure(name, nil)
+	if got := PluginHealthScore(name); got != 0 {
+		t.Errorf("熔断时健康分 = %v, want 0", got)
+	}
+}
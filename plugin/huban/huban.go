@@ -7,7 +7,6 @@ import (
 	"regexp"
 	"strings"
 	"time"
-	"context"
 	"sync"
 	"sync/atomic"
 
@@ -188,7 +187,7 @@ func (p *HubanAsyncPlugin) searchImpl(client *http.Client, keyword string, ext m
 	searchURL := fmt.Sprintf("http://103.45.162.207:20720/index.php/vod/search/wd/%s.html", url.QueryEscape(keyword))
 
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 
 	// 3. 创建请求
@@ -411,7 +410,7 @@ func (p *HubanAsyncPlugin) fetchDetailLinksAndImages(client *http.Client, itemID
 	detailURL := fmt.Sprintf("http://103.45.162.207:20720/index.php/vod/detail/id/%s.html", itemID)
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DetailTimeout)
 	defer cancel()
 
 	// 创建请求
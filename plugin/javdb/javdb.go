@@ -1,7 +1,6 @@
 package javdb
 
 import (
-	"context"
 	"crypto/md5"
 	"fmt"
 	"io"
@@ -82,6 +81,15 @@ func (p *JavdbPlugin) Description() string {
 	return Description
 }
 
+// Metadata 插件元数据
+func (p *JavdbPlugin) Metadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Homepage:   BaseURL,
+		Categories: []string{plugin.CategoryAdult, plugin.CategoryMagnet},
+		CloudTypes: []string{"magnet"},
+	}
+}
+
 // SkipServiceFilter 磁力搜索插件，跳过Service层过滤
 func (p *JavdbPlugin) SkipServiceFilter() bool {
 	return true // 磁力搜索，跳过网盘服务过滤
@@ -169,7 +177,7 @@ func (p *JavdbPlugin) executeSearchWithRateLimit(client *http.Client, keyword st
 	}
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -797,7 +805,7 @@ func (p *JavdbPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL
 	}
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
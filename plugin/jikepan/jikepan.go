@@ -35,6 +35,11 @@ func NewJikepanAsyncV2Plugin() *JikepanAsyncV2Plugin {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *JikepanAsyncV2Plugin) CacheExtKeys() []string {
+	return []string{"is_all"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *JikepanAsyncV2Plugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
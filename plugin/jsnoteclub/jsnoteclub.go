@@ -1,7 +1,6 @@
 package jsnoteclub
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -96,6 +95,11 @@ func NewJsNoteClubPlugin() *JsNoteClubPlugin {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *JsNoteClubPlugin) CacheExtKeys() []string {
+	return []string{"title_en"}
+}
+
 // Search 兼容方法
 func (p *JsNoteClubPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -221,7 +225,7 @@ func (p *JsNoteClubPlugin) getAllPosts(client *http.Client) ([]ghostPost, error)
 }
 
 func (p *JsNoteClubPlugin) fetchDataKey(client *http.Client) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, requestTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://jsnoteclub.com/", nil)
@@ -269,7 +273,7 @@ func (p *JsNoteClubPlugin) fetchPosts(client *http.Client, dataKey string) ([]gh
 
 	reqURL := fmt.Sprintf("https://jsnoteclub.com/ghost/api/content/posts/?%s", params.Encode())
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, requestTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
@@ -304,7 +308,7 @@ func (p *JsNoteClubPlugin) fetchDetailLinks(client *http.Client, detailURL strin
 		detailCache.Delete(detailURL)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
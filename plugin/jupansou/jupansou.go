@@ -2,7 +2,6 @@ package jupansou
 
 import (
 	"bufio"
-	"context"
 	"crypto/md5"
 	"fmt"
 	"net/http"
@@ -71,7 +70,7 @@ func (p *JuPansouPlugin) searchImpl(client *http.Client, keyword string, ext map
 	}
 
 	searchURL := fmt.Sprintf(jupansouAPIURL, url.QueryEscape(keyword))
-	ctx, cancel := context.WithTimeout(context.Background(), jupansouTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, jupansouTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
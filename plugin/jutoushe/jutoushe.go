@@ -1,7 +1,6 @@
 package jutoushe
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -48,7 +47,7 @@ func (p *JutoushePlugin) searchImpl(client *http.Client, keyword string, ext map
 	searchURL := fmt.Sprintf("%s/search/?keyword=%s", baseURL, url.QueryEscape(keyword))
 
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, 30*time.Second)
 	defer cancel()
 
 	// 3. 创建请求对象
@@ -159,7 +158,7 @@ func (p *JutoushePlugin) doRequestWithRetry(req *http.Request, client *http.Clie
 
 // getDetailLinks 获取详情页的下载链接
 func (p *JutoushePlugin) getDetailLinks(client *http.Client, detailURL string) []model.Link {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 15*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
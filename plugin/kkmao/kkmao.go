@@ -1,7 +1,6 @@
 package kkmao
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -109,7 +108,7 @@ func (p *KkMaoPlugin) searchImpl(client *http.Client, keyword string, ext map[st
 	}
 
 	searchURL := fmt.Sprintf("https://www.kuakemao.com/?s=%s", url.QueryEscape(keyword))
-	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, searchTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -236,7 +235,7 @@ func (p *KkMaoPlugin) fetchDetailLinks(client *http.Client, detailURL, articleID
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
@@ -1,7 +1,6 @@
 package kkv
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -115,7 +114,7 @@ func (p *KKVPlugin) filterItemsByKeyword(items []searchItem, keyword string) []s
 
 func (p *KKVPlugin) fetchSearchResults(searchURL string, client *http.Client) ([]searchItem, error) {
 	debugPrintf("🌐 请求搜索页面: %s\n", searchURL)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -205,7 +204,7 @@ func (p *KKVPlugin) processDetailPages(items []searchItem, client *http.Client)
 
 func (p *KKVPlugin) processDetailPage(item searchItem, client *http.Client) *model.SearchResult {
 	debugPrintf("🎬 处理详情页: %s (ID: %s)\n", item.Title, item.ID)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", item.DetailURL, nil)
@@ -1,7 +1,6 @@
 package labi
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -121,7 +120,7 @@ func (p *LabiAsyncPlugin) searchImpl(client *http.Client, keyword string, ext ma
 	searchURL := fmt.Sprintf("http://xiaocge.fun/index.php/vod/search/wd/%s.html", url.QueryEscape(keyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -368,7 +367,7 @@ func (p *LabiAsyncPlugin) fetchDetailLinksAndImages(client *http.Client, itemID
 	detailURL := fmt.Sprintf("http://xiaocge.fun/index.php/vod/detail/id/%s.html", itemID)
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DetailTimeout)
 	defer cancel()
 
 	// 创建请求
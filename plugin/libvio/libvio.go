@@ -64,6 +64,14 @@ func (p *LibvioPlugin) Description() string {
 	return "LIBVIO - 影视资源网盘下载"
 }
 
+// Metadata 插件元数据
+func (p *LibvioPlugin) Metadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Homepage:   BaseURL,
+		Categories: []string{plugin.CategoryVideo},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *LibvioPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -1,7 +1,6 @@
 package lingjisp
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -235,7 +234,7 @@ func doLingjiGET(client *http.Client, requestURL string, timeout time.Duration)
 	var lastErr error
 
 	for attempt := 0; attempt < lingjiMaxRetries; attempt++ {
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := plugin.ClientTimeoutContext(client, timeout)
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 		if err != nil {
 			cancel()
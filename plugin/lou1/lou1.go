@@ -1,7 +1,6 @@
 package lou1
 
 import (
-	"context"
 	"fmt"
 	"hash/crc32"
 	"net/http"
@@ -171,7 +170,7 @@ type searchThread struct {
 func (p *Lou1Plugin) fetchSearchResults(client *http.Client, keyword string) ([]searchThread, error) {
 	searchURL := fmt.Sprintf(searchPathFormat, encodeKeyword(keyword))
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, requestTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -246,7 +245,7 @@ type detailResult struct {
 }
 
 func (p *Lou1Plugin) fetchDetail(client *http.Client, detailURL string) (detailResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
@@ -2,7 +2,6 @@ package meitizy
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -147,7 +146,7 @@ func (p *MeitizyPlugin) searchImpl(client *http.Client, keyword string, ext map[
 	apiURL := BaseURL + SearchPath
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, RequestTimeout)
 	defer cancel()
 
 	// 创建POST请求
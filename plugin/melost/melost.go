@@ -2,7 +2,6 @@ package melost
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"html"
 	"io"
@@ -152,7 +151,7 @@ func (p *MelostAsyncPlugin) searchPage(client *http.Client, keyword string, page
 		return nil, fmt.Errorf("marshal request failed: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DefaultTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "POST", MelostSearchAPI, bytes.NewBuffer(jsonData))
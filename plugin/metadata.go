@@ -0,0 +1,69 @@
+package plugin
+
+// ============================================================
+// 插件元数据：供前端构建插件选择器，插件可选择实现
+// ============================================================
+
+// 插件内容分类
+const (
+	CategoryVideo  = "video"  // 影视
+	CategoryAnime  = "anime"  // 动漫
+	CategoryMagnet = "magnet" // 磁力/BT
+	CategoryAdult  = "adult"  // 成人
+	CategoryBooks  = "books"  // 书籍/文档
+)
+
+// PluginMetadata 插件元数据
+type PluginMetadata struct {
+	DisplayName   string   // 显示名称
+	Description   string   // 插件描述
+	Homepage      string   // 数据来源网站
+	Categories    []string // 内容分类，取值见Category*常量
+	CloudTypes    []string // 返回的网盘类型，为空表示不限
+	ExtKeys       []string // 接受的ext参数名
+	RequiresLogin bool     // 是否需要登录后才能搜索
+}
+
+// PluginWithMetadata 提供元数据的插件接口
+type PluginWithMetadata interface {
+	// Metadata 返回插件元数据
+	Metadata() PluginMetadata
+}
+
+// MetadataOf 获取插件元数据
+// 未实现PluginWithMetadata的插件使用DisplayName()/Description()方法（如果有）补全，
+// 未声明ext参数时使用插件声明的缓存ext参数
+func MetadataOf(p AsyncSearchPlugin) PluginMetadata {
+	var metadata PluginMetadata
+	if declared, ok := p.(PluginWithMetadata); ok {
+		metadata = declared.Metadata()
+	}
+
+	if metadata.DisplayName == "" {
+		if named, ok := p.(interface{ DisplayName() string }); ok {
+			metadata.DisplayName = named.DisplayName()
+		}
+	}
+	if metadata.DisplayName == "" {
+		metadata.DisplayName = p.Name()
+	}
+	if metadata.Description == "" {
+		if described, ok := p.(interface{ Description() string }); ok {
+			metadata.Description = described.Description()
+		}
+	}
+	if metadata.ExtKeys == nil {
+		metadata.ExtKeys = CacheExtKeysOf(p)
+	}
+
+	if metadata.Categories == nil {
+		metadata.Categories = []string{}
+	}
+	if metadata.CloudTypes == nil {
+		metadata.CloudTypes = []string{}
+	}
+	if metadata.ExtKeys == nil {
+		metadata.ExtKeys = []string{}
+	}
+	return metadata
+}
@@ -0,0 +1,45 @@
+package plugin
+
+import (
+	"sync/atomic"
+
+	"pansou/config"
+	"pansou/util/metrics"
+)
+
+// 异步插件系统的内部统计导出为Prometheus指标
+func init() {
+	metrics.NewCounterVecFunc("pansou_plugin_cache_lookups_total", "异步插件内存缓存查询次数", []string{"result"}, func() []metrics.Sample {
+		return []metrics.Sample{
+			{LabelValues: []string{"hit"}, Value: float64(atomic.LoadInt64(&cacheHits))},
+			{LabelValues: []string{"miss"}, Value: float64(atomic.LoadInt64(&cacheMisses))},
+		}
+	})
+	metrics.NewCounterFunc("pansou_plugin_async_completions_total", "异步插件后台补全完成次数", func() float64 {
+		return float64(atomic.LoadInt64(&asyncCompletions))
+	})
+	metrics.NewGaugeFunc("pansou_plugin_background_tasks", "正在运行的后台任务数", func() float64 {
+		return float64(atomic.LoadInt32(&backgroundTasksCount))
+	})
+	metrics.NewGaugeFunc("pansou_plugin_background_tasks_max", "允许的最大后台任务数", func() float64 {
+		return float64(maxBackgroundTasks())
+	})
+	metrics.NewGaugeFunc("pansou_plugin_background_workers_max", "后台工作池大小", func() float64 {
+		return float64(cap(backgroundWorkerPool))
+	})
+	metrics.NewGaugeFunc("pansou_plugin_background_saturation", "后台任务数占最大任务数的比例", func() float64 {
+		maxTasks := maxBackgroundTasks()
+		if maxTasks <= 0 {
+			return 0
+		}
+		return float64(atomic.LoadInt32(&backgroundTasksCount)) / float64(maxTasks)
+	})
+}
+
+// maxBackgroundTasks 获取允许的最大后台任务数
+func maxBackgroundTasks() int {
+	if config.Get() != nil {
+		return config.Get().AsyncMaxBackgroundTasks
+	}
+	return defaultMaxBackgroundTasks
+}
@@ -1,7 +1,6 @@
 package miaoso
 
 import (
-	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/base64"
@@ -64,6 +63,11 @@ func NewMiaosouPlugin() *MiaosouPlugin {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *MiaosouPlugin) CacheExtKeys() []string {
+	return []string{"title_en"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *MiaosouPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -94,7 +98,7 @@ func (p *MiaosouPlugin) searchImpl(client *http.Client, keyword string, ext map[
 	searchURL := fmt.Sprintf("%s?name=%s&pageNo=1", BaseURL, url.QueryEscape(searchKeyword))
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), TimeoutSeconds*time.Second)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, TimeoutSeconds*time.Second)
 	defer cancel()
 	
 	// 创建请求
@@ -1,7 +1,6 @@
 package mikuclub
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -234,7 +233,7 @@ func (p *MikuclubPlugin) fetchCategoryPosts(client *http.Client, keyword, catID
 
 	reqURL := fmt.Sprintf("https://www.mikuclub.uk/wp-json/utils/v2/post_list?%s", params.Encode())
 
-	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, searchTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
@@ -271,7 +270,7 @@ func (p *MikuclubPlugin) fetchDetailLinks(client *http.Client, postID int64, det
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
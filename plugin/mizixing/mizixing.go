@@ -1,7 +1,6 @@
 package mizixing
 
 import (
-	"context"
 	"fmt"
 	"hash/crc32"
 	"net/http"
@@ -172,7 +171,7 @@ type searchItem struct {
 func (p *MizixingPlugin) fetchSearchResults(client *http.Client, keyword string) ([]searchItem, error) {
 	searchURL := fmt.Sprintf("%s?s=%s", searchEndpoint, url.QueryEscape(keyword))
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, requestTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -235,7 +234,7 @@ type detailData struct {
 }
 
 func (p *MizixingPlugin) fetchDetailData(client *http.Client, detailURL string) (detailData, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
@@ -1,7 +1,6 @@
 package nsgame
 
 import (
-	"context"
 	"crypto/md5"
 	"fmt"
 	"io"
@@ -102,7 +101,7 @@ func (p *NSGameAsyncPlugin) searchImpl(client *http.Client, keyword string, ext
 		apiURL, pageSize, url.QueryEscape(keyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, defaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
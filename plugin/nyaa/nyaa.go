@@ -1,7 +1,6 @@
 package nyaa
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -70,6 +69,22 @@ func NewNyaaPlugin() *NyaaPlugin {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *NyaaPlugin) CacheExtKeys() []string {
+	return []string{"title_en"}
+}
+
+// Metadata 插件元数据
+func (p *NyaaPlugin) Metadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "Nyaa",
+		Description: "Nyaa - 动漫BT资源搜索",
+		Homepage:    SiteURL,
+		Categories:  []string{plugin.CategoryAnime, plugin.CategoryMagnet},
+		CloudTypes:  []string{"magnet"},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *NyaaPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -100,7 +115,7 @@ func (p *NyaaPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	searchURL := fmt.Sprintf("%s/?f=0&c=0_0&q=%s", SiteURL, url.QueryEscape(searchKeyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -8,7 +8,6 @@ import (
 	"regexp"
 	"strings"
 	"time"
-	"context"
 	"sync/atomic"
 
 	"pansou/model"
@@ -120,7 +119,7 @@ func (p *OugeAsyncPlugin) searchImpl(client *http.Client, keyword string, ext ma
 	searchURL := fmt.Sprintf("https://woog.nxog.eu.org/api.php/provide/vod?ac=detail&wd=%s", url.QueryEscape(keyword))
 	
 	// 创建HTTP请求
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
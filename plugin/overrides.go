@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"sync"
+	"time"
+
+	"pansou/config"
+)
+
+// ============================================================
+// 插件运行时覆盖：管理接口修改的优先级和响应超时
+// ============================================================
+
+// PluginOverride 插件的运行时覆盖设置，零值表示使用插件默认值
+type PluginOverride struct {
+	Priority int           // 覆盖的优先级等级（1-4）
+	Timeout  time.Duration // 覆盖的异步响应超时
+}
+
+// 插件运行时覆盖设置
+var (
+	pluginOverrides     = make(map[string]PluginOverride)
+	pluginOverridesLock sync.RWMutex
+)
+
+// SetPluginOverride 设置插件的运行时覆盖，全部为零值时清除覆盖
+func SetPluginOverride(name string, override PluginOverride) {
+	pluginOverridesLock.Lock()
+	defer pluginOverridesLock.Unlock()
+
+	if override.Priority == 0 && override.Timeout == 0 {
+		delete(pluginOverrides, name)
+		return
+	}
+	pluginOverrides[name] = override
+}
+
+// GetPluginOverride 获取插件的运行时覆盖
+func GetPluginOverride(name string) (PluginOverride, bool) {
+	pluginOverridesLock.RLock()
+	defer pluginOverridesLock.RUnlock()
+
+	override, exists := pluginOverrides[name]
+	return override, exists
+}
+
+// EffectivePriority 获取插件当前生效的优先级，优先使用运行时覆盖
+func EffectivePriority(p AsyncSearchPlugin) int {
+	if override, ok := GetPluginOverride(p.Name()); ok && override.Priority > 0 {
+		return override.Priority
+	}
+	return p.Priority()
+}
+
+// EffectiveResponseTimeout 获取插件当前生效的异步响应超时，优先使用运行时覆盖
+func EffectiveResponseTimeout(name string) time.Duration {
+	if override, ok := GetPluginOverride(name); ok && override.Timeout > 0 {
+		return override.Timeout
+	}
+	if config.Get() != nil {
+		return config.Get().AsyncResponseTimeoutDur
+	}
+	return defaultAsyncResponseTimeout
+}
@@ -500,6 +500,15 @@ func (p *PanlianPlugin) Description() string {
 	return Description
 }
 
+// Metadata 插件元数据
+func (p *PanlianPlugin) Metadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Homepage:      DefaultBaseURL,
+		Categories:    []string{plugin.CategoryVideo},
+		RequiresLogin: true,
+	}
+}
+
 func (p *PanlianPlugin) Initialize() error {
 	if p.initialized {
 		return nil
@@ -841,7 +850,7 @@ func (p *PanlianPlugin) doJSONGET(client *http.Client, cookie string, path strin
 
 	var lastErr error
 	for attempt := 0; attempt < 3; attempt++ {
-		ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+		ctx, cancel := plugin.ClientTimeoutContext(client, RequestTimeout)
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
 		if err != nil {
 			cancel()
@@ -898,6 +907,7 @@ func (p *PanlianPlugin) doLogin(username string, password string, remember bool)
 	}
 
 	loginPageURL := DefaultBaseURL + "/pages/login.php"
+	// 登录会话由所有搜索共享，不随单次搜索取消
 	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loginPageURL, nil)
 	if err != nil {
@@ -965,7 +975,7 @@ func (p *PanlianPlugin) doLogin(username string, password string, remember bool)
 		return "", nil, fmt.Errorf("解析登录响应失败: %w", err)
 	}
 	if !loginResp.Success {
-		return "", nil, fmt.Errorf(strings.TrimSpace(loginResp.Message))
+		return "", nil, errors.New(strings.TrimSpace(loginResp.Message))
 	}
 
 	baseURL, _ := url.Parse(DefaultBaseURL)
@@ -361,7 +361,7 @@ func (p *PanSearchAsyncPlugin) getBuildId() (string, error) {
 		return buildIdCache, nil
 	}
 
-	// 创建带超时的上下文
+	// 创建带超时的上下文，buildId由所有搜索共享，不随单次搜索取消
 	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
 	defer cancel()
 
@@ -559,7 +559,7 @@ func (p *PanSearchAsyncPlugin) doSearch(client *http.Client, keyword string, ext
 	pool := NewWorkerPool(actualConcurrent, neededPages)
 
 	// 创建上下文用于管理所有请求
-	ctx, cancel := context.WithTimeout(context.Background(), p.timeout*2)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, p.timeout*2)
 	defer cancel()
 
 	// 创建一个标志，用于标记是否需要刷新buildId
@@ -577,7 +577,7 @@ func (p *PanSearchAsyncPlugin) doSearch(client *http.Client, keyword string, ext
 				continue
 			}
 
-			pageResults, err = p.fetchPage(task.keyword, task.offset, task.baseURL)
+			pageResults, err = p.fetchPage(ctx, task.keyword, task.offset, task.baseURL)
 			if err == nil {
 				break
 			}
@@ -745,7 +745,7 @@ func (p *PanSearchAsyncPlugin) fetchFirstPage(keyword string, baseURL string, cl
 	reqURL := fmt.Sprintf("%s?keyword=%s&offset=0", baseURL, url.QueryEscape(keyword))
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, p.timeout)
 	defer cancel()
 
 	// 发送请求
@@ -799,12 +799,12 @@ func (p *PanSearchAsyncPlugin) fetchFirstPage(keyword string, baseURL string, cl
 }
 
 // fetchPage 获取指定偏移量的页面
-func (p *PanSearchAsyncPlugin) fetchPage(keyword string, offset int, baseURL string) ([]PanSearchItem, error) {
+func (p *PanSearchAsyncPlugin) fetchPage(ctx context.Context, keyword string, offset int, baseURL string) ([]PanSearchItem, error) {
 	// 构建请求URL
 	reqURL := fmt.Sprintf("%s?keyword=%s&offset=%d", baseURL, url.QueryEscape(keyword), offset)
 
-	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	// 创建带超时的上下文，搜索取消时随之停止
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
 
 	// 发送请求
@@ -1,7 +1,6 @@
 package panta
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -239,7 +238,7 @@ func (p *PantaAsyncPlugin) doSearch(client *http.Client, keyword string, ext map
 	searchURL := fmt.Sprintf(searchURLTemplate, encodedKeyword)
 	
 	// 创建一个带有超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(defaultTimeout)*time.Second)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, time.Duration(defaultTimeout)*time.Second)
 	defer cancel()
 	
 	// 创建请求
@@ -599,7 +598,7 @@ func (p *PantaAsyncPlugin) fetchThreadLinks(topicID string, client *http.Client)
 	threadURL := fmt.Sprintf(threadURLTemplate, topicID)
 	
 	// 创建一个带有超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(defaultTimeout)*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, time.Duration(defaultTimeout)*time.Second)
 	defer cancel()
 	
 	// 创建请求
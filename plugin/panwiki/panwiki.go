@@ -32,6 +32,7 @@ type PanwikiPlugin struct {
 	cacheTTL    time.Duration
 	debugMode   bool     // debug模式开关
 	currentBaseURL string // 当前使用的域名
+	baseURLMu      sync.RWMutex // 保护currentBaseURL，并发的搜索可能同时切换域名
 }
 
 // NewPanwikiPlugin 创建Panwiki插件实例
@@ -58,15 +59,24 @@ func NewPanwikiPlugin() *PanwikiPlugin {
 func (p *PanwikiPlugin) getSearchURL(keyword string, page int) string {
 	var searchURL string
 	if page <= 1 {
-		searchURL = fmt.Sprintf(p.currentBaseURL+SearchPath, url.QueryEscape(keyword))
+		searchURL = fmt.Sprintf(p.baseURL()+SearchPath, url.QueryEscape(keyword))
 	} else {
-		searchURL = fmt.Sprintf(p.currentBaseURL+SearchPath+"&page=%d", url.QueryEscape(keyword), page)
+		searchURL = fmt.Sprintf(p.baseURL()+SearchPath+"&page=%d", url.QueryEscape(keyword), page)
 	}
 	return searchURL
 }
 
+// baseURL 获取当前使用的域名
+func (p *PanwikiPlugin) baseURL() string {
+	p.baseURLMu.RLock()
+	defer p.baseURLMu.RUnlock()
+	return p.currentBaseURL
+}
+
 // switchToBackupDomain 切换到备用域名
 func (p *PanwikiPlugin) switchToBackupDomain() {
+	p.baseURLMu.Lock()
+	defer p.baseURLMu.Unlock()
 	if p.currentBaseURL == PrimaryBaseURL {
 		p.currentBaseURL = BackupBaseURL
 		if p.debugMode {
@@ -166,15 +176,16 @@ func (p *PanwikiPlugin) searchPage(client *http.Client, keyword string, page int
 	
 	p.setRequestHeaders(req)
 	
-	// 不自动跟随重定向
-	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+	// 不自动跟随重定向：client由插件框架共享，在副本上修改，避免并发搜索互相影响
+	noRedirectClient := *client
+	noRedirectClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
 	
-	resp, err := client.Do(req)
+	resp, err := noRedirectClient.Do(req)
 	if err != nil {
 		// 如果主域名失败，尝试切换到备用域名
-		if p.currentBaseURL == PrimaryBaseURL {
+		if p.baseURL() == PrimaryBaseURL {
 			if p.debugMode {
 				log.Printf("[Panwiki] 主域名请求失败，尝试备用域名: %v", err)
 			}
@@ -188,7 +199,7 @@ func (p *PanwikiPlugin) searchPage(client *http.Client, keyword string, page int
 			}
 			p.setRequestHeaders(req)
 			
-			resp, err = client.Do(req)
+			resp, err = noRedirectClient.Do(req)
 			if err != nil {
 				return nil, fmt.Errorf("备用域名请求也失败: %w", err)
 			}
@@ -198,9 +209,6 @@ func (p *PanwikiPlugin) searchPage(client *http.Client, keyword string, page int
 	}
 	defer resp.Body.Close()
 	
-	// 重置重定向策略
-	client.CheckRedirect = nil
-	
 	// 获取重定向URL
 	location := resp.Header.Get("Location")
 	if location == "" {
@@ -212,7 +220,7 @@ func (p *PanwikiPlugin) searchPage(client *http.Client, keyword string, page int
 	if strings.HasPrefix(location, "http") {
 		searchURL = location
 	} else {
-		searchURL = p.currentBaseURL + "/" + strings.TrimPrefix(location, "/")
+		searchURL = p.baseURL() + "/" + strings.TrimPrefix(location, "/")
 	}
 	
 	// 如果不是第一页，修改URL中的page参数
@@ -223,7 +231,7 @@ func (p *PanwikiPlugin) searchPage(client *http.Client, keyword string, page int
 			matches := re.FindStringSubmatch(searchURL)
 			if len(matches) > 1 {
 				searchid := matches[1]
-				searchURL = fmt.Sprintf("%s/search.php?mod=forum&searchid=%s&orderby=lastpost&ascdesc=desc&searchsubmit=yes&page=%d", p.currentBaseURL, searchid, page)
+				searchURL = fmt.Sprintf("%s/search.php?mod=forum&searchid=%s&orderby=lastpost&ascdesc=desc&searchsubmit=yes&page=%d", p.baseURL(), searchid, page)
 			}
 		}
 	}
@@ -258,7 +266,7 @@ func (p *PanwikiPlugin) searchPage(client *http.Client, keyword string, page int
 // setRequestHeaders 设置请求头
 func (p *PanwikiPlugin) setRequestHeaders(req *http.Request) {
 	req.Header.Set("User-Agent", UserAgent)
-	req.Header.Set("Referer", p.currentBaseURL+"/")
+	req.Header.Set("Referer", p.baseURL()+"/")
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
 	req.Header.Set("Cache-Control", "no-cache")
@@ -302,7 +310,7 @@ func (p *PanwikiPlugin) parseSearchResult(s *goquery.Selection) model.SearchResu
 		if strings.HasPrefix(detailPath, "http") {
 			detailURL = detailPath
 		} else {
-			detailURL = p.currentBaseURL + "/" + strings.TrimPrefix(detailPath, "/")
+			detailURL = p.baseURL() + "/" + strings.TrimPrefix(detailPath, "/")
 		}
 	}
 	
@@ -1,7 +1,6 @@
 package pianku
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -93,6 +92,11 @@ func NewPiankuPlugin() *PiankuPlugin {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *PiankuPlugin) CacheExtKeys() []string {
+	return []string{"title_en"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *PiankuPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -123,7 +127,7 @@ func (p *PiankuPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	searchURL := fmt.Sprintf("%s%s?wd=%s", BaseURL, SearchPath, url.QueryEscape(searchKeyword))
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), TimeoutSeconds*time.Second)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, TimeoutSeconds*time.Second)
 	defer cancel()
 	
 	// 创建请求
@@ -375,7 +379,7 @@ func (p *PiankuPlugin) buildFullURL(path string) string {
 // fetchDetailPageLinks 获取详情页的下载链接
 func (p *PiankuPlugin) fetchDetailPageLinks(client *http.Client, detailURL string) ([]model.Link, error) {
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), TimeoutSeconds*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, TimeoutSeconds*time.Second)
 	defer cancel()
 	
 	// 创建请求
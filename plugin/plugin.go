@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"pansou/config"
 	"pansou/model"
+	"pansou/util/normalize"
 )
 
 // ============================================================
@@ -28,11 +30,10 @@ type AsyncSearchPlugin interface {
 	// AsyncSearch 异步搜索方法
 	AsyncSearch(keyword string, searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error), mainCacheKey string, ext map[string]interface{}) ([]model.SearchResult, error)
 
-	// SetMainCacheKey 设置主缓存键
-	SetMainCacheKey(key string)
-
-	// SetCurrentKeyword 设置当前搜索关键词（用于日志显示）
-	SetCurrentKeyword(keyword string)
+	// AsyncSearchWithContext 带上下文的异步搜索方法
+	// ctx在响应前取消时会中止前台HTTP请求并立即返回；响应后的后台缓存补全与ctx脱离
+	// mainCacheKey和keyword随本次调用经ext传递给插件内部的AsyncSearch/AsyncSearchWithResult
+	AsyncSearchWithContext(ctx context.Context, keyword string, searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error), mainCacheKey string, ext map[string]interface{}) ([]model.SearchResult, error)
 
 	// Search 兼容性方法（内部调用AsyncSearch）
 	Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error)
@@ -138,6 +139,11 @@ func RegisterGlobalPlugin(plugin AsyncSearchPlugin) {
 		return
 	}
 
+	// 插件声明的ext参数同步到BaseAsyncPlugin，用于生成插件内存缓存键
+	if setter, ok := plugin.(interface{ SetCacheExtKeys([]string) }); ok {
+		setter.SetCacheExtKeys(CacheExtKeysOf(plugin))
+	}
+
 	globalRegistry[name] = plugin
 }
 
@@ -166,6 +172,7 @@ func GetPluginByName(name string) (AsyncSearchPlugin, bool) {
 // PluginManager 异步插件管理器
 type PluginManager struct {
 	plugins []AsyncSearchPlugin
+	mu      sync.RWMutex
 }
 
 // NewPluginManager 创建新的异步插件管理器
@@ -185,7 +192,9 @@ func (pm *PluginManager) RegisterPlugin(plugin AsyncSearchPlugin) {
 		}
 	}
 
+	pm.mu.Lock()
 	pm.plugins = append(pm.plugins, plugin)
+	pm.mu.Unlock()
 }
 
 // RegisterAllGlobalPlugins 注册所有全局异步插件
@@ -226,8 +235,72 @@ func (pm *PluginManager) RegisterGlobalPluginsWithFilter(enabledPlugins []string
 }
 
 // GetPlugins 获取所有注册的异步插件
+// 返回副本，运行时启用/禁用插件不会影响正在进行的搜索
 func (pm *PluginManager) GetPlugins() []AsyncSearchPlugin {
-	return pm.plugins
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	plugins := make([]AsyncSearchPlugin, len(pm.plugins))
+	copy(plugins, pm.plugins)
+	return plugins
+}
+
+// IsEnabled 检查插件是否已启用
+func (pm *PluginManager) IsEnabled(name string) bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	for _, p := range pm.plugins {
+		if p.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EnablePlugin 运行时启用全局注册表中的插件，已启用时不做任何操作
+func (pm *PluginManager) EnablePlugin(name string) error {
+	plugin, exists := GetPluginByName(name)
+	if !exists {
+		return fmt.Errorf("插件 %s 不存在", name)
+	}
+	if pm.IsEnabled(name) {
+		return nil
+	}
+
+	// 如果插件支持延迟初始化，先执行初始化（Initialize应是幂等的）
+	if initPlugin, ok := plugin.(InitializablePlugin); ok {
+		if err := initPlugin.Initialize(); err != nil {
+			return fmt.Errorf("插件 %s 初始化失败: %w", name, err)
+		}
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	for _, p := range pm.plugins {
+		if p.Name() == name {
+			return nil
+		}
+	}
+	pm.plugins = append(pm.plugins, plugin)
+	return nil
+}
+
+// DisablePlugin 运行时禁用插件，返回插件之前是否已启用
+func (pm *PluginManager) DisablePlugin(name string) bool {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	for i, p := range pm.plugins {
+		if p.Name() == name {
+			plugins := make([]AsyncSearchPlugin, 0, len(pm.plugins)-1)
+			plugins = append(plugins, pm.plugins[:i]...)
+			plugins = append(plugins, pm.plugins[i+1:]...)
+			pm.plugins = plugins
+			return true
+		}
+	}
+	return false
 }
 
 // ============================================================
@@ -243,22 +316,22 @@ func FilterResultsByKeyword(results []model.SearchResult, keyword string) []mode
 	// 预估过滤后会保留80%的结果
 	filteredResults := make([]model.SearchResult, 0, len(results)*8/10)
 
-	// 将关键词转为小写，用于不区分大小写的比较
-	lowerKeyword := strings.ToLower(keyword)
-
-	// 将关键词按空格分割，用于支持多关键词搜索
-	keywords := strings.Fields(lowerKeyword)
+	// 将关键词按空格分割，用于支持多关键词搜索；
+	// 每个关键词归一化（繁简、全半角、大小写、标点），与同样归一化的标题和内容比较
+	keywords := strings.Fields(keyword)
+	for i, kw := range keywords {
+		keywords[i] = normalize.Text(kw)
+	}
 
 	for _, result := range results {
-		// 将标题和内容转为小写
-		lowerTitle := strings.ToLower(result.Title)
-		lowerContent := strings.ToLower(result.Content)
+		normalizedTitle := normalize.Text(result.Title)
+		normalizedContent := normalize.Text(result.Content)
 
 		// 检查每个关键词是否在标题或内容中
 		matched := true
 		for _, kw := range keywords {
 			// 对于所有关键词，检查是否在标题或内容中
-			if !strings.Contains(lowerTitle, kw) && !strings.Contains(lowerContent, kw) {
+			if !strings.Contains(normalizedTitle, kw) && !strings.Contains(normalizedContent, kw) {
 				matched = false
 				break
 			}
@@ -331,8 +404,8 @@ func initAsyncPlugin() {
 
 	// 如果配置已加载，则从配置读取工作池大小
 	maxWorkers := defaultMaxBackgroundWorkers
-	if config.AppConfig != nil {
-		maxWorkers = config.AppConfig.AsyncMaxBackgroundWorkers
+	if config.Get() != nil {
+		maxWorkers = config.Get().AsyncMaxBackgroundWorkers
 	}
 
 	backgroundWorkerPool = make(chan struct{}, maxWorkers)
@@ -350,10 +423,7 @@ func InitAsyncPluginSystem() {
 // acquireWorkerSlot 尝试获取工作槽
 func acquireWorkerSlot() bool {
 	// 获取最大任务数
-	maxTasks := int32(defaultMaxBackgroundTasks)
-	if config.AppConfig != nil {
-		maxTasks = int32(config.AppConfig.AsyncMaxBackgroundTasks)
-	}
+	maxTasks := int32(maxBackgroundTasks())
 
 	// 检查总任务数
 	if atomic.LoadInt32(&backgroundTasksCount) >= maxTasks {
@@ -424,11 +494,11 @@ type BaseAsyncPlugin struct {
 	backgroundClient   *http.Client                                                          // 用于长超时的客户端
 	cacheTTL           time.Duration                                                         // 内存缓存有效期
 	mainCacheUpdater   func(string, []model.SearchResult, time.Duration, bool, string) error // 主缓存更新函数（支持IsFinal参数，接收原始数据，最后参数为关键词）
-	MainCacheKey       string                                                                // 主缓存键，导出字段
-	currentKeyword     string                                                                // 当前搜索的关键词，用于日志显示
+	MainCacheKey       string                                                                // 已废弃：主缓存键随每次调用传递，保留字段仅为兼容插件写法
 	finalUpdateTracker map[string]bool                                                       // 追踪已更新的最终结果缓存
 	finalUpdateMutex   sync.RWMutex                                                          // 保护finalUpdateTracker的并发访问
 	skipServiceFilter  bool                                                                  // 是否跳过Service层的关键词过滤
+	cacheExtKeys       []string                                                              // 影响搜索结果的ext参数，参与插件内存缓存键
 }
 
 // NewBaseAsyncPlugin 创建基础异步插件
@@ -444,10 +514,10 @@ func NewBaseAsyncPlugin(name string, priority int) *BaseAsyncPlugin {
 	cacheTTL := defaultCacheTTL
 
 	// 如果配置已初始化，则使用配置中的值
-	if config.AppConfig != nil {
-		responseTimeout = config.AppConfig.AsyncResponseTimeoutDur
-		processingTimeout = config.AppConfig.PluginTimeout
-		cacheTTL = time.Duration(config.AppConfig.AsyncCacheTTLHours) * time.Hour
+	if config.Get() != nil {
+		responseTimeout = config.Get().AsyncResponseTimeoutDur
+		processingTimeout = config.Get().PluginTimeout
+		cacheTTL = time.Duration(config.Get().AsyncCacheTTLHours) * time.Hour
 	}
 
 	return &BaseAsyncPlugin{
@@ -478,10 +548,10 @@ func NewBaseAsyncPluginWithFilter(name string, priority int, skipServiceFilter b
 	cacheTTL := defaultCacheTTL
 
 	// 如果配置已初始化，则使用配置中的值
-	if config.AppConfig != nil {
-		responseTimeout = config.AppConfig.AsyncResponseTimeoutDur
-		processingTimeout = config.AppConfig.PluginTimeout
-		cacheTTL = time.Duration(config.AppConfig.AsyncCacheTTLHours) * time.Hour
+	if config.Get() != nil {
+		responseTimeout = config.Get().AsyncResponseTimeoutDur
+		processingTimeout = config.Get().PluginTimeout
+		cacheTTL = time.Duration(config.Get().AsyncCacheTTLHours) * time.Hour
 	}
 
 	return &BaseAsyncPlugin{
@@ -503,14 +573,19 @@ func NewBaseAsyncPluginWithFilter(name string, priority int, skipServiceFilter b
 // 第七部分：BaseAsyncPlugin 接口实现方法
 // ============================================================
 
-// SetMainCacheKey 设置主缓存键
+// SetMainCacheKey 设置默认主缓存键
+// 已废弃：插件是全局单例，并发搜索会互相覆盖该字段；主缓存键应随AsyncSearchWithContext调用传递
 func (p *BaseAsyncPlugin) SetMainCacheKey(key string) {
 	p.MainCacheKey = key
 }
 
-// SetCurrentKeyword 设置当前搜索关键词（用于日志显示）
-func (p *BaseAsyncPlugin) SetCurrentKeyword(keyword string) {
-	p.currentKeyword = keyword
+// SetCurrentKeyword 保留的兼容方法，不再保存任何状态
+// 已废弃：关键词随每次调用传递
+func (p *BaseAsyncPlugin) SetCurrentKeyword(keyword string) {}
+
+// SetCacheExtKeys 设置影响搜索结果的ext参数（注册插件时根据PluginWithCacheExtKeys自动设置）
+func (p *BaseAsyncPlugin) SetCacheExtKeys(keys []string) {
+	p.cacheExtKeys = keys
 }
 
 // SetMainCacheUpdater 设置主缓存更新函数（修复后的签名，增加关键词参数）
@@ -548,16 +623,34 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
 	mainCacheKey string,
 	ext map[string]interface{},
+) ([]model.SearchResult, error) {
+	return p.AsyncSearchWithContext(context.Background(), keyword, searchFunc, mainCacheKey, ext)
+}
+
+// AsyncSearchWithContext 带上下文的异步搜索基础方法
+func (p *BaseAsyncPlugin) AsyncSearchWithContext(
+	ctx context.Context,
+	keyword string,
+	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
+	mainCacheKey string,
+	ext map[string]interface{},
 ) ([]model.SearchResult, error) {
 	// 确保ext不为nil
 	if ext == nil {
 		ext = make(map[string]interface{})
 	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// 本次调用的主缓存键和关键词，通过ext传给searchFunc中嵌套的插件搜索
+	call := resolveSearchCall(keyword, mainCacheKey, ext)
+	ext = withSearchCall(ext, call)
 
 	now := time.Now()
 
-	// 修改缓存键，确保包含插件名称
-	pluginSpecificCacheKey := fmt.Sprintf("%s:%s", p.name, keyword)
+	// 修改缓存键，确保包含插件名称和插件声明的ext参数
+	pluginSpecificCacheKey := p.pluginCacheKey(keyword, ext)
 	forceRefresh := ext != nil && ext["refresh"] == true
 
 	// 检查缓存
@@ -572,7 +665,7 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 
 				// 如果缓存接近过期（已用时间超过TTL的80%），在后台刷新缓存
 				if time.Since(cachedResult.Timestamp) > (p.cacheTTL * 4 / 5) {
-					go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, mainCacheKey, ext)
+					go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, call, WithoutSearchContext(ext))
 				}
 
 				return cachedResult.Results, nil
@@ -586,7 +679,7 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 				// 标记为部分过期
 				if time.Since(cachedResult.Timestamp) >= p.cacheTTL {
 					// 在后台刷新缓存
-					go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, mainCacheKey, ext)
+					go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, call, WithoutSearchContext(ext))
 
 					// 日志记录
 					fmt.Printf("[%s] 缓存已过期，后台刷新中: %s (已过期: %v)\n",
@@ -600,6 +693,10 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 
 	recordCacheMiss()
 
+	// 前台搜索上下文：响应前请求取消则中止HTTP请求，响应后与请求脱离以完成后台缓存
+	searchCtx, detach, cancel := detachableContext(ctx)
+	searchExt := WithSearchContext(ext, searchCtx)
+
 	// 创建通道
 	resultChan := make(chan []model.SearchResult, 1)
 	errorChan := make(chan error, 1)
@@ -607,10 +704,12 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 
 	// 启动后台处理
 	go func() {
+		defer cancel()
+
 		// 尝试获取工作槽
 		if !acquireWorkerSlot() {
 			// 工作池已满，使用快速响应客户端直接处理
-			results, err := searchFunc(p.client, keyword, ext)
+			results, err := searchFunc(clientWithContext(p.client, searchCtx), keyword, searchExt)
 			if err != nil {
 				select {
 				case errorChan <- err:
@@ -634,14 +733,14 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 			})
 
 			// 🔧 工作池满时短超时(默认4秒)内完成，这是完整结果
-			p.updateMainCacheWithFinal(mainCacheKey, results, true)
+			p.updateMainCacheWithFinal(call, results, true)
 
 			return
 		}
 		defer releaseWorkerSlot()
 
 		// 执行搜索
-		results, err := searchFunc(p.backgroundClient, keyword, ext)
+		results, err := searchFunc(clientWithContext(p.backgroundClient, searchCtx), keyword, searchExt)
 
 		// 检查是否已经响应
 		select {
@@ -691,7 +790,7 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 				recordAsyncCompletion()
 
 				// 异步插件后台完成时更新主缓存（标记为最终结果）
-				p.updateMainCacheWithFinal(mainCacheKey, results, true)
+				p.updateMainCacheWithFinal(call, results, true)
 
 				// 异步插件本地缓存系统已移除
 			}
@@ -744,29 +843,34 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 				})
 
 				// 🔧 短超时(默认4秒)内正常完成，这是完整的最终结果
-				p.updateMainCacheWithFinal(mainCacheKey, results, true)
+				p.updateMainCacheWithFinal(call, results, true)
 
 				// 异步插件本地缓存系统已移除
 			}
 		}
 	}()
 
-	// 获取响应超时时间
-	responseTimeout := defaultAsyncResponseTimeout
-	if config.AppConfig != nil {
-		responseTimeout = config.AppConfig.AsyncResponseTimeoutDur
-	}
+	// 获取响应超时时间（可被运行时覆盖）
+	responseTimeout := EffectiveResponseTimeout(p.name)
 
 	// 等待响应超时或结果
 	select {
 	case results := <-resultChan:
+		detach()
 		close(doneChan)
 		return results, nil
 	case err := <-errorChan:
+		detach()
 		close(doneChan)
 		return nil, err
+	case <-ctx.Done():
+		// 请求已取消，前台HTTP请求随之中止，不再等待结果
+		close(doneChan)
+		return nil, ctx.Err()
 	case <-time.After(responseTimeout):
 		// 插件响应超时，后台继续处理（优化完成，日志简化）
+		// 与请求上下文脱离，确保请求结束后后台仍能完成缓存更新
+		detach()
 
 		// 响应超时，返回空结果，后台继续处理
 		go func() {
@@ -795,7 +899,7 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 		})
 
 		// 🔧 修复：4秒超时时也要更新主缓存，标记为部分结果（空结果）
-		p.updateMainCacheWithFinal(mainCacheKey, []model.SearchResult{}, false)
+		p.updateMainCacheWithFinal(call, []model.SearchResult{}, false)
 
 		// fmt.Printf("[%s] 响应超时，后台继续处理: %s\n", p.name, pluginSpecificCacheKey)
 		return []model.SearchResult{}, nil
@@ -816,8 +920,16 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 
 	now := time.Now()
 
-	// 修改缓存键，确保包含插件名称
-	pluginSpecificCacheKey := fmt.Sprintf("%s:%s", p.name, keyword)
+	// 本次调用的主缓存键和关键词：优先使用外层调用经ext传入的值，而不是插件单例上的字段
+	call := resolveSearchCall(keyword, mainCacheKey, ext)
+	ext = withSearchCall(ext, call)
+
+	// 由Service层通过ext传入的搜索上下文，后台任务使用移除上下文后的ext
+	ctx := SearchContextFromExt(ext)
+	backgroundExt := WithoutSearchContext(ext)
+
+	// 修改缓存键，确保包含插件名称和插件声明的ext参数
+	pluginSpecificCacheKey := p.pluginCacheKey(keyword, ext)
 	forceRefresh := ext != nil && ext["refresh"] == true
 
 	// 检查缓存
@@ -832,7 +944,7 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 
 				// 如果缓存接近过期（已用时间超过TTL的80%），在后台刷新缓存
 				if time.Since(cachedResult.Timestamp) > (p.cacheTTL * 4 / 5) {
-					go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, mainCacheKey, ext)
+					go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, call, backgroundExt)
 				}
 
 				return model.PluginSearchResult{
@@ -852,7 +964,7 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 				// 标记为部分过期
 				if time.Since(cachedResult.Timestamp) >= p.cacheTTL {
 					// 在后台刷新缓存
-					go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, mainCacheKey, ext)
+					go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, call, backgroundExt)
 				}
 
 				return model.PluginSearchResult{
@@ -868,6 +980,10 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 
 	recordCacheMiss()
 
+	// 前台搜索上下文：响应前请求取消则中止HTTP请求，响应后与请求脱离
+	searchCtx, detach, cancel := detachableContext(ctx)
+	searchExt := WithSearchContext(backgroundExt, searchCtx)
+
 	// 创建通道
 	resultChan := make(chan []model.SearchResult, 1)
 	errorChan := make(chan error, 1)
@@ -875,6 +991,7 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 
 	// 启动后台处理
 	go func() {
+		defer cancel()
 		defer func() {
 			select {
 			case <-doneChan:
@@ -886,7 +1003,7 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		// 尝试获取工作槽
 		if !acquireWorkerSlot() {
 			// 工作池已满，使用快速响应客户端直接处理
-			results, err := searchFunc(p.client, keyword, ext)
+			results, err := searchFunc(clientWithContext(p.client, searchCtx), keyword, searchExt)
 			if err != nil {
 				select {
 				case errorChan <- err:
@@ -904,7 +1021,7 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		defer releaseWorkerSlot()
 
 		// 使用长超时客户端进行搜索
-		results, err := searchFunc(p.backgroundClient, keyword, ext)
+		results, err := searchFunc(clientWithContext(p.backgroundClient, searchCtx), keyword, searchExt)
 		if err != nil {
 			select {
 			case errorChan <- err:
@@ -918,15 +1035,13 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		}
 	}()
 
-	// 等待结果或超时
-	responseTimeout := defaultAsyncResponseTimeout
-	if config.AppConfig != nil {
-		responseTimeout = config.AppConfig.AsyncResponseTimeoutDur
-	}
+	// 等待结果或超时（可被运行时覆盖）
+	responseTimeout := EffectiveResponseTimeout(p.name)
 
 	select {
 	case results := <-resultChan:
 		// 不直接关闭，让defer处理
+		detach()
 
 		// 缓存结果
 		apiResponseCache.Store(pluginSpecificCacheKey, cachedResponse{
@@ -939,10 +1054,10 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 
 		// 🔧 恢复主缓存更新：使用统一的GOB序列化
 		// 传递原始数据，由主程序负责序列化
-		if mainCacheKey != "" && p.mainCacheUpdater != nil {
-			err := p.mainCacheUpdater(mainCacheKey, results, p.cacheTTL, true, p.currentKeyword)
+		if call.mainCacheKey != "" && p.mainCacheUpdater != nil {
+			err := p.mainCacheUpdater(call.mainCacheKey, results, p.cacheTTL, true, call.keyword)
 			if err != nil {
-				fmt.Printf("❌ [%s] 及时完成缓存更新失败: %s | 错误: %v\n", p.name, mainCacheKey, err)
+				fmt.Printf("❌ [%s] 及时完成缓存更新失败: %s | 错误: %v\n", p.name, call.mainCacheKey, err)
 			}
 		}
 
@@ -956,11 +1071,17 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 
 	case err := <-errorChan:
 		// 不直接关闭，让defer处理
+		detach()
 		return model.PluginSearchResult{}, err
 
+	case <-ctx.Done():
+		// 请求已取消，前台HTTP请求随之中止，不再启动后台补全
+		return model.PluginSearchResult{}, ctx.Err()
+
 	case <-time.After(responseTimeout):
-		// 🔥 超时处理：返回空结果，后台继续处理
-		go p.completeSearchInBackground(keyword, searchFunc, pluginSpecificCacheKey, mainCacheKey, doneChan, ext)
+		// 🔥 超时处理：返回空结果，后台继续处理（与请求上下文脱离）
+		detach()
+		go p.completeSearchInBackground(keyword, searchFunc, pluginSpecificCacheKey, call, doneChan, backgroundExt)
 
 		// 存储临时缓存（标记为不完整）
 		apiResponseCache.Store(pluginSpecificCacheKey, cachedResponse{
@@ -986,7 +1107,7 @@ func (p *BaseAsyncPlugin) completeSearchInBackground(
 	keyword string,
 	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
 	pluginCacheKey string,
-	mainCacheKey string,
+	call searchCall,
 	doneChan chan struct{},
 	ext map[string]interface{},
 ) {
@@ -1016,10 +1137,10 @@ func (p *BaseAsyncPlugin) completeSearchInBackground(
 
 	// 🔧 恢复主缓存更新：使用统一的GOB序列化
 	// 传递原始数据，由主程序负责序列化
-	if mainCacheKey != "" && p.mainCacheUpdater != nil {
-		err := p.mainCacheUpdater(mainCacheKey, results, p.cacheTTL, true, p.currentKeyword)
+	if call.mainCacheKey != "" && p.mainCacheUpdater != nil {
+		err := p.mainCacheUpdater(call.mainCacheKey, results, p.cacheTTL, true, call.keyword)
 		if err != nil {
-			fmt.Printf("❌ [%s] 后台完成缓存更新失败: %s | 错误: %v\n", p.name, mainCacheKey, err)
+			fmt.Printf("❌ [%s] 后台完成缓存更新失败: %s | 错误: %v\n", p.name, call.mainCacheKey, err)
 		}
 	}
 }
@@ -1030,7 +1151,7 @@ func (p *BaseAsyncPlugin) refreshCacheInBackground(
 	cacheKey string,
 	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
 	oldCache cachedResponse,
-	originalCacheKey string,
+	call searchCall,
 	ext map[string]interface{},
 ) {
 	// 确保ext不为nil
@@ -1082,7 +1203,7 @@ func (p *BaseAsyncPlugin) refreshCacheInBackground(
 	})
 
 	// 🔥 异步插件后台刷新完成时更新主缓存（标记为最终结果）
-	p.updateMainCacheWithFinal(originalCacheKey, mergedResults, true)
+	p.updateMainCacheWithFinal(call, mergedResults, true)
 
 	// 记录刷新时间
 	refreshTime := time.Since(refreshStart)
@@ -1097,12 +1218,14 @@ func (p *BaseAsyncPlugin) refreshCacheInBackground(
 // ============================================================
 
 // updateMainCache 更新主缓存系统（兼容性方法，默认IsFinal=true）
-func (p *BaseAsyncPlugin) updateMainCache(cacheKey string, results []model.SearchResult) {
-	p.updateMainCacheWithFinal(cacheKey, results, true)
+func (p *BaseAsyncPlugin) updateMainCache(call searchCall, results []model.SearchResult) {
+	p.updateMainCacheWithFinal(call, results, true)
 }
 
 // updateMainCacheWithFinal 更新主缓存系统，支持IsFinal参数
-func (p *BaseAsyncPlugin) updateMainCacheWithFinal(cacheKey string, results []model.SearchResult, isFinal bool) {
+func (p *BaseAsyncPlugin) updateMainCacheWithFinal(call searchCall, results []model.SearchResult, isFinal bool) {
+	cacheKey := call.mainCacheKey
+
 	// 如果主缓存更新函数为空或缓存键为空，直接返回
 	if p.mainCacheUpdater == nil || cacheKey == "" {
 		return
@@ -1115,9 +1238,9 @@ func (p *BaseAsyncPlugin) updateMainCacheWithFinal(cacheKey string, results []mo
 
 	// 🔥 增强防重复更新机制 - 使用数据哈希确保真正的去重
 	// 生成结果数据的简单哈希标识
-	dataHash := fmt.Sprintf("%d_%d", len(results), results[0].UniqueID)
+	dataHash := fmt.Sprintf("%d_%s", len(results), results[0].UniqueID)
 	if len(results) > 1 {
-		dataHash += fmt.Sprintf("_%d", results[len(results)-1].UniqueID)
+		dataHash += fmt.Sprintf("_%s", results[len(results)-1].UniqueID)
 	}
 	updateKey := fmt.Sprintf("final_%s_%s_%s_%t", p.name, cacheKey, dataHash, isFinal)
 
@@ -1132,7 +1255,7 @@ func (p *BaseAsyncPlugin) updateMainCacheWithFinal(cacheKey string, results []mo
 	// 🔧 恢复异步插件缓存更新，使用修复后的统一序列化
 	// 传递原始数据，由主程序负责GOB序列化
 	if p.mainCacheUpdater != nil {
-		err := p.mainCacheUpdater(cacheKey, results, p.cacheTTL, isFinal, p.currentKeyword)
+		err := p.mainCacheUpdater(cacheKey, results, p.cacheTTL, isFinal, call.keyword)
 		if err != nil {
 			fmt.Printf("❌ [%s] 主缓存更新失败: %s | 错误: %v\n", p.name, cacheKey, err)
 		}
@@ -0,0 +1,247 @@
+package plugin_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pansou/model"
+	"pansou/plugin"
+
+	// 请求全部经过框架传入的http.Client（Transport为nil，使用http.DefaultTransport）的插件。
+	// 其余插件不在此测试中：自建http.Client/Transport（如discourse的cloudscraper）的请求会绕过桩Transport访问外网，
+	// gying注册Web路由并读写本地账号文件，pansearch在包初始化时启动访问外网的后台协程，无法替换其Transport
+	_ "pansou/plugin/ash"
+	_ "pansou/plugin/bixin"
+	_ "pansou/plugin/cldi"
+	_ "pansou/plugin/clmao"
+	_ "pansou/plugin/cyg"
+	_ "pansou/plugin/haisou"
+	_ "pansou/plugin/hdr4k"
+	_ "pansou/plugin/hunhepan"
+	_ "pansou/plugin/javdb"
+	_ "pansou/plugin/jikepan"
+	_ "pansou/plugin/jutoushe"
+	_ "pansou/plugin/kkv"
+	_ "pansou/plugin/leijing"
+	_ "pansou/plugin/libvio"
+	_ "pansou/plugin/melost"
+	_ "pansou/plugin/miaoso"
+	_ "pansou/plugin/nsgame"
+	_ "pansou/plugin/pan666"
+	_ "pansou/plugin/panta"
+	_ "pansou/plugin/panwiki"
+	_ "pansou/plugin/pianku"
+	_ "pansou/plugin/qingying"
+	_ "pansou/plugin/quark4k"
+	_ "pansou/plugin/quarksoo"
+	_ "pansou/plugin/qupansou"
+	_ "pansou/plugin/sdso"
+	_ "pansou/plugin/sousou"
+	_ "pansou/plugin/susu"
+	_ "pansou/plugin/wuji"
+	_ "pansou/plugin/xdpan"
+	_ "pansou/plugin/xuexizhinan"
+	_ "pansou/plugin/yunso"
+)
+
+// 测试关键词的格式，桩Transport从请求的URL或请求体中识别关键词
+var raceKeywordPattern = regexp.MustCompile(`racekw\d+x(\d+)`)
+
+// stubTransport 模拟上游站点：根据请求中的关键词延迟响应，jikepan返回包含关键词的结果，其他站点返回空结果
+type stubTransport struct {
+	mu       sync.Mutex
+	requests int
+	inflight int
+	idleAt   time.Time // 最近一次没有进行中请求的时间
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.requests++
+	s.inflight++
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inflight--
+		if s.inflight == 0 {
+			s.idleAt = time.Now()
+		}
+		s.mu.Unlock()
+	}()
+
+	target := req.URL.String()
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		target += " " + string(body)
+	}
+	keyword := raceKeywordPattern.FindString(target)
+
+	// 根据关键词在立即完成、接近超时和超时后完成之间分布
+	if m := raceKeywordPattern.FindStringSubmatch(target); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		select {
+		case <-time.After(time.Duration(n%3) * 15 * time.Millisecond):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	body := "{}"
+	if req.URL.Host == "api.jikepan.xyz" && keyword != "" {
+		body = fmt.Sprintf(`{"msg":"success","list":[{"name":%q,"links":[{"service":"quark","link":"https://pan.quark.cn/s/%s"}]}]}`, keyword, keyword)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// waitIdle 等待没有进行中的请求且持续quiet时间，最多等待timeout
+func (s *stubTransport) waitIdle(quiet, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		idle := s.inflight == 0 && time.Since(s.idleAt) >= quiet
+		s.mu.Unlock()
+		if idle {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// raceRecorder 记录一次测试运行中各插件写入主缓存的次数和不一致的写入
+type raceRecorder struct {
+	runID      int64 // 本轮关键词的前缀，上一轮的后台补全写入不计入
+	mu         sync.Mutex
+	updates    map[string]int
+	mismatches []string
+}
+
+func (r *raceRecorder) record(pluginName, key string, results []model.SearchResult, keyword string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updates[pluginName]++
+	if key != raceMainCacheKey(keyword) {
+		r.mismatches = append(r.mismatches, fmt.Sprintf("%s: key %q written for keyword %q", pluginName, key, keyword))
+	}
+	for _, res := range results {
+		if !strings.Contains(res.Title, keyword) {
+			r.mismatches = append(r.mismatches, fmt.Sprintf("%s: result %q written for keyword %q", pluginName, res.Title, keyword))
+		}
+	}
+}
+
+var (
+	// 主缓存更新函数只设置一次：与生产环境相同，插件的后台补全读取它时不会被并发替换（-count大于1时上一轮的补全可能仍在运行）
+	raceUpdaterOnce sync.Once
+	currentRecorder atomic.Pointer[raceRecorder]
+)
+
+func raceMainCacheKey(keyword string) string {
+	return "main:" + keyword
+}
+
+// TestConcurrentKeywordsKeepMainCacheKeysIsolated 已注册的插件并发搜索不同关键词时，
+// 每个插件写入主缓存的键和结果必须与发起搜索的调用一致（配合 go test -race 运行）
+func TestConcurrentKeywordsKeepMainCacheKeysIsolated(t *testing.T) {
+	const keywordCount = 24
+
+	// 插件的客户端未设置Transport，请求经由http.DefaultTransport发出
+	// 测试结束时等待后台补全的请求全部完成后再恢复，避免后台请求访问外网
+	stub := &stubTransport{}
+	previousTransport := http.DefaultTransport
+	http.DefaultTransport = stub
+	t.Cleanup(func() {
+		stub.waitIdle(200*time.Millisecond, 5*time.Second)
+		http.DefaultTransport = previousTransport
+	})
+
+	plugins := plugin.GetRegisteredPlugins()
+	if len(plugins) == 0 {
+		t.Fatal("no registered plugins")
+	}
+
+	recorder := &raceRecorder{runID: time.Now().UnixNano(), updates: make(map[string]int)}
+	currentRecorder.Store(recorder)
+	raceUpdaterOnce.Do(func() {
+		for _, p := range plugins {
+			name := p.Name()
+			setter, ok := p.(interface {
+				SetMainCacheUpdater(func(string, []model.SearchResult, time.Duration, bool, string) error)
+			})
+			if !ok {
+				t.Fatalf("%s does not support main cache updates", name)
+			}
+			setter.SetMainCacheUpdater(func(key string, results []model.SearchResult, ttl time.Duration, isFinal bool, keyword string) error {
+				if r := currentRecorder.Load(); r != nil && strings.HasPrefix(keyword, fmt.Sprintf("racekw%dx", r.runID)) {
+					r.record(name, key, results, keyword)
+				}
+				return nil
+			})
+		}
+	})
+
+	for _, p := range plugins {
+		// 缩短响应超时，使部分搜索走后台补全路径
+		plugin.SetPluginOverride(p.Name(), plugin.PluginOverride{Timeout: 20 * time.Millisecond})
+		defer plugin.SetPluginOverride(p.Name(), plugin.PluginOverride{})
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range plugins {
+		for k := 0; k < keywordCount; k++ {
+			p := p
+			keyword := fmt.Sprintf("racekw%dx%d", recorder.runID, k)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// 与SearchService.searchPlugins相同的调用方式；桩站点的空响应可能被插件视为错误，这里只检查主缓存写入
+				_, _ = p.AsyncSearchWithContext(context.Background(), keyword, func(client *http.Client, kw string, extParams map[string]interface{}) ([]model.SearchResult, error) {
+					return p.Search(kw, extParams)
+				}, raceMainCacheKey(keyword), nil)
+			}()
+		}
+	}
+	wg.Wait()
+
+	// 等待后台补全写入主缓存
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		recorder.mu.Lock()
+		done := recorder.updates["jikepan"] >= keywordCount
+		recorder.mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	stub.mu.Lock()
+	requests := stub.requests
+	stub.mu.Unlock()
+	if requests == 0 {
+		t.Fatal("插件请求没有经过桩Transport")
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.updates["jikepan"] == 0 {
+		t.Fatal("no main cache updates recorded for jikepan")
+	}
+	for _, m := range recorder.mismatches {
+		t.Error(m)
+	}
+}
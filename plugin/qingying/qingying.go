@@ -1,7 +1,6 @@
 package qingying
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -116,7 +115,7 @@ func (p *QingYingPlugin) filterItemsByKeyword(items []searchItem, keyword string
 
 func (p *QingYingPlugin) fetchSearchResults(searchURL string, client *http.Client) ([]searchItem, error) {
 	debugPrintf("🌐 请求搜索页面: %s\n", searchURL)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -211,7 +210,7 @@ func (p *QingYingPlugin) processDetailPages(items []searchItem, client *http.Cli
 
 func (p *QingYingPlugin) processDetailPage(item searchItem, client *http.Client) *model.SearchResult {
 	debugPrintf("🎬 处理详情页: %s (ID: %s)\n", item.Title, item.ID)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", item.DetailURL, nil)
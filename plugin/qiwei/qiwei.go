@@ -1,7 +1,6 @@
 package qiwei
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -505,7 +504,7 @@ func (p *QiweiPlugin) detailURLCandidates(detailURL string) []string {
 }
 
 func (p *QiweiPlugin) fetchBody(client *http.Client, requestURL, referer string, timeout time.Duration) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, timeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
@@ -516,6 +516,17 @@ func init() {
 	plugin.RegisterGlobalPlugin(p)
 }
 
+// Metadata 插件元数据
+func (p *QQPDPlugin) Metadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName:   "QQ频道",
+		Description:   "QQ频道 - 登录后检索指定频道帖子中的网盘链接",
+		Homepage:      "https://pd.qq.com",
+		Categories:    []string{plugin.CategoryVideo},
+		RequiresLogin: true,
+	}
+}
+
 // Initialize 实现 InitializablePlugin 接口，延迟初始化插件
 func (p *QQPDPlugin) Initialize() error {
 	if p.initialized {
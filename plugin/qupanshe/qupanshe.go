@@ -2,7 +2,6 @@ package qupanshe
 
 import (
 	"compress/gzip"
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -139,7 +138,7 @@ func (p *QupanshePlugin) createSessionClient(baseClient *http.Client) (*http.Cli
 
 // getFormhash 从首页获取真实的formhash值
 func (p *QupanshePlugin) getFormhash(client *http.Client) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 15*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", BaseURL, nil)
@@ -226,7 +225,7 @@ func (p *QupanshePlugin) postSearchRequest(client *http.Client, keyword, formhas
 	data.Set("srchtxt", keyword)
 	data.Set("searchsubmit", "yes")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 15*time.Second)
 	defer cancel()
 
 	postData := data.Encode()
@@ -310,7 +309,7 @@ func (p *QupanshePlugin) postSearchRequest(client *http.Client, keyword, formhas
 
 // getSearchResults 获取搜索结果
 func (p *QupanshePlugin) getSearchResults(client *http.Client, searchURL, keyword string) ([]model.SearchResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -1,7 +1,6 @@
 package sdso
 
 import (
-	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/base64"
@@ -88,6 +87,11 @@ func init() {
 	plugin.RegisterGlobalPlugin(p)
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *SDSOPlugin) CacheExtKeys() []string {
+	return []string{"pages_per_type", "pages"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *SDSOPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -229,7 +233,7 @@ func (p *SDSOPlugin) fetchSinglePageWithType(client *http.Client, keyword string
 	}
 
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	// 3. 创建请求对象
@@ -0,0 +1,218 @@
+package plugin
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"pansou/model"
+)
+
+// ============================================================
+// 搜索上下文：将请求的取消信号传递到插件的前台HTTP请求
+// ============================================================
+
+// ExtKeySearchContext ext中保存搜索上下文的保留键（由Service层设置，插件无需关心）
+const ExtKeySearchContext = "__search_context"
+
+// WithSearchContext 返回携带搜索上下文的ext副本（不修改原ext，避免并发写入共享的ext）
+func WithSearchContext(ext map[string]interface{}, ctx context.Context) map[string]interface{} {
+	copied := make(map[string]interface{}, len(ext)+1)
+	for k, v := range ext {
+		copied[k] = v
+	}
+	if ctx != nil {
+		copied[ExtKeySearchContext] = ctx
+	}
+	return copied
+}
+
+// WithoutSearchContext 返回移除搜索上下文的ext，用于后台任务显式脱离请求生命周期
+func WithoutSearchContext(ext map[string]interface{}) map[string]interface{} {
+	if _, ok := ext[ExtKeySearchContext]; !ok {
+		return ext
+	}
+	copied := make(map[string]interface{}, len(ext))
+	for k, v := range ext {
+		if k != ExtKeySearchContext {
+			copied[k] = v
+		}
+	}
+	return copied
+}
+
+// SearchContextFromExt 从ext中获取搜索上下文，不存在时返回context.Background()
+// 不使用传入http.Client的插件可以用它构造请求，以便在请求取消时及时停止
+func SearchContextFromExt(ext map[string]interface{}) context.Context {
+	if ext != nil {
+		if ctx, ok := ext[ExtKeySearchContext].(context.Context); ok && ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// SearchTimeoutContext 基于ext中的搜索上下文创建带超时的上下文
+// 插件代替plugin.SearchTimeoutContext(ext, timeout)使用，请求取消时前台HTTP请求随之停止
+func SearchTimeoutContext(ext map[string]interface{}, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(SearchContextFromExt(ext), timeout)
+}
+
+// ClientContext 返回与http.Client绑定的搜索上下文，未绑定时返回context.Background()
+// 只接收http.Client的辅助函数（如详情页抓取）用它派生请求的上下文
+func ClientContext(client *http.Client) context.Context {
+	if client != nil {
+		if t, ok := client.Transport.(*contextTransport); ok {
+			return t.ctx
+		}
+	}
+	return context.Background()
+}
+
+// ClientTimeoutContext 基于与http.Client绑定的搜索上下文创建带超时的上下文
+func ClientTimeoutContext(client *http.Client, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ClientContext(client), timeout)
+}
+
+// SearchWithContext 使用指定上下文调用插件的Search方法
+// ctx取消时，插件通过传入的http.Client发出的前台请求会被中止
+func SearchWithContext(ctx context.Context, p AsyncSearchPlugin, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	return p.Search(keyword, WithSearchContext(ext, ctx))
+}
+
+// detachableContext 创建可分离的前台搜索上下文
+// 调用detach之前，parent取消会同时取消返回的ctx；调用detach之后，返回的ctx与parent脱离，
+// 后台缓存补全不再受请求取消的影响。cancel用于在搜索结束后释放资源。
+func detachableContext(parent context.Context) (ctx context.Context, detach func() bool, cancel context.CancelFunc) {
+	ctx, cancel = context.WithCancel(context.Background())
+	detach = context.AfterFunc(parent, cancel)
+	return ctx, detach, cancel
+}
+
+// clientWithContext 返回绑定了ctx的http.Client副本，ctx取消时中止该客户端发出的所有请求
+func clientWithContext(client *http.Client, ctx context.Context) *http.Client {
+	if client == nil || ctx == nil || ctx.Done() == nil {
+		return client
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	bound := *client
+	bound.Transport = &contextTransport{base: base, ctx: ctx}
+	return &bound
+}
+
+// contextTransport 将搜索上下文合并到每个请求的上下文中
+type contextTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+
+	resp, err := t.base.RoundTrip(req.WithContext(reqCtx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// 响应体读取完毕关闭时才释放上下文，否则会中断正在读取的响应体
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// cancelOnCloseBody 关闭响应体时释放请求上下文
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close 关闭响应体并释放请求上下文
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// ============================================================
+// 单次搜索调用状态：主缓存键和关键词随调用传递，不保存在插件单例上
+// ============================================================
+
+// extKeySearchCall ext中保存单次搜索调用状态的保留键
+const extKeySearchCall = "__search_call"
+
+// searchCall 单次搜索调用的状态
+// 插件是全局单例，若保存在插件字段上，并发的不同关键词搜索会互相覆盖主缓存键
+type searchCall struct {
+	keyword      string // 原始搜索关键词，传给主缓存更新函数
+	mainCacheKey string // 主缓存键，后台完成时写入该键
+}
+
+// resolveSearchCall 确定本次调用的状态
+// 外层AsyncSearchWithContext已通过ext传入时优先使用，插件内部传入的p.MainCacheKey仅作兜底
+func resolveSearchCall(keyword, mainCacheKey string, ext map[string]interface{}) searchCall {
+	if call, ok := ext[extKeySearchCall].(searchCall); ok {
+		return call
+	}
+	return searchCall{keyword: keyword, mainCacheKey: mainCacheKey}
+}
+
+// withSearchCall 返回携带调用状态的ext副本
+func withSearchCall(ext map[string]interface{}, call searchCall) map[string]interface{} {
+	copied := make(map[string]interface{}, len(ext)+1)
+	for k, v := range ext {
+		copied[k] = v
+	}
+	copied[extKeySearchCall] = call
+	return copied
+}
+
+// ============================================================
+// 保留键：只能由Service层和插件框架设置
+// ============================================================
+
+// reservedExtKeys ext中由服务端设置的保留键
+var reservedExtKeys = []string{ExtKeySearchContext, extKeySearchCall}
+
+// StripReservedExt 返回去掉保留键的ext，ext为nil时返回空map
+// 客户端传入的ext在计算缓存键和交给插件之前调用，避免伪造的搜索上下文或主缓存键被插件使用
+func StripReservedExt(ext map[string]interface{}) map[string]interface{} {
+	if ext == nil {
+		return make(map[string]interface{})
+	}
+
+	reserved := false
+	for _, key := range reservedExtKeys {
+		if _, ok := ext[key]; ok {
+			reserved = true
+			break
+		}
+	}
+	if !reserved {
+		return ext
+	}
+
+	copied := make(map[string]interface{}, len(ext))
+	for k, v := range ext {
+		copied[k] = v
+	}
+	for _, key := range reservedExtKeys {
+		delete(copied, key)
+	}
+	return copied
+}
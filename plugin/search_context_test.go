@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSearchTimeoutContext(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	ext := WithSearchContext(map[string]interface{}{"title_en": "x"}, parent)
+
+	ctx, cancel := SearchTimeoutContext(ext, time.Minute)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("SearchTimeoutContext() 应设置超时")
+	}
+
+	cancelParent()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("搜索上下文取消后派生的上下文应随之取消")
+	}
+
+	// 没有搜索上下文（后台补全）时只受超时限制
+	ctx, cancel = SearchTimeoutContext(nil, time.Minute)
+	defer cancel()
+	if ctx.Err() != nil {
+		t.Errorf("ctx.Err() = %v, want nil", ctx.Err())
+	}
+}
+
+func TestClientContext(t *testing.T) {
+	if got := ClientContext(nil); got != context.Background() {
+		t.Error("ClientContext(nil) 应返回context.Background()")
+	}
+	plain := &http.Client{}
+	if got := ClientContext(plain); got != context.Background() {
+		t.Error("未绑定上下文的客户端应返回context.Background()")
+	}
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	bound := clientWithContext(plain, parent)
+	if got := ClientContext(bound); got != parent {
+		t.Error("ClientContext() 应返回绑定的搜索上下文")
+	}
+
+	ctx, cancel := ClientTimeoutContext(bound, time.Minute)
+	defer cancel()
+	cancelParent()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("搜索上下文取消后派生的上下文应随之取消")
+	}
+}
+
+func TestStripReservedExt(t *testing.T) {
+	if got := StripReservedExt(nil); got == nil || len(got) != 0 {
+		t.Errorf("StripReservedExt(nil) = %v, want empty map", got)
+	}
+
+	ext := map[string]interface{}{"title_en": "x"}
+	if got := StripReservedExt(ext); len(got) != 1 || got["title_en"] != "x" {
+		t.Errorf("StripReservedExt() = %v", got)
+	}
+
+	forged := map[string]interface{}{
+		"title_en":          "x",
+		ExtKeySearchContext: "forged",
+		extKeySearchCall:    searchCall{keyword: "other", mainCacheKey: "victim"},
+	}
+	got := StripReservedExt(forged)
+	if len(got) != 1 || got["title_en"] != "x" {
+		t.Errorf("StripReservedExt() = %v, want only title_en", got)
+	}
+	// 不修改调用方的map
+	if len(forged) != 3 {
+		t.Errorf("原ext被修改: %v", forged)
+	}
+
+	// 去掉保留键后，框架使用本次调用的状态
+	call := resolveSearchCall("kw", "key", got)
+	if call.keyword != "kw" || call.mainCacheKey != "key" {
+		t.Errorf("resolveSearchCall() = %+v", call)
+	}
+}
@@ -1,7 +1,6 @@
 package shandian
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -121,7 +120,7 @@ func (p *ShandianAsyncPlugin) searchImpl(client *http.Client, keyword string, ex
 	searchURL := fmt.Sprintf("http://1.95.79.193/index.php/vod/search/wd/%s.html", url.QueryEscape(keyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -356,7 +355,7 @@ func (p *ShandianAsyncPlugin) fetchDetailLinks(client *http.Client, itemID strin
 	detailURL := fmt.Sprintf("http://1.95.79.193/index.php/vod/detail/id/%s.html", itemID)
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DetailTimeout)
 	defer cancel()
 	
 	// 创建请求
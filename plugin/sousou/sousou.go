@@ -1,7 +1,6 @@
 package sousou
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"log"
@@ -175,7 +174,7 @@ func (p *SousouAsyncPlugin) searchByType(client *http.Client, keyword string, di
 			debugLog("请求URL (page %d, type %s): %s", pageNum, diskType, apiURL)
 
 			// 创建带超时的上下文
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 			defer cancel()
 
 			// 创建请求
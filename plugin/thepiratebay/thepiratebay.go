@@ -1,7 +1,6 @@
 package thepiratebay
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -128,6 +127,11 @@ func startCacheCleaner() {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *ThePirateBayPlugin) CacheExtKeys() []string {
+	return []string{"title_en"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *ThePirateBayPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -242,7 +246,7 @@ func (p *ThePirateBayPlugin) searchPage(client *http.Client, encodedKeyword stri
 	}
 	
 	// 3. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DefaultTimeout)
 	defer cancel()
 	
 	// 4. 创建请求
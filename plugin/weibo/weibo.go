@@ -479,6 +479,17 @@ func (p *WeiboPlugin) SkipServiceFilter() bool {
 	return true
 }
 
+// Metadata 插件元数据
+func (p *WeiboPlugin) Metadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName:   "微博",
+		Description:   "微博 - 登录后检索指定博主微博及评论中的网盘链接",
+		Homepage:      "https://weibo.com",
+		Categories:    []string{plugin.CategoryVideo},
+		RequiresLogin: true,
+	}
+}
+
 func (p *WeiboPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
 	if err != nil {
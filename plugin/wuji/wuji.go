@@ -1,7 +1,6 @@
 package wuji
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -83,6 +82,11 @@ func (p *WujiPlugin) Description() string {
 	return "ØMagnet 无极磁链 - 磁力链接搜索引擎"
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *WujiPlugin) CacheExtKeys() []string {
+	return []string{"search"}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *WujiPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -173,7 +177,7 @@ func (p *WujiPlugin) searchPage(client *http.Client, keyword string, page int) (
 	searchURL := fmt.Sprintf(SearchURL, encodedKeyword, page)
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), TimeoutSeconds*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, TimeoutSeconds*time.Second)
 	defer cancel()
 	
 	// 创建请求
@@ -299,7 +303,7 @@ func (p *WujiPlugin) fetchMagnetLink(client *http.Client, detailURL string) (str
 		}
 	}
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), TimeoutSeconds*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, TimeoutSeconds*time.Second)
 	defer cancel()
 	
 	// 创建请求
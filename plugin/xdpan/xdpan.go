@@ -1,7 +1,6 @@
 package xdpan
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -90,7 +89,7 @@ func (p *XdpanPlugin) fetchSearchResults(client *http.Client, keyword string) ([
 	// 构建搜索URL（只获取第一页）
 	searchURL := fmt.Sprintf("%s/search?page=1&k=%s", BaseURL, url.QueryEscape(keyword))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -279,7 +278,7 @@ func (p *XdpanPlugin) fetchDetailPageLinks(client *http.Client, detailURL string
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
@@ -2,7 +2,6 @@ package xdyh
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -127,7 +126,7 @@ func (p *XdyhAsyncPlugin) searchImpl(client *http.Client, keyword string, ext ma
 	}
 	
 	// 4. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 5. 创建请求
@@ -120,7 +120,7 @@ func (p *XiaojiAsyncPlugin) searchImpl(client *http.Client, keyword string, ext
 	searchURL := fmt.Sprintf("%s/?s=%s", baseURL, encodedKeyword)
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -151,7 +151,7 @@ func (p *XiaojiAsyncPlugin) searchImpl(client *http.Client, keyword string, ext
 	}
 	
 	// 8. 解析搜索结果
-	results := p.parseSearchResults(doc, keyword)
+	results := p.parseSearchResults(plugin.SearchContextFromExt(ext), doc, keyword)
 	
 	// 9. 关键词过滤
 	return plugin.FilterResultsByKeyword(results, keyword), nil
@@ -198,12 +198,12 @@ func (p *XiaojiAsyncPlugin) doRequestWithRetry(req *http.Request, client *http.C
 }
 
 // parseSearchResults 解析搜索结果
-func (p *XiaojiAsyncPlugin) parseSearchResults(doc *goquery.Document, keyword string) []model.SearchResult {
+func (p *XiaojiAsyncPlugin) parseSearchResults(ctx context.Context, doc *goquery.Document, keyword string) []model.SearchResult {
 	results := make([]model.SearchResult, 0)
 	
 	// 查找所有搜索结果项
 	doc.Find("article.poster-item").Each(func(i int, s *goquery.Selection) {
-		result := p.parseSearchResultItem(s, keyword)
+		result := p.parseSearchResultItem(ctx, s, keyword)
 		if result != nil {
 			results = append(results, *result)
 		}
@@ -213,7 +213,7 @@ func (p *XiaojiAsyncPlugin) parseSearchResults(doc *goquery.Document, keyword st
 }
 
 // parseSearchResultItem 解析单个搜索结果项
-func (p *XiaojiAsyncPlugin) parseSearchResultItem(s *goquery.Selection, keyword string) *model.SearchResult {
+func (p *XiaojiAsyncPlugin) parseSearchResultItem(ctx context.Context, s *goquery.Selection, keyword string) *model.SearchResult {
 	// 1. 提取详情页链接
 	detailLink, exists := s.Find(".poster-link").Attr("href")
 	if !exists || detailLink == "" {
@@ -266,7 +266,7 @@ func (p *XiaojiAsyncPlugin) parseSearchResultItem(s *goquery.Selection, keyword
 	}
 	
 	// 10. 获取详情页的下载链接
-	links := p.fetchDetailPageLinks(detailLink)
+	links := p.fetchDetailPageLinks(ctx, detailLink)
 	
 	// 11. 创建搜索结果
 	result := &model.SearchResult{
@@ -288,7 +288,7 @@ func (p *XiaojiAsyncPlugin) parseSearchResultItem(s *goquery.Selection, keyword
 }
 
 // fetchDetailPageLinks 获取详情页的下载链接
-func (p *XiaojiAsyncPlugin) fetchDetailPageLinks(detailURL string) []model.Link {
+func (p *XiaojiAsyncPlugin) fetchDetailPageLinks(ctx context.Context, detailURL string) []model.Link {
 	// 1. 检查缓存
 	if cached, ok := detailCache.Load(detailURL); ok {
 		if links, ok := cached.([]model.Link); ok {
@@ -297,7 +297,7 @@ func (p *XiaojiAsyncPlugin) fetchDetailPageLinks(detailURL string) []model.Link
 	}
 	
 	// 2. 创建请求
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := context.WithTimeout(ctx, DetailTimeout)
 	defer cancel()
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
@@ -1,7 +1,6 @@
 package xinjuc
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -120,7 +119,7 @@ func (p *XinjucPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	searchURL := fmt.Sprintf("%s/?s=%s", SiteURL, url.QueryEscape(keyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, DefaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -343,7 +342,7 @@ type DetailCacheData struct {
 // fetchDetailPage 获取详情页信息
 func (p *XinjucPlugin) fetchDetailPage(client *http.Client, detailURL string) ([]model.Link, string) {
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), DetailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, DetailTimeout)
 	defer cancel()
 	
 	// 创建请求
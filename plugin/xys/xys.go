@@ -1,7 +1,6 @@
 package xys
 
 import (
-	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -141,7 +140,7 @@ func (p *XysPlugin) getToken(client *http.Client, keyword string) (string, error
 		BaseURL, TokenPath, url.QueryEscape(keyword))
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", tokenURL, nil)
@@ -240,7 +239,7 @@ func (p *XysPlugin) executeSearch(client *http.Client, token, keyword string) ([
 		BaseURL, SearchPath, token, url.QueryEscape(keyword))
 
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.ClientTimeoutContext(client, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "POST", searchURL, nil)
@@ -1,7 +1,6 @@
 package yiove
 
 import (
-	"context"
 	"fmt"
 	"hash/crc32"
 	"net/http"
@@ -215,7 +214,7 @@ func (p *YiovePlugin) fetchSearchResults(client *http.Client, keyword string, de
 	searchURL := fmt.Sprintf(searchPathFormat, encodeKeyword(keyword))
 	logDebug(debug, "[%s] 搜索URL=%s", p.Name(), searchURL)
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, requestTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -294,7 +293,7 @@ type detailPayload struct {
 
 func (p *YiovePlugin) fetchDetail(client *http.Client, detailURL string, debug bool) (detailPayload, error) {
 	logDebug(debug, "[%s] 抓取详情 URL=%s", p.Name(), detailURL)
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
@@ -1,7 +1,6 @@
 package ypfxw
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -123,7 +122,7 @@ func (p *YpfxwPlugin) searchImpl(client *http.Client, keyword string, ext map[st
 	}
 
 	searchURL := fmt.Sprintf("https://ypfxw.com/search.php?q=%s", url.QueryEscape(keyword))
-	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, searchTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -258,7 +257,7 @@ func (p *YpfxwPlugin) fetchDetailLinks(client *http.Client, detailURL, articleID
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, detailURL, nil)
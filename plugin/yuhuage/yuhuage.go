@@ -1,7 +1,6 @@
 package yuhuage
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"log"
@@ -77,7 +76,7 @@ func (p *YuhuagePlugin) searchImpl(client *http.Client, keyword string, ext map[
 	searchURL := fmt.Sprintf("%s%s%s-%d-time.html", BaseURL, SearchPath, encodedQuery, 1)
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, 30*time.Second)
 	defer cancel()
 	
 	// 创建请求对象
@@ -112,6 +112,11 @@ func newOptimizedHTTPClient() *http.Client {
 	}
 }
 
+// CacheExtKeys 返回影响搜索结果的ext参数
+func (p *YulinshufaPlugin) CacheExtKeys() []string {
+	return []string{"title_en"}
+}
+
 // Search 兼容方法
 func (p *YulinshufaPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -149,7 +154,7 @@ func (p *YulinshufaPlugin) searchImpl(client *http.Client, keyword string, ext m
 	}
 	p.debugf("搜索解析到 %d 条候选结果", len(items))
 
-	results := p.fetchDetailResults(items)
+	results := p.fetchDetailResults(plugin.SearchContextFromExt(ext), items)
 	validResults := make([]model.SearchResult, 0, len(results))
 	for _, res := range results {
 		if len(res.Links) == 0 {
@@ -170,7 +175,7 @@ func (p *YulinshufaPlugin) fetchSearchItems(client *http.Client, keyword string)
 		return nil, fmt.Errorf("[%s] GBK 编码搜索关键词失败: %w", p.Name(), err)
 	}
 	searchURL := fmt.Sprintf(baseURL+searchPath, encodedKeyword)
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, requestTimeout)
 	defer cancel()
 	p.debugf("请求搜索URL: %s", searchURL)
 
@@ -268,7 +273,7 @@ func (p *YulinshufaPlugin) parseSearchItems(doc *goquery.Document) []searchItem
 }
 
 // fetchDetailResults 并发抓取详情页
-func (p *YulinshufaPlugin) fetchDetailResults(items []searchItem) []model.SearchResult {
+func (p *YulinshufaPlugin) fetchDetailResults(ctx context.Context, items []searchItem) []model.SearchResult {
 	results := make([]model.SearchResult, 0, len(items))
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -282,7 +287,7 @@ func (p *YulinshufaPlugin) fetchDetailResults(items []searchItem) []model.Search
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			if detail, err := p.getDetailResult(itemCopy); err == nil && detail != nil {
+			if detail, err := p.getDetailResult(ctx, itemCopy); err == nil && detail != nil {
 				mu.Lock()
 				results = append(results, *detail)
 				mu.Unlock()
@@ -297,14 +302,14 @@ func (p *YulinshufaPlugin) fetchDetailResults(items []searchItem) []model.Search
 	return results
 }
 
-func (p *YulinshufaPlugin) getDetailResult(item searchItem) (*model.SearchResult, error) {
+func (p *YulinshufaPlugin) getDetailResult(ctx context.Context, item searchItem) (*model.SearchResult, error) {
 	cacheKey := item.DetailURL
 	if cached, ok := p.loadFromCache(cacheKey); ok {
 		p.debugf("详情缓存命中: %s", cacheKey)
 		return &cached, nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), detailTimeout)
+	ctx, cancel := context.WithTimeout(ctx, detailTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, item.DetailURL, nil)
@@ -1,7 +1,6 @@
 package yunso
 
 import (
-	"context"
 	"encoding/base64"
 	"fmt"
 	"html"
@@ -138,7 +137,7 @@ func (p *YunsoAsyncPlugin) doSearch(client *http.Client, keyword string, ext map
 }
 
 func (p *YunsoAsyncPlugin) searchPage(client *http.Client, keyword string, page int) ([]YunsoItem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), yunsoDefaultTimeout)
+	ctx, cancel := plugin.ClientTimeoutContext(client, yunsoDefaultTimeout)
 	defer cancel()
 
 	params := url.Values{}
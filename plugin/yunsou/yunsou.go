@@ -1,7 +1,6 @@
 package yunsou
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -124,7 +123,7 @@ func (p *YunsouAsyncPlugin) searchImpl(client *http.Client, keyword string, ext
 	searchURL := fmt.Sprintf(searchURLTemplate, url.QueryEscape(keyword))
 	
 	// 2. 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	ctx, cancel := plugin.SearchTimeoutContext(ext, defaultTimeout)
 	defer cancel()
 	
 	// 3. 创建请求
@@ -59,7 +59,8 @@ func (p *ZXZJPlugin) SearchWithResult(keyword string, ext map[string]interface{}
 func (p *ZXZJPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	searchURL := fmt.Sprintf("%s%s?wd=%s&submit=", baseURL, searchPath, url.QueryEscape(keyword))
 	
-	items, err := p.fetchSearchResults(searchURL)
+	ctx := plugin.SearchContextFromExt(ext)
+	items, err := p.fetchSearchResults(ctx, searchURL)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +73,7 @@ func (p *ZXZJPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 		items = items[:maxResults]
 	}
 	
-	results := p.processDetailPages(items)
+	results := p.processDetailPages(ctx, items)
 	
 	return plugin.FilterResultsByKeyword(results, keyword), nil
 }
@@ -83,8 +84,8 @@ type searchItem struct {
 	DetailURL string
 }
 
-func (p *ZXZJPlugin) fetchSearchResults(searchURL string) ([]searchItem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (p *ZXZJPlugin) fetchSearchResults(ctx context.Context, searchURL string) ([]searchItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
@@ -141,7 +142,7 @@ func (p *ZXZJPlugin) fetchSearchResults(searchURL string) ([]searchItem, error)
 	return items, nil
 }
 
-func (p *ZXZJPlugin) processDetailPages(items []searchItem) []model.SearchResult {
+func (p *ZXZJPlugin) processDetailPages(ctx context.Context, items []searchItem) []model.SearchResult {
 	var results []model.SearchResult
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -154,7 +155,7 @@ func (p *ZXZJPlugin) processDetailPages(items []searchItem) []model.SearchResult
 			sem <- struct{}{}
 			defer func() { <-sem }()
 			
-			result := p.processDetailPage(it)
+			result := p.processDetailPage(ctx, it)
 			if result != nil {
 				mu.Lock()
 				results = append(results, *result)
@@ -167,11 +168,11 @@ func (p *ZXZJPlugin) processDetailPages(items []searchItem) []model.SearchResult
 	return results
 }
 
-func (p *ZXZJPlugin) processDetailPage(item searchItem) *model.SearchResult {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (p *ZXZJPlugin) processDetailPage(ctx context.Context, item searchItem) *model.SearchResult {
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 	
-	req, err := http.NewRequestWithContext(ctx, "GET", item.DetailURL, nil)
+	req, err := http.NewRequestWithContext(reqCtx, "GET", item.DetailURL, nil)
 	if err != nil {
 		return nil
 	}
@@ -223,7 +224,7 @@ func (p *ZXZJPlugin) processDetailPage(item searchItem) *model.SearchResult {
 		return nil
 	}
 	
-	links := p.fetchPanLinks(playLinks)
+	links := p.fetchPanLinks(ctx, playLinks)
 	if len(links) == 0 {
 		return nil
 	}
@@ -301,7 +302,7 @@ func (p *ZXZJPlugin) detectPanType(title string) string {
 	return ""
 }
 
-func (p *ZXZJPlugin) fetchPanLinks(playLinks []playLink) []model.Link {
+func (p *ZXZJPlugin) fetchPanLinks(ctx context.Context, playLinks []playLink) []model.Link {
 	var links []model.Link
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -314,7 +315,7 @@ func (p *ZXZJPlugin) fetchPanLinks(playLinks []playLink) []model.Link {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 			
-			link := p.fetchSinglePanLink(playLink)
+			link := p.fetchSinglePanLink(ctx, playLink)
 			if link != nil {
 				mu.Lock()
 				links = append(links, *link)
@@ -327,8 +328,8 @@ func (p *ZXZJPlugin) fetchPanLinks(playLinks []playLink) []model.Link {
 	return links
 }
 
-func (p *ZXZJPlugin) fetchSinglePanLink(pl playLink) *model.Link {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (p *ZXZJPlugin) fetchSinglePanLink(ctx context.Context, pl playLink) *model.Link {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", pl.URL, nil)
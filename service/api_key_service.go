@@ -0,0 +1,423 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"pansou/config"
+	"pansou/model"
+)
+
+const (
+	apiKeyBucketName    = "api_keys"
+	apiKeyFileName      = "api_keys.db"
+	apiKeyPrefix        = "psk_"           // 密钥前缀，用于区分API密钥和JWT
+	apiKeyFlushInterval = 30 * time.Second // 用量写回磁盘的间隔
+	apiKeyMaxNameLength = 64
+)
+
+var (
+	ErrAPIKeyNotFound      = errors.New("API密钥不存在")
+	ErrAPIKeyInvalid       = errors.New("API密钥无效")
+	ErrAPIKeyExpired       = errors.New("API密钥已过期")
+	ErrAPIKeyRevoked       = errors.New("API密钥已吊销")
+	ErrAPIKeyQuotaExceeded = errors.New("API密钥请求次数超出限额")
+	ErrAPIKeyStore         = errors.New("API密钥存储不可用")
+)
+
+// apiKeyRecord 持久化的API密钥，只保存密钥的SHA-256哈希
+type apiKeyRecord struct {
+	model.APIKey
+	Hash     string `json:"hash"`
+	UsageDay string `json:"usage_day,omitempty"` // UsedToday对应的日期
+}
+
+// apiKeyEntry 内存中的API密钥和用量计数
+type apiKeyEntry struct {
+	record      apiKeyRecord
+	minuteStart time.Time // 当前分钟窗口的开始时间
+	minuteCount int
+	dirty       bool // 用量已变化，尚未写回磁盘
+}
+
+// APIKeyService API密钥管理
+// 密钥以"psk_<id>_<secret>"的形式发给调用方，磁盘上只保存哈希；
+// 每分钟和每天的请求数在内存中计数，当天用量定期写回bbolt，重启后继续累计。
+type APIKeyService struct {
+	mu   sync.Mutex
+	keys map[string]*apiKeyEntry
+	db   *bolt.DB
+}
+
+// NewAPIKeyService 创建API密钥服务并加载已保存的密钥
+func NewAPIKeyService() *APIKeyService {
+	s := &APIKeyService{keys: make(map[string]*apiKeyEntry)}
+	s.openStore(filepath.Join(config.Get().CachePath, apiKeyFileName))
+	s.load()
+
+	go func() {
+		ticker := time.NewTicker(apiKeyFlushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.flushUsage()
+		}
+	}()
+	return s
+}
+
+func (s *APIKeyService) openStore(path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(apiKeyBucketName))
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return
+	}
+
+	s.db = db
+}
+
+// load 从磁盘加载全部密钥
+func (s *APIKeyService) load() {
+	if s.db == nil {
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(apiKeyBucketName))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var record apiKeyRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return nil
+			}
+			if record.UsageDay != today {
+				record.UsedToday = 0
+			}
+			s.keys[record.ID] = &apiKeyEntry{record: record}
+			return nil
+		})
+	})
+}
+
+// Create 创建API密钥，返回的明文密钥之后无法再次获取
+func (s *APIKeyService) Create(req model.APIKeyCreateRequest) (model.APIKeyCreateResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > apiKeyMaxNameLength {
+		return model.APIKeyCreateResponse{}, fmt.Errorf("name不能为空且不超过%d个字符", apiKeyMaxNameLength)
+	}
+	scopes, err := normalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		return model.APIKeyCreateResponse{}, err
+	}
+	if req.ExpiresInDays < 0 || req.PerMinute < 0 || req.PerDay < 0 {
+		return model.APIKeyCreateResponse{}, errors.New("expires_in_days、per_minute和per_day不能为负数")
+	}
+	if s.db == nil {
+		return model.APIKeyCreateResponse{}, ErrAPIKeyStore
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return model.APIKeyCreateResponse{}, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return model.APIKeyCreateResponse{}, err
+	}
+	key := apiKeyPrefix + id + "_" + secret
+
+	now := time.Now()
+	record := apiKeyRecord{
+		APIKey: model.APIKey{
+			ID:        id,
+			Name:      name,
+			Prefix:    key[:len(apiKeyPrefix)+len(id)+5],
+			Scopes:    scopes,
+			PerMinute: req.PerMinute,
+			PerDay:    req.PerDay,
+			CreatedAt: now.UnixMilli(),
+		},
+		Hash: hashSecret(key),
+	}
+	if req.ExpiresInDays > 0 {
+		record.ExpiresAt = now.AddDate(0, 0, req.ExpiresInDays).UnixMilli()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.save(record); err != nil {
+		return model.APIKeyCreateResponse{}, err
+	}
+	s.keys[id] = &apiKeyEntry{record: record}
+	return model.APIKeyCreateResponse{APIKey: record.APIKey, Key: key}, nil
+}
+
+// List 列出全部密钥（包括已过期和已吊销的），按创建时间倒序
+func (s *APIKeyService) List() []model.APIKey {
+	s.mu.Lock()
+	keys := make([]model.APIKey, 0, len(s.keys))
+	for _, entry := range s.keys {
+		keys = append(keys, entry.record.APIKey)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt > keys[j].CreatedAt
+	})
+	return keys
+}
+
+// Revoke 吊销密钥，吊销后的密钥保留在列表中但不能再使用
+func (s *APIKeyService) Revoke(id string) (model.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.keys[id]
+	if !ok {
+		return model.APIKey{}, ErrAPIKeyNotFound
+	}
+	if entry.record.RevokedAt == 0 {
+		record := entry.record
+		record.RevokedAt = time.Now().UnixMilli()
+		if err := s.save(record); err != nil {
+			return model.APIKey{}, err
+		}
+		entry.record = record
+		entry.dirty = false
+	}
+	return entry.record.APIKey, nil
+}
+
+// Authenticate 校验明文密钥，返回密钥的元数据
+func (s *APIKeyService) Authenticate(key string) (model.APIKey, error) {
+	id, ok := apiKeyID(key)
+	if !ok {
+		return model.APIKey{}, ErrAPIKeyInvalid
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.keys[id]
+	if !exists || subtle.ConstantTimeCompare([]byte(entry.record.Hash), []byte(hashSecret(key))) != 1 {
+		return model.APIKey{}, ErrAPIKeyInvalid
+	}
+	if entry.record.RevokedAt > 0 {
+		return model.APIKey{}, ErrAPIKeyRevoked
+	}
+	if entry.record.ExpiresAt > 0 && time.Now().UnixMilli() >= entry.record.ExpiresAt {
+		return model.APIKey{}, ErrAPIKeyExpired
+	}
+	return entry.record.APIKey, nil
+}
+
+// Consume 按每分钟和每天的限额计入一次请求，超出限额时返回ErrAPIKeyQuotaExceeded和建议的重试等待时间
+func (s *APIKeyService) Consume(id string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.keys[id]
+	if !ok {
+		return 0, ErrAPIKeyNotFound
+	}
+
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	if entry.record.UsageDay != today {
+		entry.record.UsageDay = today
+		entry.record.UsedToday = 0
+	}
+	minuteStart := now.Truncate(time.Minute)
+	if !entry.minuteStart.Equal(minuteStart) {
+		entry.minuteStart = minuteStart
+		entry.minuteCount = 0
+	}
+
+	if entry.record.PerDay > 0 && entry.record.UsedToday >= entry.record.PerDay {
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+		return tomorrow.Sub(now), ErrAPIKeyQuotaExceeded
+	}
+	if entry.record.PerMinute > 0 && entry.minuteCount >= entry.record.PerMinute {
+		return minuteStart.Add(time.Minute).Sub(now), ErrAPIKeyQuotaExceeded
+	}
+
+	entry.minuteCount++
+	entry.record.UsedToday++
+	entry.record.LastUsedAt = now.UnixMilli()
+	entry.dirty = true
+	return 0, nil
+}
+
+// apiKeyUsage 待写回磁盘的用量
+type apiKeyUsage struct {
+	id         string
+	usageDay   string
+	usedToday  int
+	lastUsedAt int64
+}
+
+// flushUsage 将变化的用量写回磁盘
+// 在锁内复制变化的用量，锁外用一个事务写入，磁盘写入期间不阻塞认证和计数
+func (s *APIKeyService) flushUsage() {
+	if s.db == nil {
+		return
+	}
+
+	s.mu.Lock()
+	var usages []apiKeyUsage
+	for id, entry := range s.keys {
+		if !entry.dirty {
+			continue
+		}
+		usages = append(usages, apiKeyUsage{
+			id:         id,
+			usageDay:   entry.record.UsageDay,
+			usedToday:  entry.record.UsedToday,
+			lastUsedAt: entry.record.LastUsedAt,
+		})
+		entry.dirty = false
+	}
+	s.mu.Unlock()
+
+	if len(usages) == 0 {
+		return
+	}
+
+	// 只更新磁盘记录的用量字段，不覆盖写入期间吊销等操作保存的记录
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(apiKeyBucketName))
+		if bucket == nil {
+			return ErrAPIKeyStore
+		}
+		for _, usage := range usages {
+			var record apiKeyRecord
+			raw := bucket.Get([]byte(usage.id))
+			if raw == nil || json.Unmarshal(raw, &record) != nil {
+				continue
+			}
+			record.UsageDay = usage.usageDay
+			record.UsedToday = usage.usedToday
+			record.LastUsedAt = usage.lastUsedAt
+			data, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(usage.id), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// 写入失败时重新标记，下次继续写回
+		s.mu.Lock()
+		for _, usage := range usages {
+			if entry, ok := s.keys[usage.id]; ok {
+				entry.dirty = true
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// save 写入一条密钥记录，调用方需持有锁
+func (s *APIKeyService) save(record apiKeyRecord) error {
+	if s.db == nil {
+		return ErrAPIKeyStore
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(apiKeyBucketName))
+		if bucket == nil {
+			return ErrAPIKeyStore
+		}
+		return bucket.Put([]byte(record.ID), raw)
+	})
+}
+
+// IsAPIKey 判断令牌是否为API密钥（而不是JWT）
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+// apiKeyID 从明文密钥中解析出ID
+func apiKeyID(key string) (string, bool) {
+	if !IsAPIKey(key) {
+		return "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(key, apiKeyPrefix), "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// normalizeAPIKeyScopes 校验并去重权限范围
+func normalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	valid := make(map[string]bool, len(model.APIKeyScopes))
+	for _, scope := range model.APIKeyScopes {
+		valid[scope] = true
+	}
+
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !valid[scope] {
+			return nil, fmt.Errorf("不支持的scope: %s，可选值：%s", scope, strings.Join(model.APIKeyScopes, "、"))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New("scopes不能为空")
+	}
+	return result, nil
+}
+
+// hashSecret 计算密钥或令牌的SHA-256哈希，磁盘上只保存哈希
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
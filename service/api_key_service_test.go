@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pansou/model"
+)
+
+// newTestAPIKeyService 创建使用临时目录的API密钥服务，不启动定期写回
+func newTestAPIKeyService(t *testing.T) *APIKeyService {
+	t.Helper()
+	s := &APIKeyService{keys: make(map[string]*apiKeyEntry)}
+	s.openStore(filepath.Join(t.TempDir(), apiKeyFileName))
+	if s.db == nil {
+		t.Fatal("打开API密钥存储失败")
+	}
+	t.Cleanup(func() { _ = s.db.Close() })
+	return s
+}
+
+func createTestAPIKey(t *testing.T, s *APIKeyService, req model.APIKeyCreateRequest) model.APIKeyCreateResponse {
+	t.Helper()
+	if req.Name == "" {
+		req.Name = "test"
+	}
+	if req.Scopes == nil {
+		req.Scopes = []string{model.APIKeyScopeSearch}
+	}
+	created, err := s.Create(req)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return created
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	s := newTestAPIKeyService(t)
+	valid := createTestAPIKey(t, s, model.APIKeyCreateRequest{})
+	revoked := createTestAPIKey(t, s, model.APIKeyCreateRequest{})
+	if _, err := s.Revoke(revoked.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	expired := createTestAPIKey(t, s, model.APIKeyCreateRequest{ExpiresInDays: 1})
+	s.keys[expired.ID].record.ExpiresAt = time.Now().Add(-time.Second).UnixMilli()
+
+	// 密钥ID正确但密文不匹配
+	badHash := valid.Key[:len(valid.Key)-1] + "x"
+	if strings.HasSuffix(valid.Key, "x") {
+		badHash = valid.Key[:len(valid.Key)-1] + "y"
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{"valid", valid.Key, nil},
+		{"revoked", revoked.Key, ErrAPIKeyRevoked},
+		{"expired", expired.Key, ErrAPIKeyExpired},
+		{"bad hash", badHash, ErrAPIKeyInvalid},
+		{"unknown id", "psk_0000000000000000_secret", ErrAPIKeyInvalid},
+		{"not an api key", "eyJhbGciOi", ErrAPIKeyInvalid},
+		{"missing secret", "psk_" + valid.ID + "_", ErrAPIKeyInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := s.Authenticate(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && key.ID != valid.ID {
+				t.Errorf("Authenticate() id = %q, want %q", key.ID, valid.ID)
+			}
+		})
+	}
+}
+
+func TestAPIKeyConsumeQuota(t *testing.T) {
+	s := newTestAPIKeyService(t)
+
+	t.Run("per minute", func(t *testing.T) {
+		created := createTestAPIKey(t, s, model.APIKeyCreateRequest{PerMinute: 2})
+		for i := 0; i < 2; i++ {
+			if _, err := s.Consume(created.ID); err != nil {
+				t.Fatalf("Consume() #%d error = %v", i+1, err)
+			}
+		}
+		retryAfter, err := s.Consume(created.ID)
+		if !errors.Is(err, ErrAPIKeyQuotaExceeded) || retryAfter <= 0 || retryAfter > time.Minute {
+			t.Fatalf("Consume() = %v, %v, want quota exceeded within a minute", retryAfter, err)
+		}
+
+		// 进入新的分钟窗口后重新计数
+		s.keys[created.ID].minuteStart = time.Now().Add(-2 * time.Minute).Truncate(time.Minute)
+		if _, err := s.Consume(created.ID); err != nil {
+			t.Errorf("新分钟窗口 Consume() error = %v", err)
+		}
+	})
+
+	t.Run("per day", func(t *testing.T) {
+		created := createTestAPIKey(t, s, model.APIKeyCreateRequest{PerDay: 1})
+		if _, err := s.Consume(created.ID); err != nil {
+			t.Fatalf("Consume() error = %v", err)
+		}
+		retryAfter, err := s.Consume(created.ID)
+		if !errors.Is(err, ErrAPIKeyQuotaExceeded) || retryAfter <= 0 || retryAfter > 24*time.Hour {
+			t.Fatalf("Consume() = %v, %v, want quota exceeded until tomorrow", retryAfter, err)
+		}
+		// 被拒绝的请求不计入用量
+		if used := s.keys[created.ID].record.UsedToday; used != 1 {
+			t.Errorf("UsedToday = %d, want 1", used)
+		}
+
+		// 跨天后重新计数
+		s.keys[created.ID].record.UsageDay = "2000-01-01"
+		if _, err := s.Consume(created.ID); err != nil {
+			t.Errorf("新的一天 Consume() error = %v", err)
+		}
+		if used := s.keys[created.ID].record.UsedToday; used != 1 {
+			t.Errorf("UsedToday = %d, want 1", used)
+		}
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		created := createTestAPIKey(t, s, model.APIKeyCreateRequest{})
+		for i := 0; i < 100; i++ {
+			if _, err := s.Consume(created.ID); err != nil {
+				t.Fatalf("Consume() #%d error = %v", i+1, err)
+			}
+		}
+	})
+
+	if _, err := s.Consume("missing"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("Consume(missing) error = %v, want ErrAPIKeyNotFound", err)
+	}
+}
+
+func TestAPIKeyFlushUsage(t *testing.T) {
+	s := newTestAPIKeyService(t)
+	used := createTestAPIKey(t, s, model.APIKeyCreateRequest{})
+	revoked := createTestAPIKey(t, s, model.APIKeyCreateRequest{})
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.Consume(used.ID); err != nil {
+			t.Fatalf("Consume() error = %v", err)
+		}
+	}
+	if _, err := s.Consume(revoked.ID); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	s.flushUsage()
+	if s.keys[used.ID].dirty {
+		t.Error("写回后dirty应被清除")
+	}
+
+	// 写回用量不覆盖之后保存的吊销状态
+	if _, err := s.Consume(revoked.ID); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if _, err := s.Revoke(revoked.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	s.keys[revoked.ID].dirty = true
+	s.flushUsage()
+
+	reloaded := &APIKeyService{keys: make(map[string]*apiKeyEntry), db: s.db}
+	reloaded.load()
+	if got := reloaded.keys[used.ID].record.UsedToday; got != 3 {
+		t.Errorf("重新加载后UsedToday = %d, want 3", got)
+	}
+	if reloaded.keys[used.ID].record.LastUsedAt == 0 {
+		t.Error("重新加载后LastUsedAt为空")
+	}
+	if record := reloaded.keys[revoked.ID].record; record.RevokedAt == 0 || record.UsedToday != 2 {
+		t.Errorf("重新加载后 revoked_at = %d, used_today = %d, want revoked and 2", record.RevokedAt, record.UsedToday)
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"pansou/config"
+)
+
+const (
+	authTokenFileName      = "auth_tokens.db"
+	revokedTokenBucketName = "revoked_tokens"
+	refreshTokenBucketName = "refresh_tokens"
+	refreshTokenPrefix     = "psr_"
+	authTokenPruneInterval = time.Hour // 清理过期记录的间隔
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期")
+	ErrAuthTokenStore      = errors.New("令牌存储不可用")
+)
+
+// refreshTokenRecord 持久化的刷新令牌，键为令牌的SHA-256哈希
+type refreshTokenRecord struct {
+	Username  string `json:"username"`
+	ExpiresAt int64  `json:"expires_at"` // 登录会话的过期时间（毫秒时间戳），轮换后保持不变
+}
+
+// AuthTokenStore 登录令牌的服务端状态
+// 记录注销后吊销的访问令牌（按jti，保留到令牌过期）和有效的刷新令牌，均保存在bbolt中，重启后仍然生效。
+// 刷新令牌每次使用后轮换，旧令牌立即失效。
+type AuthTokenStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time // 已吊销的jti → 令牌过期时间
+	db      *bolt.DB
+}
+
+// NewAuthTokenStore 创建令牌存储并加载未过期的吊销记录
+func NewAuthTokenStore() *AuthTokenStore {
+	s := &AuthTokenStore{revoked: make(map[string]time.Time)}
+	s.openStore(filepath.Join(config.Get().CachePath, authTokenFileName))
+	s.prune()
+	s.load()
+
+	go func() {
+		ticker := time.NewTicker(authTokenPruneInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.prune()
+		}
+	}()
+	return s
+}
+
+func (s *AuthTokenStore) openStore(path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range []string{revokedTokenBucketName, refreshTokenBucketName} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		_ = db.Close()
+		return
+	}
+
+	s.db = db
+}
+
+// load 加载吊销记录到内存，认证中间件每次请求都要查询
+func (s *AuthTokenStore) load() {
+	if s.db == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(revokedTokenBucketName)).ForEach(func(k, v []byte) error {
+			if expiresAt, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+				s.revoked[string(k)] = time.UnixMilli(expiresAt)
+			}
+			return nil
+		})
+	})
+}
+
+// RevokeToken 吊销访问令牌，吊销记录保留到令牌过期
+func (s *AuthTokenStore) RevokeToken(tokenID string, expiresAt time.Time) {
+	if tokenID == "" || !time.Now().Before(expiresAt) {
+		return
+	}
+
+	s.mu.Lock()
+	s.revoked[tokenID] = expiresAt
+	s.mu.Unlock()
+
+	if s.db != nil {
+		_ = s.db.Update(func(tx *bolt.Tx) error {
+			value := strconv.FormatInt(expiresAt.UnixMilli(), 10)
+			return tx.Bucket([]byte(revokedTokenBucketName)).Put([]byte(tokenID), []byte(value))
+		})
+	}
+}
+
+// IsRevoked 检查访问令牌是否已被吊销
+func (s *AuthTokenStore) IsRevoked(tokenID string) bool {
+	if tokenID == "" {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, revoked := s.revoked[tokenID]
+	return revoked
+}
+
+// IssueRefreshToken 为用户签发刷新令牌，expiresAt为登录会话的过期时间
+func (s *AuthTokenStore) IssueRefreshToken(username string, expiresAt time.Time) (string, error) {
+	if s.db == nil {
+		return "", ErrAuthTokenStore
+	}
+
+	token, err := newRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return putRefreshToken(tx, token, refreshTokenRecord{Username: username, ExpiresAt: expiresAt.UnixMilli()})
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RotateRefreshToken 使用刷新令牌换取新的刷新令牌，旧令牌立即失效
+// 返回用户名、登录会话的过期时间和新的刷新令牌
+func (s *AuthTokenStore) RotateRefreshToken(token string) (string, time.Time, string, error) {
+	if s.db == nil {
+		return "", time.Time{}, "", ErrAuthTokenStore
+	}
+
+	newToken, err := newRefreshToken()
+	if err != nil {
+		return "", time.Time{}, "", err
+	}
+
+	var record refreshTokenRecord
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(refreshTokenBucketName))
+		key := []byte(hashSecret(token))
+		raw := bucket.Get(key)
+		if raw == nil || json.Unmarshal(raw, &record) != nil {
+			return ErrRefreshTokenInvalid
+		}
+		if time.Now().UnixMilli() >= record.ExpiresAt {
+			return ErrRefreshTokenInvalid
+		}
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+		return putRefreshToken(tx, newToken, record)
+	})
+	if err != nil {
+		return "", time.Time{}, "", err
+	}
+	return record.Username, time.UnixMilli(record.ExpiresAt), newToken, nil
+}
+
+// RevokeRefreshToken 吊销刷新令牌
+func (s *AuthTokenStore) RevokeRefreshToken(token string) {
+	if s.db == nil || token == "" {
+		return
+	}
+	_ = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(refreshTokenBucketName)).Delete([]byte(hashSecret(token)))
+	})
+}
+
+// prune 删除已过期的吊销记录和刷新令牌
+func (s *AuthTokenStore) prune() {
+	now := time.Now()
+
+	s.mu.Lock()
+	for tokenID, expiresAt := range s.revoked {
+		if !now.Before(expiresAt) {
+			delete(s.revoked, tokenID)
+		}
+	}
+	s.mu.Unlock()
+
+	if s.db == nil {
+		return
+	}
+	_ = s.db.Update(func(tx *bolt.Tx) error {
+		revoked := tx.Bucket([]byte(revokedTokenBucketName))
+		var expired [][]byte
+		_ = revoked.ForEach(func(k, v []byte) error {
+			if expiresAt, err := strconv.ParseInt(string(v), 10, 64); err != nil || now.UnixMilli() >= expiresAt {
+				expired = append(expired, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		for _, k := range expired {
+			if err := revoked.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		refresh := tx.Bucket([]byte(refreshTokenBucketName))
+		expired = expired[:0]
+		_ = refresh.ForEach(func(k, v []byte) error {
+			var record refreshTokenRecord
+			if err := json.Unmarshal(v, &record); err != nil || now.UnixMilli() >= record.ExpiresAt {
+				expired = append(expired, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		for _, k := range expired {
+			if err := refresh.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// putRefreshToken 保存刷新令牌，只保存令牌的哈希
+func putRefreshToken(tx *bolt.Tx, token string, record refreshTokenRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket([]byte(refreshTokenBucketName)).Put([]byte(hashSecret(token)), raw)
+}
+
+func newRefreshToken() (string, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+	return refreshTokenPrefix + secret, nil
+}
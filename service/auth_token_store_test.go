@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestAuthTokenStore 创建使用临时目录的令牌存储，不启动定期清理
+func newTestAuthTokenStore(t *testing.T) *AuthTokenStore {
+	t.Helper()
+	s := &AuthTokenStore{revoked: make(map[string]time.Time)}
+	s.openStore(filepath.Join(t.TempDir(), authTokenFileName))
+	if s.db == nil {
+		t.Fatal("打开令牌存储失败")
+	}
+	t.Cleanup(func() { _ = s.db.Close() })
+	return s
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	s := newTestAuthTokenStore(t)
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+
+	token, err := s.IssueRefreshToken("alice", expiresAt)
+	if err != nil {
+		t.Fatalf("IssueRefreshToken() error = %v", err)
+	}
+
+	username, sessionExpiresAt, rotated, err := s.RotateRefreshToken(token)
+	if err != nil {
+		t.Fatalf("RotateRefreshToken() error = %v", err)
+	}
+	if username != "alice" || !sessionExpiresAt.Equal(expiresAt) || rotated == token {
+		t.Errorf("RotateRefreshToken() = %q, %v, %q", username, sessionExpiresAt, rotated)
+	}
+
+	// 已轮换的令牌不能再次使用
+	if _, _, _, err := s.RotateRefreshToken(token); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("重复使用旧令牌 error = %v, want ErrRefreshTokenInvalid", err)
+	}
+	// 新令牌仍然有效，登录会话的过期时间保持不变
+	_, sessionExpiresAt, _, err = s.RotateRefreshToken(rotated)
+	if err != nil || !sessionExpiresAt.Equal(expiresAt) {
+		t.Errorf("RotateRefreshToken(new) = %v, %v", sessionExpiresAt, err)
+	}
+}
+
+func TestRotateRefreshTokenInvalid(t *testing.T) {
+	s := newTestAuthTokenStore(t)
+
+	expired, err := s.IssueRefreshToken("alice", time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("IssueRefreshToken() error = %v", err)
+	}
+	revoked, err := s.IssueRefreshToken("alice", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("IssueRefreshToken() error = %v", err)
+	}
+	s.RevokeRefreshToken(revoked)
+
+	for name, token := range map[string]string{"unknown": "psr_unknown", "expired": expired, "revoked": revoked} {
+		if _, _, _, err := s.RotateRefreshToken(token); !errors.Is(err, ErrRefreshTokenInvalid) {
+			t.Errorf("%s: error = %v, want ErrRefreshTokenInvalid", name, err)
+		}
+	}
+
+	// 存储不可用时返回ErrAuthTokenStore
+	if _, _, _, err := (&AuthTokenStore{}).RotateRefreshToken("psr_x"); !errors.Is(err, ErrAuthTokenStore) {
+		t.Errorf("error = %v, want ErrAuthTokenStore", err)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	s := newTestAuthTokenStore(t)
+
+	s.RevokeToken("jti-1", time.Now().Add(time.Hour))
+	s.RevokeToken("jti-expired", time.Now().Add(-time.Second))
+	if !s.IsRevoked("jti-1") || s.IsRevoked("jti-expired") || s.IsRevoked("") {
+		t.Error("IsRevoked() 结果不正确")
+	}
+
+	// 吊销记录持久化，重新加载后仍然生效
+	reloaded := &AuthTokenStore{revoked: make(map[string]time.Time), db: s.db}
+	reloaded.load()
+	if !reloaded.IsRevoked("jti-1") {
+		t.Error("重新加载后吊销记录丢失")
+	}
+}
@@ -85,7 +85,7 @@ func (c *CacheWriteIntegration) HandleCacheWrite(key string, results []model.Sea
 func (c *CacheWriteIntegration) getPluginPriority(pluginName string) int {
 	// 从插件管理器动态获取真实的优先级
 	if pluginInstance, exists := plugin.GetPluginByName(pluginName); exists {
-		return pluginInstance.Priority()
+		return plugin.EffectivePriority(pluginInstance)
 	}
 	
 	// 如果插件不存在，返回默认等级4（最低优先级）
@@ -2,43 +2,27 @@ package service
 
 import (
 	"bytes"
-	"compress/gzip"
-	"compress/zlib"
 	"context"
-	"crypto/md5"
 	"encoding/gob"
-	"encoding/xml"
-	"fmt"
-	"io"
-	"math/rand"
-	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strings"
 	"sync"
 	"time"
 
 	bolt "go.etcd.io/bbolt"
 
+	"pansou/checker"
+	"pansou/config"
 	"pansou/model"
-	utiljson "pansou/util/json"
-	"pansou/util"
+	"pansou/util/ratelimit"
 )
 
-const (
-	checkStateOK          = "ok"
-	checkStateBad         = "bad"
-	checkStateLocked      = "locked"
-	checkStateUnsupported = "unsupported"
-	checkStateUncertain   = "uncertain"
-	checkCacheBucketName  = "check_results"
-)
+const checkCacheBucketName = "check_results"
 
 type cachedCheckResult struct {
 	result    model.CheckResult
 	expiresAt time.Time
+	password  string // 检测时使用的提取码，复检时需要
 }
 
 type activeCheckCall struct {
@@ -50,45 +34,136 @@ type activeCheckCall struct {
 type cachedCheckDiskEntry struct {
 	Result    model.CheckResult
 	ExpiresAt int64
+	Password  string
 }
 
 type CheckService struct {
-	mu        sync.Mutex
-	cache     map[string]cachedCheckResult
-	inflight  map[string]*activeCheckCall
-	client    *http.Client
-	cacheFile string
-	cacheDB   *bolt.DB
+	mu           sync.Mutex
+	cache        map[string]cachedCheckResult
+	inflight     map[string]*activeCheckCall
+	cacheFile    string
+	cacheDB      *bolt.DB
+	concurrency  int
+	batchTimeout time.Duration
+	limiter      *ratelimit.Limiter
+	hits         map[string]int // 缓存命中次数，即链接在检测请求和搜索结果中出现的次数
+	sweeper      *CheckSweeper
 }
 
 func NewCheckService() *CheckService {
+	concurrency := 8
+	batchTimeout := 15 * time.Second
+	ratePerSecond := 3.0
+	rateBurst := 5
+	if config.Get() != nil {
+		concurrency = config.Get().CheckConcurrency
+		batchTimeout = config.Get().CheckTimeout
+		ratePerSecond = config.Get().CheckRatePerSecond
+		rateBurst = config.Get().CheckRateBurst
+	}
+
 	service := &CheckService{
-		cache:     make(map[string]cachedCheckResult),
-		inflight:  make(map[string]*activeCheckCall),
-		client:    util.GetHTTPClient(),
-		cacheFile: filepath.Join(".", "cache", "check_cache.db"),
+		cache:        make(map[string]cachedCheckResult),
+		inflight:     make(map[string]*activeCheckCall),
+		cacheFile:    filepath.Join(".", "cache", "check_cache.db"),
+		concurrency:  concurrency,
+		batchTimeout: batchTimeout,
+		limiter:      ratelimit.NewLimiter(ratePerSecond, rateBurst),
+		hits:         make(map[string]int),
 	}
 	service.openCacheStore()
 	service.pruneExpiredCacheStore()
+
+	if config.Get() != nil && config.Get().CheckSweepInterval > 0 {
+		service.sweeper = newCheckSweeper(service, config.Get().CheckSweepInterval, config.Get().CheckSweepWindow, config.Get().CheckSweepBatch)
+		service.sweeper.start()
+	}
 	return service
 }
 
-func (s *CheckService) Check(items []model.CheckItem) model.CheckResponse {
-	results := make([]model.CheckResult, 0, len(items))
+// Check 并发检测一批链接，结果顺序与items一致。
+// 同一网盘的检测受令牌桶限流；超过总时限仍未完成的链接标记为uncertain，
+// 已发出的检测在后台继续完成并写入缓存。
+func (s *CheckService) Check(ctx context.Context, items []model.CheckItem) model.CheckResponse {
+	ctx, cancel := context.WithTimeout(ctx, s.batchTimeout)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		results  = make([]model.CheckResult, len(items))
+		finished = make([]bool, len(items))
+		pending  = len(items)
+		allDone  = make(chan struct{})
+	)
+
+	indexes := make(chan int, len(items))
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+
+	workers := s.concurrency
+	if workers <= 0 || workers > len(items) {
+		workers = len(items)
+	}
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range indexes {
+				if ctx.Err() != nil {
+					return
+				}
+
+				result := s.checkOne(ctx, items[i])
+
+				mu.Lock()
+				results[i] = result
+				finished[i] = true
+				pending--
+				if pending == 0 {
+					close(allDone)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if len(items) > 0 {
+		select {
+		case <-allDone:
+		case <-ctx.Done():
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	response := make([]model.CheckResult, len(items))
+	for i, item := range items {
+		if finished[i] {
+			response[i] = results[i]
+			continue
+		}
+		response[i] = checker.BuildResult(item, checker.Normalize(item.DiskType, item.URL, item.Password), checker.StateUncertain, "检测超时")
+	}
 
-	for _, item := range items {
-		results = append(results, s.checkOne(item))
+	for _, result := range response {
+		recordCheckResult(result.DiskType, result.State)
 	}
 
 	return model.CheckResponse{
-		Results: results,
+		Results: response,
 	}
 }
 
-func (s *CheckService) checkOne(item model.CheckItem) model.CheckResult {
-	normalized := s.normalizeShareLink(item.DiskType, item.URL, item.Password)
+func (s *CheckService) checkOne(ctx context.Context, item model.CheckItem) model.CheckResult {
+	normalized := checker.Normalize(item.DiskType, item.URL, item.Password)
 	if normalized == "" {
-		return s.buildResult(item, "", checkStateUncertain, false, "链接格式无效")
+		return checker.BuildResult(item, "", checker.StateUncertain, "链接格式无效")
+	}
+
+	linkChecker, ok := checker.GetChecker(item.DiskType)
+	if !ok {
+		return checker.BuildResult(item, normalized, checker.StateUnsupported, "当前平台暂不支持检测")
 	}
 
 	cacheKey := item.DiskType + "|" + normalized
@@ -99,37 +174,114 @@ func (s *CheckService) checkOne(item model.CheckItem) model.CheckResult {
 
 	call, wait := s.acquireInflight(cacheKey)
 	if wait {
-		<-call.done
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return checker.BuildResult(item, normalized, checker.StateUncertain, "检测超时")
+		}
 		if call.err != nil {
-			return s.buildResult(item, normalized, checkStateUncertain, false, "检测失败")
+			return checker.BuildResult(item, normalized, checker.StateUncertain, "检测失败")
 		}
 		result := call.result
 		result.CacheHit = false
 		return result
 	}
 
-	result, err := s.runCheck(item, normalized)
-	s.finishInflight(cacheKey, call, result, err)
+	// 同一网盘的检测请求按令牌桶限流，避免被上游封禁
+	if err := s.limiter.Wait(ctx, item.DiskType); err != nil {
+		s.finishInflight(cacheKey, item, call, model.CheckResult{}, err)
+		return checker.BuildResult(item, normalized, checker.StateUncertain, "检测超时")
+	}
+
+	// 已发出的检测不随批次截止而取消，完成后仍写入缓存
+	result, err := linkChecker.Check(context.WithoutCancel(ctx), item)
+	s.finishInflight(cacheKey, item, call, result, err)
 
 	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "检测失败")
+		return checker.BuildResult(item, normalized, checker.StateUncertain, "检测失败")
 	}
 
 	return result
 }
 
+// Lookup 只从缓存读取链接的检测结果，不发起检测
+func (s *CheckService) Lookup(item model.CheckItem) (model.CheckResult, bool) {
+	result, ok := s.LookupBatch([]model.CheckItem{item})[0]
+	return result, ok
+}
+
+// LookupBatch 批量从缓存读取链接的检测结果，返回命中项在items中的下标到结果的映射
+// 内存缓存未命中的链接在一个bbolt读事务中查询，避免搜索结果中的每个链接各开一个事务
+func (s *CheckService) LookupBatch(items []model.CheckItem) map[int]model.CheckResult {
+	found := make(map[int]model.CheckResult)
+	keys := make([]string, len(items))
+	missing := make(map[string][]int)
+	now := time.Now()
+
+	s.mu.Lock()
+	for i, item := range items {
+		normalized := checker.Normalize(item.DiskType, item.URL, item.Password)
+		if normalized == "" {
+			continue
+		}
+		key := item.DiskType + "|" + normalized
+		keys[i] = key
+
+		entry, ok := s.cache[key]
+		if ok && now.After(entry.expiresAt) {
+			delete(s.cache, key)
+			delete(s.hits, key)
+			ok = false
+		}
+		if ok {
+			found[i] = entry.result
+			s.hits[key]++
+			continue
+		}
+		missing[key] = append(missing[key], i)
+	}
+	s.mu.Unlock()
+
+	if len(missing) == 0 {
+		return found
+	}
+
+	loaded := s.loadPersistentCaches(missing)
+	var expired []string
+	s.mu.Lock()
+	for key, entry := range loaded {
+		if now.After(entry.expiresAt) {
+			expired = append(expired, key)
+			continue
+		}
+		s.cache[key] = entry
+		for _, i := range missing[key] {
+			found[i] = entry.result
+			s.hits[key]++
+		}
+	}
+	s.mu.Unlock()
+
+	if len(expired) > 0 {
+		s.deletePersistentCaches(expired)
+	}
+	return found
+}
+
 func (s *CheckService) getCached(key string) (model.CheckResult, bool) {
 	s.mu.Lock()
 	entry, ok := s.cache[key]
 	if ok {
 		if time.Now().After(entry.expiresAt) {
 			delete(s.cache, key)
+			delete(s.hits, key)
 			s.mu.Unlock()
 			s.deletePersistentCache(key)
 			return model.CheckResult{}, false
 		}
 
 		result := entry.result
+		s.hits[key]++
 		s.mu.Unlock()
 		return result, true
 	}
@@ -147,6 +299,7 @@ func (s *CheckService) getCached(key string) (model.CheckResult, bool) {
 
 	s.mu.Lock()
 	s.cache[key] = entry
+	s.hits[key]++
 	s.mu.Unlock()
 
 	return entry.result, true
@@ -167,7 +320,7 @@ func (s *CheckService) acquireInflight(key string) (*activeCheckCall, bool) {
 	return call, false
 }
 
-func (s *CheckService) finishInflight(key string, call *activeCheckCall, result model.CheckResult, err error) {
+func (s *CheckService) finishInflight(key string, item model.CheckItem, call *activeCheckCall, result model.CheckResult, err error) {
 	var entry cachedCheckResult
 	call.result = result
 	call.err = err
@@ -177,6 +330,7 @@ func (s *CheckService) finishInflight(key string, call *activeCheckCall, result
 		entry = cachedCheckResult{
 			result:    result,
 			expiresAt: time.UnixMilli(result.ExpiresAt),
+			password:  item.Password,
 		}
 		s.cache[key] = entry
 	}
@@ -190,789 +344,6 @@ func (s *CheckService) finishInflight(key string, call *activeCheckCall, result
 	}
 }
 
-func (s *CheckService) runCheck(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	switch item.DiskType {
-	case "aliyun":
-		return s.checkAliyun(item, normalized)
-	case "quark":
-		return s.checkQuark(item, normalized)
-	case "uc":
-		return s.checkUC(item, normalized)
-	case "baidu":
-		return s.checkBaidu(item, normalized)
-	case "tianyi":
-		return s.checkTianyi(item, normalized)
-	case "123":
-		return s.check123(item, normalized)
-	case "xunlei":
-		return s.checkXunlei(item, normalized)
-	case "115":
-		return s.check115(item, normalized)
-	case "mobile":
-		return s.checkMobile(item, normalized)
-	default:
-		return s.buildResult(item, normalized, checkStateUnsupported, false, "当前平台暂不支持检测"), nil
-	}
-}
-
-func (s *CheckService) checkAliyun(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	shareID := extractAliyunShareID(normalized)
-	if shareID == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法解析分享地址"), nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	body, statusCode, err := s.doJSONRequest(ctx, "POST", "https://api.aliyundrive.com/adrive/v3/share_link/get_share_by_anonymous?share_id="+shareID, map[string]string{
-		"share_id": shareID,
-	}, map[string]string{
-		"content-type": "application/json",
-		"origin":       "https://www.alipan.com",
-		"referer":      "https://www.alipan.com/",
-		"x-canary":     "client=web,app=share,version=v2.3.1",
-	})
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	var parsed struct {
-		ShareName  string `json:"share_name"`
-		ShareTitle string `json:"share_title"`
-		Code       string `json:"code"`
-		Message    string `json:"message"`
-	}
-	_ = utiljson.Unmarshal(body, &parsed)
-
-	switch {
-	case statusCode == http.StatusOK && (parsed.ShareName != "" || parsed.ShareTitle != ""):
-		return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-	case strings.Contains(parsed.Code, "NotFound"), strings.Contains(parsed.Code, "Cancelled"):
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	default:
-		return s.buildResult(item, normalized, checkStateUncertain, false, parsed.Message), nil
-	}
-}
-
-func (s *CheckService) checkQuark(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	resourceID, password := extractQuarkShareIDAndPassword(normalized)
-	if resourceID == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法解析分享地址"), nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	tokenBody, _, err := s.doJSONRequest(ctx, "POST", "https://drive-h.quark.cn/1/clouddrive/share/sharepage/token", map[string]any{
-		"pwd_id":                            resourceID,
-		"passcode":                          password,
-		"support_visit_limit_private_share": true,
-	}, map[string]string{
-		"content-type": "application/json",
-		"origin":       "https://pan.quark.cn",
-		"referer":      "https://pan.quark.cn/",
-	})
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	var tokenResp struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-		Data    struct {
-			Stoken string `json:"stoken"`
-		} `json:"data"`
-	}
-	_ = utiljson.Unmarshal(tokenBody, &tokenResp)
-
-	switch tokenResp.Code {
-	case 0:
-	case 41008:
-		return s.buildResult(item, normalized, checkStateLocked, false, "需要提取码"), nil
-	case 41004, 41010, 41011:
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	default:
-		if containsAny(strings.ToLower(tokenResp.Message), []string{"不存在", "失效", "违规", "过期", "取消"}) {
-			return s.buildResult(item, normalized, checkStateBad, false, tokenResp.Message), nil
-		}
-		if containsAny(strings.ToLower(tokenResp.Message), []string{"提取码", "密码"}) {
-			return s.buildResult(item, normalized, checkStateLocked, false, tokenResp.Message), nil
-		}
-		return s.buildResult(item, normalized, checkStateUncertain, false, tokenResp.Message), nil
-	}
-
-	if tokenResp.Data.Stoken == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "访问令牌缺失"), nil
-	}
-
-	detailURL := fmt.Sprintf("https://drive-pc.quark.cn/1/clouddrive/share/sharepage/detail?pwd_id=%s&stoken=%s&ver=2&pr=ucpro", url.QueryEscape(resourceID), url.QueryEscape(tokenResp.Data.Stoken))
-	detailBody, _, err := s.doRequest(ctx, "GET", detailURL, nil, map[string]string{
-		"accept":        "application/json, text/plain, */*",
-		"origin":        "https://pan.quark.cn",
-		"referer":       "https://pan.quark.cn/",
-		"cache-control": "no-cache",
-	})
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "详情请求失败"), err
-	}
-
-	var detailResp struct {
-		Code int `json:"code"`
-		Data struct {
-			List []any `json:"list"`
-		} `json:"data"`
-	}
-	_ = utiljson.Unmarshal(detailBody, &detailResp)
-
-	if detailResp.Code == 0 && len(detailResp.Data.List) > 0 {
-		return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-	}
-
-	return s.buildResult(item, normalized, checkStateUncertain, false, "无法确认链接状态"), nil
-}
-
-func (s *CheckService) checkUC(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
-
-	body, statusCode, err := s.doRequest(ctx, "GET", normalized, nil, map[string]string{
-		"user-agent": "Mozilla/5.0 (Linux; Android 10; Mobile) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36",
-	})
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	if statusCode == http.StatusNotFound {
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	}
-
-	pageText := strings.ToLower(string(body))
-	switch {
-	case containsAny(pageText, []string{"失效", "不存在", "违规", "删除", "已过期", "被取消"}):
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	case containsAny(pageText, []string{"提取码", "访问码", "请输入密码"}):
-		return s.buildResult(item, normalized, checkStateLocked, false, "需要提取码"), nil
-	case containsAny(pageText, []string{"文件", "分享", "drive.uc.cn"}):
-		return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-	default:
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法确认链接状态"), nil
-	}
-}
-
-func (s *CheckService) checkBaidu(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	shareID, shortURL, password := extractBaiduShareInfo(normalized)
-	if shareID == "" || shortURL == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法解析分享地址"), nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
-	defer cancel()
-
-	var bdclnd string
-	if password != "" {
-		verifyURL := fmt.Sprintf("https://pan.baidu.com/share/verify?surl=%s&pwd=%s", url.QueryEscape(shortURL), url.QueryEscape(password))
-		body, _, err := s.doFormRequest(ctx, "POST", verifyURL, url.Values{
-			"pwd":       {password},
-			"vcode":     {""},
-			"vcode_str": {""},
-		}, map[string]string{
-			"referer":      normalized,
-			"content-type": "application/x-www-form-urlencoded",
-		})
-		if err != nil {
-			return s.buildResult(item, normalized, checkStateUncertain, false, "验证失败"), err
-		}
-
-		var verifyResp struct {
-			Errno  int    `json:"errno"`
-			Errmsg string `json:"errmsg"`
-			Randsk string `json:"randsk"`
-		}
-		_ = utiljson.Unmarshal(body, &verifyResp)
-
-		switch verifyResp.Errno {
-		case 0:
-			bdclnd = verifyResp.Randsk
-		case -9, -12:
-			return s.buildResult(item, normalized, checkStateLocked, false, "提取码错误或缺失"), nil
-		default:
-			return s.buildResult(item, normalized, checkStateUncertain, false, verifyResp.Errmsg), nil
-		}
-	}
-
-	listURL := fmt.Sprintf("https://pan.baidu.com/share/list?web=1&page=1&num=20&order=time&desc=1&showempty=0&shorturl=%s&root=1&clienttype=0", url.QueryEscape(shortURL))
-	headers := map[string]string{
-		"accept":     "application/json, text/plain, */*",
-		"referer":    normalized,
-		"user-agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36",
-	}
-	if bdclnd != "" {
-		headers["cookie"] = fmt.Sprintf("BDCLND=%s", bdclnd)
-	}
-
-	body, _, err := s.doRequest(ctx, "GET", listURL, nil, headers)
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	var listResp struct {
-		Errno  int    `json:"errno"`
-		Errmsg string `json:"errmsg"`
-		List   []any  `json:"list"`
-	}
-	_ = utiljson.Unmarshal(body, &listResp)
-
-	switch listResp.Errno {
-	case 0:
-		if len(listResp.List) > 0 {
-			return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-		}
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	case -9, -12:
-		return s.buildResult(item, normalized, checkStateLocked, false, "需要提取码"), nil
-	case -7, 105, 115, 117, 145:
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	default:
-		return s.buildResult(item, normalized, checkStateUncertain, false, listResp.Errmsg), nil
-	}
-}
-
-func (s *CheckService) checkTianyi(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	shareCode, password, referer := extractTianyiShareInfo(normalized, item.Password)
-	if shareCode == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法解析分享地址"), nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	noCache := fmt.Sprintf("%f", rand.New(rand.NewSource(time.Now().UnixNano())).Float64())
-	shareCodeParam := shareCode
-	if password != "" {
-		shareCodeParam = fmt.Sprintf("%s（访问码：%s）", shareCode, password)
-	}
-
-	apiURL := "https://cloud.189.cn/api/open/share/getShareInfoByCodeV2.action"
-	targetURL, err := url.Parse(apiURL)
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求地址构造失败"), err
-	}
-
-	query := targetURL.Query()
-	query.Set("noCache", noCache)
-	query.Set("shareCode", shareCodeParam)
-	targetURL.RawQuery = query.Encode()
-
-	body, statusCode, err := s.doRequest(ctx, "GET", targetURL.String(), nil, map[string]string{
-		"referer":   referer,
-		"sign-type": "1",
-	})
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	bodyText := strings.TrimSpace(string(body))
-
-	var shareResponse struct {
-		XMLName        xml.Name `xml:"shareVO"`
-		NeedAccessCode int      `xml:"needAccessCode"`
-		ShareID        int64    `xml:"shareId"`
-		FileName       string   `xml:"fileName"`
-		AccessCode     string   `xml:"accessCode"`
-	}
-	if err := xml.Unmarshal(body, &shareResponse); err == nil && shareResponse.XMLName.Local == "shareVO" {
-		switch {
-		case shareResponse.ShareID > 0:
-			return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-		case shareResponse.FileName != "":
-			return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-		case shareResponse.NeedAccessCode == 1:
-			return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-		}
-	}
-
-	var errorResponse struct {
-		XMLName xml.Name `xml:"error"`
-		Code    string   `xml:"code"`
-		Message string   `xml:"message"`
-	}
-	if err := xml.Unmarshal(body, &errorResponse); err == nil && errorResponse.XMLName.Local == "error" {
-		message := coalesce(errorResponse.Message, errorResponse.Code)
-		messageLower := strings.ToLower(message)
-
-		switch {
-		case containsAny(messageLower, []string{"accesscode", "访问码", "提取码", "密码"}):
-			return s.buildResult(item, normalized, checkStateLocked, false, message), nil
-		case containsAny(messageLower, []string{"shareinfonotfound", "sharenotfound", "filenotfound", "shareexpirederror", "shareauditnotpass", "不存在", "失效", "取消", "过期"}):
-			return s.buildResult(item, normalized, checkStateBad, false, message), nil
-		}
-		return s.buildResult(item, normalized, checkStateBad, false, message), nil
-	}
-
-	switch {
-	case statusCode == http.StatusOK && strings.Contains(bodyText, "<shareVO>"):
-		if strings.Contains(bodyText, "<shareId>") || strings.Contains(bodyText, "<fileName>") {
-			return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-		}
-		if strings.Contains(bodyText, "<needAccessCode>1</needAccessCode>") {
-			return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-		}
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法确认链接状态"), nil
-	case containsAny(strings.ToLower(bodyText), []string{"erroraccesscode", "needaccesscode", "访问码", "提取码", "密码"}):
-		return s.buildResult(item, normalized, checkStateLocked, false, "需要访问码"), nil
-	case containsAny(strings.ToLower(bodyText), []string{"shareinfonotfound", "sharenotfound", "filenotfound", "shareexpirederror", "shareauditnotpass", "不存在", "失效", "取消", "过期"}):
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	default:
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法确认链接状态"), nil
-	}
-}
-
-func (s *CheckService) check123(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	shareKey := extract123ShareKey(normalized)
-	if shareKey == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法解析分享地址"), nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
-
-	apiURL := fmt.Sprintf("https://www.123pan.com/api/share/info?shareKey=%s", url.QueryEscape(shareKey))
-	body, statusCode, err := s.doRequest(ctx, "GET", apiURL, nil, nil)
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	if statusCode == http.StatusForbidden {
-		return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-	}
-
-	var response struct {
-		Code int `json:"code"`
-		Data struct {
-			HasPwd bool `json:"HasPwd"`
-		} `json:"data"`
-		Message string `json:"message"`
-	}
-	if err := utiljson.Unmarshal(body, &response); err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "响应解析失败"), nil
-	}
-
-	switch {
-	case response.Code == 0:
-		return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-	case response.Data.HasPwd:
-		return s.buildResult(item, normalized, checkStateLocked, false, "需要提取码"), nil
-	case response.Message != "":
-		return s.buildResult(item, normalized, checkStateBad, false, response.Message), nil
-	default:
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	}
-}
-
-func (s *CheckService) checkXunlei(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	shareID, password := extractXunleiShareInfo(normalized)
-	if shareID == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法解析分享地址"), nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
-	defer cancel()
-
-	captchaToken, _ := s.fetchXunleiCaptchaToken(ctx)
-
-	apiURL := fmt.Sprintf("https://api-pan.xunlei.com/drive/v1/share?share_id=%s&pass_code=%s&limit=100&pass_code_token=&page_token=&thumbnail_size=SIZE_SMALL",
-		url.QueryEscape(shareID), url.QueryEscape(password))
-
-	headers := map[string]string{
-		"accept":          "*/*",
-		"content-type":    "application/json",
-		"origin":          "https://pan.xunlei.com",
-		"referer":         "https://pan.xunlei.com/",
-		"user-agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36",
-		"accept-encoding": "gzip, deflate",
-		"x-client-id":     "ZUBzD9J_XPXfn7f7",
-		"x-device-id":     "5505bd0cab8c9469b98e5891d9fb3e0d",
-	}
-	if captchaToken != "" {
-		headers["x-captcha-token"] = captchaToken
-	}
-
-	body, statusCode, err := s.doRequest(ctx, "GET", apiURL, nil, headers)
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	body, _ = decompressResponseBody(body, headers["accept-encoding"], "")
-
-	if statusCode == http.StatusNotFound || statusCode == http.StatusForbidden {
-		return s.buildResult(item, normalized, checkStateBad, false, "链接失效"), nil
-	}
-
-	var response struct {
-		ErrorCode       int    `json:"error_code"`
-		Error           string `json:"error"`
-		ErrorMsg        string `json:"error_description"`
-		ShareID         string `json:"share_id"`
-		PassCode        string `json:"pass_code"`
-		FileCount       int    `json:"file_count"`
-		ShareName       string `json:"share_name"`
-		ShareStatus     string `json:"share_status"`
-		ShareStatusText string `json:"share_status_text"`
-	}
-	if err := utiljson.Unmarshal(body, &response); err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "响应解析失败"), nil
-	}
-
-	switch {
-	case response.ShareStatus == "OK":
-		return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-	case response.ShareID != "", response.ShareName != "", response.FileCount > 0:
-		return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-	case containsAny(strings.ToLower(response.Error), []string{"pass_code"}), containsAny(strings.ToLower(response.ErrorMsg), []string{"pass_code", "提取码", "密码"}):
-		return s.buildResult(item, normalized, checkStateLocked, false, coalesce(response.ErrorMsg, "需要提取码")), nil
-	case containsAny(strings.ToLower(response.ShareStatus), []string{"pass_code"}), containsAny(strings.ToLower(response.ShareStatusText), []string{"pass_code", "提取码", "密码"}):
-		return s.buildResult(item, normalized, checkStateLocked, false, coalesce(response.ShareStatusText, "需要提取码")), nil
-	case response.ShareStatus != "" && response.ShareStatus != "OK":
-		summary := coalesce(response.ShareStatusText, fmt.Sprintf("分享状态: %s", response.ShareStatus))
-		if containsAny(strings.ToLower(summary), []string{"不存在", "失效", "过期", "not found", "deleted"}) {
-			return s.buildResult(item, normalized, checkStateBad, false, summary), nil
-		}
-		return s.buildResult(item, normalized, checkStateBad, false, summary), nil
-	case response.ErrorCode != 0 || response.Error != "" || response.ErrorMsg != "":
-		if containsAny(strings.ToLower(response.ErrorMsg), []string{"参数错误", "share_status", "不存在", "失效", "过期", "not found"}) {
-			return s.buildResult(item, normalized, checkStateBad, false, coalesce(response.ErrorMsg, "链接失效")), nil
-		}
-		if containsAny(strings.ToLower(response.Error), []string{"参数错误", "share_status", "不存在", "失效", "过期", "not found"}) {
-			return s.buildResult(item, normalized, checkStateBad, false, coalesce(response.ErrorMsg, response.Error)), nil
-		}
-		if containsAny(strings.ToLower(response.ErrorMsg), []string{"not found", "不存在", "失效", "过期"}) {
-			return s.buildResult(item, normalized, checkStateBad, false, coalesce(response.ErrorMsg, "链接失效")), nil
-		}
-		return s.buildResult(item, normalized, checkStateUncertain, false, coalesce(response.ErrorMsg, response.Error)), nil
-	default:
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法确认链接状态"), nil
-	}
-}
-
-func (s *CheckService) check115(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	shareCode, password := extract115ShareInfo(normalized, item.Password)
-	if shareCode == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法解析分享地址"), nil
-	}
-	if password == "" {
-		return s.buildResult(item, normalized, checkStateLocked, false, "115 需要提取码"), nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	apiURL := fmt.Sprintf("https://115cdn.com/webapi/share/snap?share_code=%s&offset=0&limit=20&receive_code=%s&cid=",
-		url.QueryEscape(shareCode), url.QueryEscape(password))
-
-	body, _, err := s.doRequest(ctx, "GET", apiURL, nil, map[string]string{
-		"priority":            "u=1, i",
-		"referer":             fmt.Sprintf("https://115cdn.com/s/%s?password=%s&", shareCode, password),
-		"x-requested-with":    "XMLHttpRequest",
-		"sec-ch-ua":           `"Chromium";v="142", "Google Chrome";v="142", "Not_A Brand";v="99"`,
-		"sec-ch-ua-mobile":    "?0",
-		"sec-ch-ua-platform":  `"Windows"`,
-		"sec-fetch-dest":      "empty",
-		"sec-fetch-mode":      "cors",
-		"sec-fetch-site":      "same-origin",
-	})
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	var response struct {
-		State bool   `json:"state"`
-		Error string `json:"error"`
-		Errno int    `json:"errno"`
-		Data  struct {
-			List       []any `json:"list"`
-			Count      int   `json:"count"`
-			ShareState int `json:"share_state"`
-			ShareInfo  struct {
-				SnapID       string `json:"snap_id"`
-				ShareTitle   string `json:"share_title"`
-				ShareState   int    `json:"share_state"`
-				ForbidReason string `json:"forbid_reason"`
-			} `json:"shareinfo"`
-		} `json:"data"`
-	}
-	if err := utiljson.Unmarshal(body, &response); err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "响应解析失败"), nil
-	}
-
-	if response.State && response.Errno == 0 {
-		if len(response.Data.List) > 0 || response.Data.Count > 0 || response.Data.ShareInfo.SnapID != "" || response.Data.ShareInfo.ShareTitle != "" {
-			return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-		}
-
-		shareState := response.Data.ShareState
-		if shareState == 0 {
-			shareState = response.Data.ShareInfo.ShareState
-		}
-
-		if shareState == 1 {
-			return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-		}
-
-		reason := strings.TrimSpace(response.Data.ShareInfo.ForbidReason)
-		if reason == "" {
-			reason = fmt.Sprintf("链接状态异常(share_state=%d)", shareState)
-		}
-		if containsAny(strings.ToLower(reason), []string{"密码", "提取码"}) {
-			return s.buildResult(item, normalized, checkStateLocked, false, reason), nil
-		}
-		return s.buildResult(item, normalized, checkStateBad, false, reason), nil
-	}
-
-	if containsAny(strings.ToLower(response.Error), []string{"密码", "提取码", "receive_code"}) {
-		return s.buildResult(item, normalized, checkStateLocked, false, coalesce(response.Error, "需要提取码")), nil
-	}
-
-	if containsAny(strings.ToLower(response.Error), []string{"参数错误", "不存在", "失效", "share_code", "forbid", "forbidden", "违规", "删除", "取消"}) {
-		return s.buildResult(item, normalized, checkStateBad, false, coalesce(response.Error, "链接失效")), nil
-	}
-
-	if response.Error == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法确认链接状态"), nil
-	}
-
-	return s.buildResult(item, normalized, checkStateBad, false, response.Error), nil
-}
-
-func (s *CheckService) checkMobile(item model.CheckItem, normalized string) (model.CheckResult, error) {
-	shareID := extractMobileShareID(normalized)
-	if shareID == "" {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法解析分享地址"), nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	requestPayload := map[string]any{
-		"getOutLinkInfoReq": map[string]any{
-			"account": "",
-			"linkID":  shareID,
-			"passwd":  item.Password,
-			"caSrt":   1,
-			"coSrt":   1,
-			"srtDr":   0,
-			"bNum":    1,
-			"pCaID":   "root",
-			"eNum":    200,
-		},
-		"commonAccountInfo": map[string]any{
-			"account":     "",
-			"accountType": 1,
-		},
-	}
-
-	encrypted, err := encryptMobilePayload(requestPayload)
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求加密失败"), err
-	}
-
-	requestBody, err := utiljson.Marshal(encrypted)
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求序列化失败"), err
-	}
-
-	body, _, err := s.doRequest(ctx, "POST", "https://share-kd-njs.yun.139.com/yun-share/richlifeApp/devapp/IOutLink/getOutLinkInfoV6", strings.NewReader(string(requestBody)), map[string]string{
-		"accept":        "application/json, text/plain, */*",
-		"content-type":  "application/json",
-		"hcy-cool-flag": "1",
-		"x-deviceinfo":  "||3|12.27.0|chrome|131.0.0.0|5c7c68368f048245e1ce47f1c0f8f2d0||windows 10|1536X695|zh-CN|||",
-	})
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "请求失败"), err
-	}
-
-	decrypted, err := decryptMobilePayload(string(body))
-	if err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "响应解密失败"), nil
-	}
-
-	var response map[string]any
-	if err := utiljson.Unmarshal([]byte(decrypted), &response); err != nil {
-		return s.buildResult(item, normalized, checkStateUncertain, false, "响应解析失败"), nil
-	}
-
-	resultCode, _ := response["resultCode"].(string)
-	description, _ := response["desc"].(string)
-	data := response["data"]
-
-	switch {
-	case resultCode == "0" && data != nil:
-		return s.buildResult(item, normalized, checkStateOK, false, "链接有效"), nil
-	case containsAny(strings.ToLower(description), []string{"提取码", "密码", "访问码"}):
-		return s.buildResult(item, normalized, checkStateLocked, false, coalesce(description, "需要提取码")), nil
-	case description != "":
-		if containsAny(strings.ToLower(description), []string{"失效", "不存在", "过期", "取消"}) {
-			return s.buildResult(item, normalized, checkStateBad, false, description), nil
-		}
-		return s.buildResult(item, normalized, checkStateUncertain, false, description), nil
-	case resultCode != "":
-		return s.buildResult(item, normalized, checkStateBad, false, "错误码: "+resultCode), nil
-	default:
-		return s.buildResult(item, normalized, checkStateUncertain, false, "无法确认链接状态"), nil
-	}
-}
-
-func (s *CheckService) doJSONRequest(ctx context.Context, method, targetURL string, payload any, headers map[string]string) ([]byte, int, error) {
-	var reader io.Reader
-	if payload != nil {
-		raw, err := utiljson.Marshal(payload)
-		if err != nil {
-			return nil, 0, err
-		}
-		reader = bytes.NewReader(raw)
-	}
-
-	return s.doRequest(ctx, method, targetURL, reader, headers)
-}
-
-func (s *CheckService) doFormRequest(ctx context.Context, method, targetURL string, form url.Values, headers map[string]string) ([]byte, int, error) {
-	if headers == nil {
-		headers = map[string]string{}
-	}
-	if _, ok := headers["content-type"]; !ok {
-		headers["content-type"] = "application/x-www-form-urlencoded"
-	}
-	return s.doRequest(ctx, method, targetURL, strings.NewReader(form.Encode()), headers)
-}
-
-func (s *CheckService) doRequest(ctx context.Context, method, targetURL string, body io.Reader, headers map[string]string) ([]byte, int, error) {
-	req, err := http.NewRequestWithContext(ctx, method, targetURL, body)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	req.Header.Set("user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36")
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer resp.Body.Close()
-
-	raw, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, resp.StatusCode, err
-	}
-
-	return raw, resp.StatusCode, nil
-}
-
-func (s *CheckService) fetchXunleiCaptchaToken(ctx context.Context) (string, error) {
-	deviceID := "5505bd0cab8c9469b98e5891d9fb3e0d"
-	clientID := "ZUBzD9J_XPXfn7f7"
-	clientVersion := "1.10.0.2633"
-	packageName := "com.xunlei.browser"
-	timestamp, signature := buildXunleiCaptchaSignature(clientID, clientVersion, packageName, deviceID)
-
-	requestBody := map[string]any{
-		"action":        "get:/drive/v1/share",
-		"captcha_token": "",
-		"client_id":     clientID,
-		"device_id":     deviceID,
-		"meta": map[string]any{
-			"timestamp":      timestamp,
-			"captcha_sign":   signature,
-			"client_version": clientVersion,
-			"package_name":   packageName,
-		},
-		"redirect_uri": "xlaccsdk01://xunlei.com/callback?state=harbor",
-	}
-
-	body, _, err := s.doJSONRequest(ctx, "POST", "https://xluser-ssl.xunlei.com/v1/shield/captcha/init", requestBody, map[string]string{
-		"accept":           "application/json;charset=UTF-8",
-		"content-type":     "application/json",
-		"x-device-id":      deviceID,
-		"x-client-id":      clientID,
-		"x-client-version": clientVersion,
-	})
-	if err != nil {
-		return "", err
-	}
-
-	var response struct {
-		CaptchaToken string `json:"captcha_token"`
-		URL          string `json:"url"`
-	}
-	if err := utiljson.Unmarshal(body, &response); err != nil {
-		return "", err
-	}
-	if response.URL != "" {
-		return "", fmt.Errorf("xunlei captcha required")
-	}
-	return response.CaptchaToken, nil
-}
-
-func (s *CheckService) buildResult(item model.CheckItem, normalized string, state string, cacheHit bool, summary string) model.CheckResult {
-	now := time.Now()
-	expiresAt := now.Add(ttlForState(state))
-
-	return model.CheckResult{
-		DiskType:      item.DiskType,
-		URL:           item.URL,
-		NormalizedURL: normalized,
-		State:         state,
-		CacheHit:      cacheHit,
-		CheckedAt:     now.UnixMilli(),
-		ExpiresAt:     expiresAt.UnixMilli(),
-		Summary:       summary,
-	}
-}
-
-func (s *CheckService) normalizeShareLink(diskType, rawURL, password string) string {
-	base := strings.TrimSpace(rawURL)
-	if base == "" {
-		return ""
-	}
-
-	parsed, err := url.Parse(base)
-	if err != nil {
-		return base
-	}
-
-	parsed.Fragment = ""
-	parsed.Host = strings.ToLower(parsed.Host)
-
-	query := parsed.Query()
-	if password != "" {
-		switch diskType {
-		case "baidu", "quark", "uc":
-			if query.Get("pwd") == "" {
-				query.Set("pwd", password)
-			}
-		}
-	}
-
-	parsed.RawQuery = query.Encode()
-	return parsed.String()
-}
-
-func ttlForState(state string) time.Duration {
-	switch state {
-	case checkStateOK:
-		return 24 * time.Hour
-	case checkStateBad:
-		return 6 * time.Hour
-	case checkStateLocked:
-		return 12 * time.Hour
-	case checkStateUnsupported:
-		return 24 * time.Hour
-	default:
-		return 30 * time.Minute
-	}
-}
-
 func (s *CheckService) openCacheStore() {
 	if s.cacheFile == "" {
 		return
@@ -1002,6 +373,7 @@ func encodeCachedCheckEntry(entry cachedCheckResult) ([]byte, error) {
 	payload := cachedCheckDiskEntry{
 		Result:    entry.result,
 		ExpiresAt: entry.expiresAt.UnixMilli(),
+		Password:  entry.password,
 	}
 
 	var buf bytes.Buffer
@@ -1021,6 +393,7 @@ func decodeCachedCheckEntry(raw []byte) (cachedCheckResult, error) {
 	return cachedCheckResult{
 		result:    payload.Result,
 		expiresAt: time.UnixMilli(payload.ExpiresAt),
+		password:  payload.Password,
 	}, nil
 }
 
@@ -1055,6 +428,34 @@ func (s *CheckService) loadPersistentCache(key string) (cachedCheckResult, bool)
 	return entry, found
 }
 
+// loadPersistentCaches 在一个读事务中读取多条持久化的检测结果
+func (s *CheckService) loadPersistentCaches(keys map[string][]int) map[string]cachedCheckResult {
+	entries := make(map[string]cachedCheckResult)
+	if s.cacheDB == nil {
+		return entries
+	}
+
+	_ = s.cacheDB.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(checkCacheBucketName))
+		if bucket == nil {
+			return nil
+		}
+
+		for key := range keys {
+			raw := bucket.Get([]byte(key))
+			if len(raw) == 0 {
+				continue
+			}
+			if decoded, err := decodeCachedCheckEntry(raw); err == nil {
+				entries[key] = decoded
+			}
+		}
+		return nil
+	})
+
+	return entries
+}
+
 func (s *CheckService) savePersistentCache(key string, entry cachedCheckResult) {
 	if s.cacheDB == nil {
 		return
@@ -1088,6 +489,26 @@ func (s *CheckService) deletePersistentCache(key string) {
 	})
 }
 
+// deletePersistentCaches 在一个写事务中删除多条持久化的检测结果
+func (s *CheckService) deletePersistentCaches(keys []string) {
+	if s.cacheDB == nil {
+		return
+	}
+
+	_ = s.cacheDB.Batch(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(checkCacheBucketName))
+		if bucket == nil {
+			return nil
+		}
+		for _, key := range keys {
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (s *CheckService) pruneExpiredCacheStore() {
 	if s.cacheDB == nil {
 		return
@@ -1120,244 +541,3 @@ func (s *CheckService) pruneExpiredCacheStore() {
 		return nil
 	})
 }
-
-func buildXunleiCaptchaSignature(clientID, clientVersion, packageName, deviceID string) (string, string) {
-	timestamp := fmt.Sprint(time.Now().UnixMilli())
-	content := fmt.Sprint(clientID, clientVersion, packageName, deviceID, timestamp)
-	parts := []string{
-		"uWRwO7gPfdPB/0NfPtfQO+71",
-		"F93x+qPluYy6jdgNpq+lwdH1ap6WOM+nfz8/V",
-		"0HbpxvpXFsBK5CoTKam",
-		"dQhzbhzFRcawnsZqRETT9AuPAJ+wTQso82mRv",
-		"SAH98AmLZLRa6DB2u68sGhyiDh15guJpXhBzI",
-		"unqfo7Z64Rie9RNHMOB",
-		"7yxUdFADp3DOBvXdz0DPuKNVT35wqa5z0DEyEvf",
-		"RBG",
-		"ThTWPG5eC0UBqlbQ+04nZAptqGCdpv9o55A",
-	}
-
-	for _, part := range parts {
-		sum := md5.Sum([]byte(content + part))
-		content = fmt.Sprintf("%x", sum)
-	}
-
-	return timestamp, "1." + content
-}
-
-func containsAny(content string, keywords []string) bool {
-	for _, keyword := range keywords {
-		if strings.Contains(content, strings.ToLower(keyword)) {
-			return true
-		}
-	}
-	return false
-}
-
-func coalesce(values ...string) string {
-	for _, value := range values {
-		if strings.TrimSpace(value) != "" {
-			return value
-		}
-	}
-	return ""
-}
-
-func decompressResponseBody(raw []byte, acceptedEncoding string, contentEncoding string) ([]byte, error) {
-	encoding := strings.ToLower(contentEncoding)
-	if encoding == "" {
-		encoding = strings.ToLower(acceptedEncoding)
-	}
-
-	if strings.Contains(encoding, "gzip") {
-		reader, err := gzip.NewReader(bytes.NewReader(raw))
-		if err != nil {
-			return raw, err
-		}
-		defer reader.Close()
-		return io.ReadAll(reader)
-	}
-
-	if strings.Contains(encoding, "deflate") {
-		reader, err := zlib.NewReader(bytes.NewReader(raw))
-		if err != nil {
-			return raw, err
-		}
-		defer reader.Close()
-		return io.ReadAll(reader)
-	}
-
-	return raw, nil
-}
-
-func extractAliyunShareID(rawURL string) string {
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return ""
-	}
-
-	pathParts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
-	if len(pathParts) == 0 {
-		return ""
-	}
-
-	return pathParts[len(pathParts)-1]
-}
-
-func extractQuarkShareIDAndPassword(rawURL string) (string, string) {
-	re := regexp.MustCompile(`/s/([A-Za-z0-9]+)`)
-	matches := re.FindStringSubmatch(rawURL)
-	if len(matches) < 2 {
-		return "", ""
-	}
-
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return matches[1], ""
-	}
-
-	return matches[1], parsed.Query().Get("pwd")
-}
-
-func extractBaiduShareInfo(rawURL string) (string, string, string) {
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return "", "", ""
-	}
-
-	queryPwd := parsed.Query().Get("pwd")
-
-	if strings.HasPrefix(parsed.Path, "/s/") {
-		shareID := strings.TrimPrefix(parsed.Path, "/s/")
-		shortURL := shareID
-		if strings.HasPrefix(shortURL, "1") && len(shortURL) > 1 {
-			shortURL = shortURL[1:]
-		}
-		return shareID, shortURL, queryPwd
-	}
-
-	if strings.HasPrefix(parsed.Path, "/share/init") {
-		shareID := parsed.Query().Get("surl")
-		shortURL := shareID
-		if strings.HasPrefix(shortURL, "1") && len(shortURL) > 1 {
-			shortURL = shortURL[1:]
-		}
-		return shareID, shortURL, queryPwd
-	}
-
-	return "", "", queryPwd
-}
-
-func extractTianyiShareInfo(rawURL string, fallbackPassword string) (string, string, string) {
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return "", fallbackPassword, rawURL
-	}
-
-	shareCode := parsed.Query().Get("code")
-	if shareCode == "" && strings.HasPrefix(parsed.Path, "/t/") {
-		shareCode = strings.TrimPrefix(parsed.Path, "/t/")
-	}
-	if shareCode == "" && strings.HasPrefix(parsed.Fragment, "/t/") {
-		shareCode = strings.TrimPrefix(parsed.Fragment, "/t/")
-	}
-
-	if index := strings.Index(shareCode, "/"); index >= 0 {
-		shareCode = shareCode[:index]
-	}
-
-	password := fallbackPassword
-	re := regexp.MustCompile(`（访问码[：:]\s*([a-zA-Z0-9]+)）`)
-	matches := re.FindStringSubmatch(rawURL)
-	if len(matches) >= 2 && matches[1] != "" {
-		password = matches[1]
-	}
-
-	return shareCode, password, rawURL
-}
-
-func extract123ShareKey(rawURL string) string {
-	patterns := []string{
-		`https?://(?:www\.)?(?:123684|123685|123912|123pan|123592|123865)\.com/s/([a-zA-Z0-9-]+)`,
-		`https?://(?:www\.)?123pan\.cn/s/([a-zA-Z0-9-]+)`,
-	}
-
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(rawURL)
-		if len(matches) >= 2 {
-			return matches[1]
-		}
-	}
-
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return ""
-	}
-
-	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
-	if len(parts) == 0 {
-		return ""
-	}
-
-	return parts[len(parts)-1]
-}
-
-func extractXunleiShareInfo(rawURL string) (string, string) {
-	re := regexp.MustCompile(`pan\.xunlei\.com/s/([^?/#]+)`)
-	matches := re.FindStringSubmatch(rawURL)
-	if len(matches) < 2 {
-		return "", ""
-	}
-
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return matches[1], ""
-	}
-
-	return matches[1], parsed.Query().Get("pwd")
-}
-
-func extract115ShareInfo(rawURL string, fallbackPassword string) (string, string) {
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return "", fallbackPassword
-	}
-
-	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
-	if len(parts) == 0 {
-		return "", fallbackPassword
-	}
-
-	shareCode := parts[len(parts)-1]
-	password := parsed.Query().Get("password")
-	if password == "" {
-		password = fallbackPassword
-	}
-
-	if password == "" && parsed.Fragment != "" && strings.Contains(parsed.Fragment, "password=") {
-		if values, err := url.ParseQuery(parsed.Fragment); err == nil {
-			password = values.Get("password")
-		}
-	}
-
-	return shareCode, password
-}
-
-func extractMobileShareID(rawURL string) string {
-	patterns := []string{
-		`https?://(?:www\.)?yun\.139\.com/shareweb/#/w/i/([^&/?#]+)`,
-		`https?://(?:www\.)?caiyun\.139\.com/w/i/([^&/?#]+)`,
-		`https?://(?:www\.)?caiyun\.139\.com/m/i\?([^&/?#]+)`,
-		`https?://caiyun\.feixin\.10086\.cn/([^&/?#]+)`,
-	}
-
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(rawURL)
-		if len(matches) >= 2 {
-			return matches[1]
-		}
-	}
-
-	return ""
-}
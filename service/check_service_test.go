@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pansou/checker"
+	"pansou/model"
+	"pansou/util/ratelimit"
+)
+
+// stubLinkChecker 测试用检测器，按链接后缀（-bad、-locked）决定检测结果，记录每次检测的开始时间
+type stubLinkChecker struct {
+	diskType string
+	delay    func(item model.CheckItem) time.Duration
+	block    chan struct{} // 非nil时链接含"/slow"的检测阻塞到通道关闭
+
+	mu    sync.Mutex
+	calls []time.Time
+	urls  []string
+}
+
+// registerStubChecker 注册测试检测器，每个测试使用不同的网盘类型避免互相影响
+func registerStubChecker(t *testing.T, diskType string) *stubLinkChecker {
+	t.Helper()
+	stub := &stubLinkChecker{diskType: diskType}
+	checker.RegisterGlobalChecker(stub)
+	return stub
+}
+
+func (c *stubLinkChecker) DiskType() string {
+	return c.diskType
+}
+
+func (c *stubLinkChecker) Normalize(rawURL, password string) string {
+	return checker.NormalizeShareLink(rawURL, password, "")
+}
+
+func (c *stubLinkChecker) Check(ctx context.Context, item model.CheckItem) (model.CheckResult, error) {
+	c.mu.Lock()
+	c.calls = append(c.calls, time.Now())
+	c.urls = append(c.urls, item.URL)
+	c.mu.Unlock()
+
+	if c.delay != nil {
+		time.Sleep(c.delay(item))
+	}
+	if c.block != nil && strings.Contains(item.URL, "/slow") {
+		<-c.block
+	}
+
+	state := checker.StateOK
+	switch {
+	case strings.HasSuffix(item.URL, "-"+checker.StateBad):
+		state = checker.StateBad
+	case strings.HasSuffix(item.URL, "-"+checker.StateLocked):
+		state = checker.StateLocked
+	}
+	return checker.BuildResult(item, c.Normalize(item.URL, item.Password), state, "stub"), nil
+}
+
+func (c *stubLinkChecker) callTimes() []time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := append([]time.Time(nil), c.calls...)
+	sort.Slice(calls, func(i, j int) bool { return calls[i].Before(calls[j]) })
+	return calls
+}
+
+func (c *stubLinkChecker) checkedURLs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.urls...)
+}
+
+func TestCheckPreservesOrder(t *testing.T) {
+	stub := registerStubChecker(t, "stubcheck-order")
+	s := newTestCheckService(t)
+	s.concurrency = 4
+	s.batchTimeout = 5 * time.Second
+	s.limiter = ratelimit.NewLimiter(0, 0)
+
+	// 越靠前的链接检测越慢，完成顺序与输入顺序相反
+	delays := make(map[string]time.Duration)
+	var items []model.CheckItem
+	var states []string
+	for i := 0; i < 10; i++ {
+		state := checker.StateOK
+		if i%3 == 1 {
+			state = checker.StateBad
+		}
+		item := model.CheckItem{DiskType: "stubcheck-order", URL: fmt.Sprintf("https://stub.example/s/%d-%s", i, state)}
+		delays[item.URL] = time.Duration(10-i) * 3 * time.Millisecond
+		items = append(items, item)
+		states = append(states, state)
+	}
+	stub.delay = func(item model.CheckItem) time.Duration { return delays[item.URL] }
+
+	items = append(items,
+		model.CheckItem{DiskType: "stubcheck-unknown", URL: "https://unknown.example/s/1"},
+		model.CheckItem{DiskType: "stubcheck-order", URL: "  "},
+	)
+	states = append(states, checker.StateUnsupported, checker.StateUncertain)
+
+	response := s.Check(context.Background(), items)
+	if len(response.Results) != len(items) {
+		t.Fatalf("len(Results) = %d, want %d", len(response.Results), len(items))
+	}
+	for i, result := range response.Results {
+		if result.URL != items[i].URL || result.State != states[i] {
+			t.Errorf("Results[%d] = %q %q, want %q %q", i, result.URL, result.State, items[i].URL, states[i])
+		}
+	}
+}
+
+func TestCheckBatchDeadline(t *testing.T) {
+	stub := registerStubChecker(t, "stubcheck-deadline")
+	stub.block = make(chan struct{})
+	released := false
+	release := func() {
+		if !released {
+			released = true
+			close(stub.block)
+		}
+	}
+	t.Cleanup(release)
+
+	s := newTestCheckService(t)
+	s.concurrency = 2
+	s.batchTimeout = 100 * time.Millisecond
+	s.limiter = ratelimit.NewLimiter(0, 0)
+
+	// 两个worker：一个检测fast后卡在slow-2，另一个卡在slow-1，queued始终没有开始检测
+	items := []model.CheckItem{
+		{DiskType: "stubcheck-deadline", URL: "https://stub.example/s/fast"},
+		{DiskType: "stubcheck-deadline", URL: "https://stub.example/slow-1"},
+		{DiskType: "stubcheck-deadline", URL: "https://stub.example/slow-2"},
+		{DiskType: "stubcheck-deadline", URL: "https://stub.example/s/queued"},
+	}
+
+	start := time.Now()
+	response := s.Check(context.Background(), items)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Check() 用时 %v，应在批次截止时返回", elapsed)
+	}
+
+	want := []string{checker.StateOK, checker.StateUncertain, checker.StateUncertain, checker.StateUncertain}
+	for i, result := range response.Results {
+		if result.URL != items[i].URL || result.State != want[i] {
+			t.Errorf("Results[%d] = %q %q, want %q %q", i, result.URL, result.State, items[i].URL, want[i])
+		}
+		if result.State == checker.StateUncertain && result.Summary != "检测超时" {
+			t.Errorf("Results[%d].Summary = %q, want 检测超时", i, result.Summary)
+		}
+	}
+	for _, url := range stub.checkedURLs() {
+		if strings.HasSuffix(url, "/queued") {
+			t.Error("截止后不应再开始新的检测")
+		}
+	}
+
+	// 已发出的检测在后台完成后写入缓存
+	release()
+	deadline := time.Now().Add(2 * time.Second)
+	for _, item := range items[1:3] {
+		for {
+			if cached, ok := s.getCached(checkCacheKey(item)); ok {
+				if cached.State != checker.StateOK {
+					t.Errorf("%s 缓存的State = %q, want %q", item.URL, cached.State, checker.StateOK)
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s 的检测结果没有写入缓存", item.URL)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+}
+
+func TestCheckRateLimit(t *testing.T) {
+	t.Run("按网盘分别限流", func(t *testing.T) {
+		limited := registerStubChecker(t, "stubcheck-rate-a")
+		other := registerStubChecker(t, "stubcheck-rate-b")
+
+		s := newTestCheckService(t)
+		s.concurrency = 8
+		s.batchTimeout = 5 * time.Second
+		s.limiter = ratelimit.NewLimiter(20, 2)
+
+		var items []model.CheckItem
+		for i := 0; i < 6; i++ {
+			items = append(items, model.CheckItem{DiskType: "stubcheck-rate-a", URL: fmt.Sprintf("https://stub.example/a/%d", i)})
+		}
+
+		start := time.Now()
+		response := s.Check(context.Background(), items)
+		for i, result := range response.Results {
+			if result.State != checker.StateOK {
+				t.Errorf("Results[%d].State = %q, want %q", i, result.State, checker.StateOK)
+			}
+		}
+
+		// 桶容量2、每秒20个令牌：前两个立即检测，之后每50ms一个
+		calls := limited.callTimes()
+		if len(calls) != 6 {
+			t.Fatalf("stubcheck-rate-a 检测了 %d 次, want 6", len(calls))
+		}
+		for i := 2; i < len(calls); i++ {
+			if earliest := time.Duration(i-1)*50*time.Millisecond - 10*time.Millisecond; calls[i].Sub(start) < earliest {
+				t.Errorf("第%d次检测在 %v 开始，限流下应不早于 %v", i+1, calls[i].Sub(start), earliest)
+			}
+		}
+
+		// stubcheck-rate-a的令牌已用完，其他网盘仍可立即检测
+		start = time.Now()
+		s.Check(context.Background(), []model.CheckItem{
+			{DiskType: "stubcheck-rate-b", URL: "https://stub.example/b/1"},
+			{DiskType: "stubcheck-rate-b", URL: "https://stub.example/b/2"},
+		})
+		for i, call := range other.callTimes() {
+			if call.Sub(start) > 40*time.Millisecond {
+				t.Errorf("stubcheck-rate-b 第%d次检测在 %v 开始，不应被其他网盘限流", i+1, call.Sub(start))
+			}
+		}
+	})
+
+	t.Run("截止前等不到令牌", func(t *testing.T) {
+		stub := registerStubChecker(t, "stubcheck-rate-wait")
+
+		s := newTestCheckService(t)
+		s.concurrency = 3
+		s.batchTimeout = 200 * time.Millisecond
+		s.limiter = ratelimit.NewLimiter(1, 1)
+
+		items := []model.CheckItem{
+			{DiskType: "stubcheck-rate-wait", URL: "https://stub.example/s/1"},
+			{DiskType: "stubcheck-rate-wait", URL: "https://stub.example/s/2"},
+			{DiskType: "stubcheck-rate-wait", URL: "https://stub.example/s/3"},
+		}
+
+		start := time.Now()
+		response := s.Check(context.Background(), items)
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Errorf("Check() 用时 %v，等不到令牌时应立即返回", elapsed)
+		}
+
+		states := make(map[string]int)
+		for _, result := range response.Results {
+			states[result.State]++
+		}
+		if states[checker.StateOK] != 1 || states[checker.StateUncertain] != 2 {
+			t.Errorf("结果状态 = %v, want 1个ok、2个uncertain", states)
+		}
+		if calls := len(stub.callTimes()); calls != 1 {
+			t.Errorf("检测器调用了 %d 次, want 1", calls)
+		}
+	})
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"pansou/checker"
+	"pansou/model"
+)
+
+// 复检记录保留的最近状态变化数
+const maxRecentTransitions = 100
+
+// errRecheckUncertain 复检结果无法确认时保留原有缓存，不以uncertain覆盖
+var errRecheckUncertain = errors.New("复检结果无法确认")
+
+type sweepCandidate struct {
+	key       string
+	item      model.CheckItem
+	state     string
+	hits      int
+	expiresAt time.Time
+}
+
+// CheckSweeper 检测缓存后台复检
+// 定期复检即将过期的有效（ok/locked）链接，在搜索结果中出现次数多的优先；
+// 复检与普通检测共用按网盘的令牌桶限流，结果直接刷新检测缓存。
+type CheckSweeper struct {
+	service  *CheckService
+	interval time.Duration
+	window   time.Duration
+	batch    int
+
+	mu     sync.Mutex
+	status model.CheckSweeperStatus
+}
+
+func newCheckSweeper(service *CheckService, interval, window time.Duration, batch int) *CheckSweeper {
+	return &CheckSweeper{
+		service:  service,
+		interval: interval,
+		window:   window,
+		batch:    batch,
+		status: model.CheckSweeperStatus{
+			Enabled:           true,
+			IntervalSeconds:   int64(interval / time.Second),
+			WindowSeconds:     int64(window / time.Second),
+			RecentTransitions: []model.CheckTransition{},
+			RecentDeaths:      []model.CheckTransition{},
+		},
+	}
+}
+
+func (w *CheckSweeper) start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			w.sweep(context.Background())
+		}
+	}()
+}
+
+// Status 返回复检进度和最近的状态变化
+func (w *CheckSweeper) Status() model.CheckSweeperStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := w.status
+	status.RecentTransitions = append([]model.CheckTransition(nil), w.status.RecentTransitions...)
+	status.RecentDeaths = append([]model.CheckTransition(nil), w.status.RecentDeaths...)
+	return status
+}
+
+// sweep 执行一轮复检
+func (w *CheckSweeper) sweep(ctx context.Context) {
+	candidates := w.service.sweepCandidates(w.window, w.batch)
+
+	w.mu.Lock()
+	w.status.Running = true
+	w.status.LastStartedAt = time.Now().UnixMilli()
+	w.status.Candidates = len(candidates)
+	w.status.Rechecked = 0
+	w.status.Transitions = 0
+	w.mu.Unlock()
+
+	indexes := make(chan int, len(candidates))
+	for i := range candidates {
+		indexes <- i
+	}
+	close(indexes)
+
+	workers := w.service.concurrency
+	if workers <= 0 || workers > len(candidates) {
+		workers = len(candidates)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				candidate := candidates[index]
+				result, ok := w.service.recheck(ctx, candidate.key, candidate.item)
+				if ok {
+					w.record(candidate, result)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	w.service.pruneExpiredCacheStore()
+
+	w.mu.Lock()
+	w.status.Running = false
+	w.status.LastFinishedAt = time.Now().UnixMilli()
+	w.mu.Unlock()
+}
+
+// record 记录一次复检结果，状态变化时保存到最近变化列表
+func (w *CheckSweeper) record(candidate sweepCandidate, result model.CheckResult) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.status.Rechecked++
+	w.status.TotalRechecked++
+
+	if result.State == candidate.state {
+		return
+	}
+
+	transition := model.CheckTransition{
+		DiskType: candidate.item.DiskType,
+		URL:      candidate.item.URL,
+		From:     candidate.state,
+		To:       result.State,
+		Summary:  result.Summary,
+		At:       result.CheckedAt,
+	}
+	w.status.Transitions++
+	w.status.TotalTransitions++
+	w.status.RecentTransitions = appendRecentTransition(w.status.RecentTransitions, transition)
+
+	if result.State == checker.StateBad {
+		w.status.TotalDeaths++
+		w.status.RecentDeaths = appendRecentTransition(w.status.RecentDeaths, transition)
+		fmt.Printf("[链接复检] %s 链接已失效: %s (%s)\n", transition.DiskType, transition.URL, transition.Summary)
+	}
+}
+
+// appendRecentTransition 追加状态变化，最新的在前，超出上限时丢弃最旧的
+func appendRecentTransition(list []model.CheckTransition, transition model.CheckTransition) []model.CheckTransition {
+	list = append([]model.CheckTransition{transition}, list...)
+	if len(list) > maxRecentTransitions {
+		list = list[:maxRecentTransitions]
+	}
+	return list
+}
+
+// SweeperStatus 返回后台复检进度，未启用复检时Enabled为false
+func (s *CheckService) SweeperStatus() model.CheckSweeperStatus {
+	if s.sweeper == nil {
+		return model.CheckSweeperStatus{
+			RecentTransitions: []model.CheckTransition{},
+			RecentDeaths:      []model.CheckTransition{},
+		}
+	}
+	return s.sweeper.Status()
+}
+
+// sweepCandidates 收集window内即将过期的有效链接，按出现次数和过期时间排序，最多返回limit个
+func (s *CheckService) sweepCandidates(window time.Duration, limit int) []sweepCandidate {
+	now := time.Now()
+	deadline := now.Add(window)
+	entries := make(map[string]cachedCheckResult)
+
+	if s.cacheDB != nil {
+		_ = s.cacheDB.View(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket([]byte(checkCacheBucketName))
+			if bucket == nil {
+				return nil
+			}
+			return bucket.ForEach(func(key, value []byte) error {
+				if entry, err := decodeCachedCheckEntry(value); err == nil {
+					entries[string(key)] = entry
+				}
+				return nil
+			})
+		})
+	}
+
+	s.mu.Lock()
+	for key, entry := range s.cache {
+		entries[key] = entry
+	}
+
+	candidates := make([]sweepCandidate, 0)
+	for key, entry := range entries {
+		state := entry.result.State
+		if state != checker.StateOK && state != checker.StateLocked {
+			continue
+		}
+		if entry.expiresAt.Before(now) || entry.expiresAt.After(deadline) {
+			continue
+		}
+		if _, inflight := s.inflight[key]; inflight {
+			continue
+		}
+
+		candidates = append(candidates, sweepCandidate{
+			key: key,
+			item: model.CheckItem{
+				DiskType: entry.result.DiskType,
+				URL:      entry.result.URL,
+				Password: entry.password,
+			},
+			state:     state,
+			hits:      s.hits[key],
+			expiresAt: entry.expiresAt,
+		})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].hits != candidates[j].hits {
+			return candidates[i].hits > candidates[j].hits
+		}
+		return candidates[i].expiresAt.Before(candidates[j].expiresAt)
+	})
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// recheck 忽略缓存重新检测一个链接并刷新缓存；链接正在检测或检测失败时返回false
+func (s *CheckService) recheck(ctx context.Context, key string, item model.CheckItem) (model.CheckResult, bool) {
+	linkChecker, ok := checker.GetChecker(item.DiskType)
+	if !ok {
+		return model.CheckResult{}, false
+	}
+
+	call, wait := s.acquireInflight(key)
+	if wait {
+		return model.CheckResult{}, false
+	}
+
+	if err := s.limiter.Wait(ctx, item.DiskType); err != nil {
+		s.finishInflight(key, item, call, model.CheckResult{}, err)
+		return model.CheckResult{}, false
+	}
+
+	result, err := linkChecker.Check(ctx, item)
+	if err == nil && result.State == checker.StateUncertain {
+		err = errRecheckUncertain
+	}
+	s.finishInflight(key, item, call, result, err)
+	if err != nil {
+		return model.CheckResult{}, false
+	}
+	return result, true
+}
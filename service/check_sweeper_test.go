@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"pansou/checker"
+	"pansou/model"
+	"pansou/util/ratelimit"
+)
+
+// seedSweepEntry 写入一条检测缓存，persist为true时只写磁盘，否则只写内存
+func seedSweepEntry(s *CheckService, item model.CheckItem, state string, expiresIn time.Duration, hits int, persist bool) string {
+	key := checkCacheKey(item)
+	entry := cachedCheckResult{
+		result:    model.CheckResult{DiskType: item.DiskType, URL: item.URL, State: state},
+		expiresAt: time.Now().Add(expiresIn),
+		password:  item.Password,
+	}
+	if persist {
+		s.savePersistentCache(key, entry)
+	} else {
+		s.cache[key] = entry
+	}
+	if hits > 0 {
+		s.hits[key] = hits
+	}
+	return key
+}
+
+func TestSweepCandidates(t *testing.T) {
+	registerStubChecker(t, "stubsweep-select")
+	s := newTestCheckService(t)
+
+	link := func(name string) model.CheckItem {
+		return model.CheckItem{DiskType: "stubsweep-select", URL: "https://stub.example/s/" + name, Password: "pw"}
+	}
+	popular := seedSweepEntry(s, link("popular"), checker.StateLocked, 40*time.Minute, 5, true)
+	sooner := seedSweepEntry(s, link("sooner"), checker.StateOK, 5*time.Minute, 1, false)
+	soon := seedSweepEntry(s, link("soon"), checker.StateOK, 20*time.Minute, 1, false)
+	seedSweepEntry(s, link("bad"), checker.StateBad, 10*time.Minute, 9, false)
+	seedSweepEntry(s, link("uncertain"), checker.StateUncertain, 10*time.Minute, 9, false)
+	seedSweepEntry(s, link("later"), checker.StateOK, 3*time.Hour, 9, false)
+	seedSweepEntry(s, link("expired"), checker.StateOK, -time.Minute, 9, false)
+	busy := seedSweepEntry(s, link("busy"), checker.StateOK, 10*time.Minute, 9, false)
+	s.inflight[busy] = &activeCheckCall{done: make(chan struct{})}
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		limit  int
+		want   []string
+	}{
+		{name: "窗口内的有效链接按出现次数和过期时间排序", window: time.Hour, want: []string{popular, sooner, soon}},
+		{name: "CheckSweepBatch限制数量", window: time.Hour, limit: 2, want: []string{popular, sooner}},
+		{name: "窗口外的不复检", window: 10 * time.Minute, want: []string{sooner}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := s.sweepCandidates(tt.window, tt.limit)
+			if len(candidates) != len(tt.want) {
+				t.Fatalf("sweepCandidates() 返回 %d 个, want %d: %+v", len(candidates), len(tt.want), candidates)
+			}
+			for i, candidate := range candidates {
+				if candidate.key != tt.want[i] {
+					t.Errorf("candidates[%d] = %q, want %q", i, candidate.key, tt.want[i])
+				}
+				if candidate.item.Password != "pw" {
+					t.Errorf("candidates[%d] 的提取码 = %q, want pw", i, candidate.item.Password)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSweeperSweep(t *testing.T) {
+	stub := registerStubChecker(t, "stubsweep-run")
+	s := newTestCheckService(t)
+	s.concurrency = 2
+	s.limiter = ratelimit.NewLimiter(0, 0)
+	w := newCheckSweeper(s, time.Minute, time.Hour, 2)
+
+	link := func(name string) model.CheckItem {
+		return model.CheckItem{DiskType: "stubsweep-run", URL: "https://stub.example/s/" + name}
+	}
+	// 检测器按链接后缀返回状态：alive仍有效，dead-bad已失效，rare-locked出现次数少，第一轮排不上
+	seedSweepEntry(s, link("alive"), checker.StateOK, 10*time.Minute, 3, false)
+	dead := seedSweepEntry(s, link("dead-bad"), checker.StateOK, 20*time.Minute, 2, true)
+	seedSweepEntry(s, link("rare-locked"), checker.StateLocked, 30*time.Minute, 0, false)
+
+	rounds := []struct {
+		want model.CheckSweeperStatus
+	}{
+		{want: model.CheckSweeperStatus{Candidates: 2, Rechecked: 2, Transitions: 1, TotalRechecked: 2, TotalTransitions: 1, TotalDeaths: 1}},
+		{want: model.CheckSweeperStatus{Candidates: 1, Rechecked: 1, Transitions: 0, TotalRechecked: 3, TotalTransitions: 1, TotalDeaths: 1}},
+		{want: model.CheckSweeperStatus{Candidates: 0, Rechecked: 0, Transitions: 0, TotalRechecked: 3, TotalTransitions: 1, TotalDeaths: 1}},
+	}
+	for i, round := range rounds {
+		w.sweep(context.Background())
+		got := w.Status()
+		if got.Running || got.LastFinishedAt == 0 {
+			t.Errorf("第%d轮: Running = %v, LastFinishedAt = %d", i+1, got.Running, got.LastFinishedAt)
+		}
+		if got.Candidates != round.want.Candidates || got.Rechecked != round.want.Rechecked || got.Transitions != round.want.Transitions ||
+			got.TotalRechecked != round.want.TotalRechecked || got.TotalTransitions != round.want.TotalTransitions || got.TotalDeaths != round.want.TotalDeaths {
+			t.Errorf("第%d轮: status = %+v, want %+v", i+1, got, round.want)
+		}
+	}
+
+	if calls := len(stub.callTimes()); calls != 3 {
+		t.Errorf("检测器调用了 %d 次, want 3", calls)
+	}
+
+	status := w.Status()
+	if len(status.RecentDeaths) != 1 || len(status.RecentTransitions) != 1 {
+		t.Fatalf("RecentDeaths = %+v, RecentTransitions = %+v", status.RecentDeaths, status.RecentTransitions)
+	}
+	death := status.RecentDeaths[0]
+	if death.URL != link("dead-bad").URL || death.From != checker.StateOK || death.To != checker.StateBad {
+		t.Errorf("RecentDeaths[0] = %+v", death)
+	}
+
+	// 复检结果刷新缓存
+	if cached, ok := s.getCached(dead); !ok || cached.State != checker.StateBad {
+		t.Errorf("复检后的缓存 = %+v, %v, want %q", cached, ok, checker.StateBad)
+	}
+}
+
+func TestCheckSweeperStatus(t *testing.T) {
+	t.Run("未启用", func(t *testing.T) {
+		s := newTestCheckService(t)
+		body, err := json.Marshal(s.SweeperStatus())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"enabled":false,"running":false,"interval_seconds":0,"window_seconds":0,"candidates":0,"rechecked":0,"transitions":0,` +
+			`"total_rechecked":0,"total_transitions":0,"total_deaths":0,"recent_transitions":[],"recent_deaths":[]}`
+		if string(body) != want {
+			t.Errorf("SweeperStatus() = %s, want %s", body, want)
+		}
+	})
+
+	t.Run("按间隔自动复检", func(t *testing.T) {
+		registerStubChecker(t, "stubsweep-start")
+		s := newTestCheckService(t)
+		s.concurrency = 2
+		s.limiter = ratelimit.NewLimiter(0, 0)
+
+		for _, name := range []string{"a", "b-bad", "c"} {
+			seedSweepEntry(s, model.CheckItem{DiskType: "stubsweep-start", URL: "https://stub.example/s/" + name}, checker.StateOK, 10*time.Minute, 1, false)
+		}
+
+		s.sweeper = newCheckSweeper(s, 20*time.Millisecond, time.Hour, 2)
+		s.sweeper.start()
+
+		// 每轮最多复检2个，第二轮复检剩下的1个
+		deadline := time.Now().Add(2 * time.Second)
+		var status model.CheckSweeperStatus
+		for {
+			status = s.SweeperStatus()
+			if status.TotalRechecked == 3 && !status.Running {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("后台复检没有完成: %+v", status)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if !status.Enabled || status.IntervalSeconds != 0 || status.WindowSeconds != 3600 {
+			t.Errorf("status = %+v", status)
+		}
+		if status.TotalTransitions != 1 || status.TotalDeaths != 1 || len(status.RecentDeaths) != 1 {
+			t.Errorf("TotalTransitions = %d, TotalDeaths = %d, RecentDeaths = %+v", status.TotalTransitions, status.TotalDeaths, status.RecentDeaths)
+		}
+
+		body, err := json.Marshal(status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range []string{"candidates", "rechecked", "transitions", "total_rechecked", "total_transitions", "total_deaths", "last_started_at", "last_finished_at"} {
+			if _, ok := fields[field]; !ok {
+				t.Errorf("SweeperStatus() JSON 缺少 %s: %s", field, body)
+			}
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pansou/checker"
+	"pansou/config"
+	"pansou/plugin"
+	"pansou/util/metrics"
+)
+
+// metricLabelOther 不在配置或注册列表中的标签值统一记为other
+// 频道和网盘类型来自请求参数，直接作为标签会让客户端制造任意多的时间序列
+const metricLabelOther = "other"
+
+// 搜索与链接检测相关的Prometheus指标
+var (
+	pluginRequests = metrics.NewCounterVec("pansou_plugin_requests_total", "插件搜索次数", "plugin")
+	pluginErrors   = metrics.NewCounterVec("pansou_plugin_errors_total", "插件搜索失败次数", "plugin")
+	pluginTimeouts = metrics.NewCounterVec("pansou_plugin_timeouts_total", "插件在响应时限内未返回最终结果的次数", "plugin")
+	pluginDuration = metrics.NewHistogramVec("pansou_plugin_duration_seconds", "插件搜索耗时（秒）", nil, "plugin")
+	pluginProbes   = metrics.NewCounterVec("pansou_plugin_probes_total", "插件合成探测次数，按结果统计（ok、empty、error、timeout）", "plugin", "status")
+
+	tgChannelFetches = metrics.NewCounterVec("pansou_tg_channel_fetches_total", "TG频道抓取次数，按结果统计（ok、error、timeout、canceled）", "channel", "result")
+
+	checkResults = metrics.NewCounterVec("pansou_check_results_total", "链接检测结果数，按网盘类型和状态统计", "disk_type", "state")
+)
+
+func init() {
+	metrics.NewGaugeVecFunc("pansou_plugin_health_score", "插件健康分（0-1），按最近搜索的成功率平滑计算", []string{"plugin"}, func() []metrics.Sample {
+		healths := plugin.GetPluginHealths()
+		samples := make([]metrics.Sample, 0, len(healths))
+		for _, health := range healths {
+			samples = append(samples, metrics.Sample{LabelValues: []string{health.Name}, Value: health.Score})
+		}
+		return samples
+	})
+	metrics.NewGaugeVecFunc("pansou_plugin_circuit_open", "插件是否处于熔断状态（1为熔断或半开探测中）", []string{"plugin"}, func() []metrics.Sample {
+		healths := plugin.GetPluginHealths()
+		samples := make([]metrics.Sample, 0, len(healths))
+		for _, health := range healths {
+			var open float64
+			if health.Circuit != plugin.CircuitClosed {
+				open = 1
+			}
+			samples = append(samples, metrics.Sample{LabelValues: []string{health.Name}, Value: open})
+		}
+		return samples
+	})
+	metrics.NewGaugeFunc("pansou_cache_write_queue_size", "缓存延迟写入队列中的操作数", func() float64 {
+		if manager := globalCacheWriteManager; manager != nil {
+			return float64(manager.GetWriteManagerStats().CurrentQueueSize)
+		}
+		return 0
+	})
+	metrics.NewCounterVecFunc("pansou_cache_writes_total", "缓存延迟写入的磁盘写入次数，按结果统计", []string{"result"}, func() []metrics.Sample {
+		var successful, failed float64
+		if manager := globalCacheWriteManager; manager != nil {
+			stats := manager.GetWriteManagerStats()
+			successful = float64(stats.SuccessfulWrites)
+			failed = float64(stats.FailedWrites)
+		}
+		return []metrics.Sample{
+			{LabelValues: []string{"success"}, Value: successful},
+			{LabelValues: []string{"failed"}, Value: failed},
+		}
+	})
+}
+
+// recordPluginSearch 记录一次插件搜索的耗时和结果
+func recordPluginSearch(name string, elapsed time.Duration, err error, timedOut bool) {
+	pluginRequests.Inc(name)
+	pluginDuration.Observe(elapsed.Seconds(), name)
+	if err != nil {
+		pluginErrors.Inc(name)
+	}
+	if timedOut {
+		pluginTimeouts.Inc(name)
+	}
+}
+
+// recordPluginProbe 记录一次插件合成探测的结果
+func recordPluginProbe(name string, status string) {
+	pluginProbes.Inc(name, status)
+}
+
+// recordChannelFetch 记录一次TG频道抓取的结果
+func recordChannelFetch(channel string, err error) {
+	result := "ok"
+	switch {
+	case err == nil:
+	case errors.Is(err, context.DeadlineExceeded):
+		result = "timeout"
+	case errors.Is(err, context.Canceled):
+		result = "canceled"
+	default:
+		result = "error"
+	}
+	tgChannelFetches.Inc(channelLabel(channel), result)
+}
+
+// recordCheckResult 记录一次链接检测的结果
+func recordCheckResult(diskType, state string) {
+	checkResults.Inc(diskTypeLabel(diskType), state)
+}
+
+// channelLabel 返回频道的指标标签，只有配置的默认频道使用频道名
+func channelLabel(channel string) string {
+	for _, c := range config.Get().DefaultChannels {
+		if c == channel {
+			return channel
+		}
+	}
+	return metricLabelOther
+}
+
+// diskTypeLabel 返回网盘类型的指标标签，只有注册了检测器的类型使用类型名
+func diskTypeLabel(diskType string) string {
+	if _, ok := checker.GetChecker(diskType); ok {
+		return diskType
+	}
+	return metricLabelOther
+}
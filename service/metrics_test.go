@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pansou/config"
+)
+
+// setTestConfig 替换当前配置，测试结束时恢复
+func setTestConfig(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	previous := config.Get()
+	config.Set(cfg)
+	t.Cleanup(func() { config.Set(previous) })
+}
+
+func TestMetricLabels(t *testing.T) {
+	setTestConfig(t, &config.Config{DefaultChannels: []string{"tgsearchers6", "yunpanx"}})
+
+	tests := []struct {
+		name  string
+		label func(string) string
+		value string
+		want  string
+	}{
+		{"configured channel", channelLabel, "yunpanx", "yunpanx"},
+		{"unknown channel", channelLabel, "attacker-chosen-123", metricLabelOther},
+		{"empty channel", channelLabel, "", metricLabelOther},
+		{"registered checker", diskTypeLabel, "quark", "quark"},
+		{"unknown disk type", diskTypeLabel, "random-type", metricLabelOther},
+		{"empty disk type", diskTypeLabel, "", metricLabelOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.label(tt.value); got != tt.want {
+				t.Errorf("label(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordChannelFetch(t *testing.T) {
+	setTestConfig(t, &config.Config{DefaultChannels: []string{"tgsearchers6"}})
+
+	before := tgChannelFetches.Value(metricLabelOther, "timeout")
+	recordChannelFetch("not-configured", context.DeadlineExceeded)
+	if got := tgChannelFetches.Value(metricLabelOther, "timeout"); got != before+1 {
+		t.Errorf("other/timeout = %v, want %v", got, before+1)
+	}
+	if got := tgChannelFetches.Value("not-configured", "timeout"); got != 0 {
+		t.Errorf("未配置的频道不应作为标签, got %v", got)
+	}
+
+	before = tgChannelFetches.Value("tgsearchers6", "error")
+	recordChannelFetch("tgsearchers6", errors.New("boom"))
+	if got := tgChannelFetches.Value("tgsearchers6", "error"); got != before+1 {
+		t.Errorf("tgsearchers6/error = %v, want %v", got, before+1)
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"pansou/plugin"
+)
+
+// allowPluginSearch 检查插件熔断器是否允许本次搜索调用插件
+func allowPluginSearch(name string) bool {
+	return plugin.AllowPluginRequest(name)
+}
+
+// recordPluginHealth 根据插件本次搜索的结果更新熔断器和健康分
+// 请求被取消和命中插件自身缓存时无法反映插件状态，不计入
+func recordPluginHealth(ctx context.Context, name string, err error, timedOut bool, cacheHit bool) {
+	switch {
+	case ctx.Err() != nil, cacheHit:
+		return
+	case err != nil:
+		plugin.RecordPluginFailure(name, err)
+	case timedOut:
+		plugin.RecordPluginFailure(name, ErrSourceTimeout)
+	default:
+		plugin.RecordPluginSuccess(name)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"pansou/config"
+	"pansou/plugin"
+)
+
+func TestGetPluginLevelScore(t *testing.T) {
+	setTestConfig(t, &config.Config{PluginBreakerThreshold: 3, PluginBreakerCooldown: time.Hour})
+	resetPluginLevelCache()
+	t.Cleanup(resetPluginLevelCache)
+
+	tests := []struct {
+		name     string
+		priority int
+		failures int
+		want     int
+	}{
+		{"healthy level 1", 1, 0, 1000},
+		// 健康分按每次失败乘以0.8衰减
+		{"one failure", 1, 1, 800},
+		{"two failures", 1, 2, 640},
+		{"level 2", 2, 1, 400},
+		// 熔断中的插件不加分
+		{"circuit open", 1, 3, 0},
+		// 0分和负分不按健康分折算
+		{"level 3", 3, 2, 0},
+		{"level 4", 4, 2, -200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 熔断器按插件名保存且无法重置，每个用例使用新的插件名
+			name := fmt.Sprintf("levelstub%d", time.Now().UnixNano())
+			plugin.RegisterGlobalPlugin(&refreshStubPlugin{BaseAsyncPlugin: plugin.NewBaseAsyncPlugin(name, tt.priority), refreshed: make(map[string]bool)})
+			for i := 0; i < tt.failures; i++ {
+				plugin.RecordPluginFailure(name, errors.New("failed"))
+			}
+
+			if got := getPluginLevelScore("plugin:" + name); got != tt.want {
+				t.Errorf("getPluginLevelScore(%s) = %d, want %d", name, got, tt.want)
+			}
+		})
+	}
+
+	if got := getPluginLevelScore("tg:tgsearchers"); got != 0 {
+		t.Errorf("getPluginLevelScore(tg) = %d, want 0", got)
+	}
+}
+
+func TestRecordPluginHealth(t *testing.T) {
+	setTestConfig(t, &config.Config{PluginBreakerThreshold: 3, PluginBreakerCooldown: time.Hour})
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		err           error
+		timedOut      bool
+		cacheHit      bool
+		wantSuccesses int64
+		wantFailures  int64
+	}{
+		{"success", context.Background(), nil, false, false, 1, 0},
+		{"error", context.Background(), errors.New("failed"), false, false, 0, 1},
+		{"timeout", context.Background(), nil, true, false, 0, 1},
+		// 请求取消和命中插件缓存不计入
+		{"cancelled", cancelled, errors.New("canceled"), false, false, 0, 0},
+		{"cache hit", context.Background(), nil, false, true, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := fmt.Sprintf("healthrecord%d", time.Now().UnixNano())
+			recordPluginHealth(tt.ctx, name, tt.err, tt.timedOut, tt.cacheHit)
+			health := plugin.PluginHealthOf(name)
+			if health.Successes != tt.wantSuccesses || health.Failures != tt.wantFailures {
+				t.Errorf("successes = %d, failures = %d, want %d, %d", health.Successes, health.Failures, tt.wantSuccesses, tt.wantFailures)
+			}
+		})
+	}
+}
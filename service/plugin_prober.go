@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/plugin"
+)
+
+const (
+	pluginProbeBucketName = "plugin_probes"
+	pluginProbeFileName   = "plugin_probes.db"
+	pluginProbeWorkers    = 4 // 同时探测的插件数
+)
+
+// PluginProber 插件合成探测
+// 定期用固定关键词调用每个已启用插件的Search，记录是否成功、结果数、链接数和耗时，
+// 每个插件的最近记录保存在bbolt中的环形缓冲区，用于在用户反馈前发现网站改版导致的解析失败。
+type PluginProber struct {
+	pluginManager *plugin.PluginManager
+	interval      time.Duration
+	keyword       string
+	history       int
+	timeout       time.Duration
+	db            *bolt.DB
+
+	mu             sync.Mutex
+	running        bool
+	lastStartedAt  time.Time
+	lastFinishedAt time.Time
+}
+
+func newPluginProber(pluginManager *plugin.PluginManager, interval time.Duration, keyword string, history int) *PluginProber {
+	prober := &PluginProber{
+		pluginManager: pluginManager,
+		interval:      interval,
+		keyword:       keyword,
+		history:       history,
+		timeout:       config.Get().PluginTimeout,
+	}
+	prober.openStore(filepath.Join(config.Get().CachePath, pluginProbeFileName))
+	return prober
+}
+
+func (p *PluginProber) openStore(path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(pluginProbeBucketName))
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return
+	}
+
+	p.db = db
+}
+
+// start 按间隔定期探测，第一轮在启动一个间隔之后执行，避免频繁重启时反复请求上游站点
+func (p *PluginProber) start() {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			p.probeAll()
+		}
+	}()
+}
+
+// probeAll 探测一轮所有已启用的插件
+func (p *PluginProber) probeAll() {
+	plugins := p.pluginManager.GetPlugins()
+
+	p.mu.Lock()
+	p.running = true
+	p.lastStartedAt = time.Now()
+	p.mu.Unlock()
+
+	indexes := make(chan int, len(plugins))
+	for i := range plugins {
+		indexes <- i
+	}
+	close(indexes)
+
+	var wg sync.WaitGroup
+	for i := 0; i < pluginProbeWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				target := plugins[index]
+				run := p.probe(target)
+				recordPluginProbe(target.Name(), run.Status)
+				p.save(target.Name(), run)
+			}
+		}()
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.running = false
+	p.lastFinishedAt = time.Now()
+	p.mu.Unlock()
+}
+
+// probe 用探测关键词执行一次真实搜索（跳过插件缓存）
+func (p *PluginProber) probe(target plugin.AsyncSearchPlugin) model.PluginProbeRun {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	type probeResult struct {
+		results []model.SearchResult
+		final   bool
+		err     error
+	}
+	done := make(chan probeResult, 1)
+
+	startTime := time.Now()
+	go func() {
+		ext := plugin.WithSearchContext(map[string]interface{}{"refresh": true}, ctx)
+		if resultPlugin, ok := target.(searchWithResultPlugin); ok {
+			result, err := resultPlugin.SearchWithResult(p.keyword, ext)
+			done <- probeResult{results: result.Results, final: result.IsFinal, err: err}
+			return
+		}
+		results, err := target.Search(p.keyword, ext)
+		done <- probeResult{results: results, final: true, err: err}
+	}()
+
+	run := model.PluginProbeRun{At: startTime.UnixMilli()}
+	select {
+	case result := <-done:
+		run.LatencyMs = time.Since(startTime).Milliseconds()
+		run.ResultCount = len(result.results)
+		for _, r := range result.results {
+			run.LinkCount += len(r.Links)
+		}
+
+		switch {
+		case result.err != nil:
+			run.Status = model.PluginProbeStatusError
+			run.Error = result.err.Error()
+		case run.LinkCount > 0:
+			run.Status = model.PluginProbeStatusOK
+		case !result.final:
+			run.Status = model.PluginProbeStatusTimeout
+			run.Error = ErrSourceTimeout.Error()
+		default:
+			run.Status = model.PluginProbeStatusEmpty
+		}
+	case <-ctx.Done():
+		run.LatencyMs = time.Since(startTime).Milliseconds()
+		run.Status = model.PluginProbeStatusTimeout
+		run.Error = ErrSourceTimeout.Error()
+	}
+	run.Success = run.Status == model.PluginProbeStatusOK
+	return run
+}
+
+// save 追加一条探测记录，超过保留数时删除最旧的记录
+func (p *PluginProber) save(name string, run model.PluginProbeRun) {
+	if p.db == nil {
+		return
+	}
+
+	raw, err := json.Marshal(run)
+	if err != nil {
+		return
+	}
+
+	_ = p.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(pluginProbeBucketName))
+		if root == nil {
+			return nil
+		}
+		bucket, err := root.CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return err
+		}
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		if err := bucket.Put(key, raw); err != nil {
+			return err
+		}
+
+		// 键按序号递增，从头部删除超出保留数的旧记录
+		cursor := bucket.Cursor()
+		excess := -p.history
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			excess++
+		}
+		for k, _ := cursor.First(); k != nil && excess > 0; k, _ = cursor.First() {
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+			excess--
+		}
+		return nil
+	})
+}
+
+// Status 返回探测进度和每个插件最近limit次探测记录
+// 列出当前启用的插件和有探测记录的插件
+func (p *PluginProber) Status(limit int) model.PluginsStatusResponse {
+	if limit <= 0 || limit > p.history {
+		limit = p.history
+	}
+
+	p.mu.Lock()
+	response := model.PluginsStatusResponse{
+		Enabled:         true,
+		Running:         p.running,
+		Keyword:         p.keyword,
+		IntervalSeconds: int64(p.interval / time.Second),
+	}
+	if !p.lastStartedAt.IsZero() {
+		response.LastStartedAt = p.lastStartedAt.UnixMilli()
+	}
+	if !p.lastFinishedAt.IsZero() {
+		response.LastFinishedAt = p.lastFinishedAt.UnixMilli()
+	}
+	p.mu.Unlock()
+
+	histories := make(map[string]*model.PluginProbeHistory)
+	for _, enabledPlugin := range p.pluginManager.GetPlugins() {
+		histories[enabledPlugin.Name()] = &model.PluginProbeHistory{
+			Name:    enabledPlugin.Name(),
+			Enabled: true,
+			Runs:    []model.PluginProbeRun{},
+		}
+	}
+
+	if p.db != nil {
+		_ = p.db.View(func(tx *bolt.Tx) error {
+			root := tx.Bucket([]byte(pluginProbeBucketName))
+			if root == nil {
+				return nil
+			}
+			return root.ForEachBucket(func(name []byte) error {
+				history, ok := histories[string(name)]
+				if !ok {
+					history = &model.PluginProbeHistory{Name: string(name), Runs: []model.PluginProbeRun{}}
+					histories[string(name)] = history
+				}
+
+				cursor := root.Bucket(name).Cursor()
+				for k, v := cursor.Last(); k != nil && len(history.Runs) < limit; k, v = cursor.Prev() {
+					var run model.PluginProbeRun
+					if err := json.Unmarshal(v, &run); err == nil {
+						history.Runs = append(history.Runs, run)
+					}
+				}
+				return nil
+			})
+		})
+	}
+
+	response.Plugins = make([]model.PluginProbeHistory, 0, len(histories))
+	for _, history := range histories {
+		if len(history.Runs) > 0 {
+			history.LastStatus = history.Runs[0].Status
+			successes := 0
+			for _, run := range history.Runs {
+				if run.Success {
+					successes++
+				}
+			}
+			history.SuccessRate = float64(successes) / float64(len(history.Runs))
+		}
+		response.Plugins = append(response.Plugins, *history)
+	}
+	sort.Slice(response.Plugins, func(i, j int) bool {
+		return response.Plugins[i].Name < response.Plugins[j].Name
+	})
+	return response
+}
+
+// PluginsStatus 返回插件合成探测的状态和每个插件最近limit次探测记录
+func (s *SearchService) PluginsStatus(limit int) model.PluginsStatusResponse {
+	if s.prober == nil {
+		return model.PluginsStatusResponse{Plugins: []model.PluginProbeHistory{}}
+	}
+	return s.prober.Status(limit)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/plugin"
+	"pansou/util/cache"
+)
+
+// 插件运行时状态文件名，保存在CACHE_PATH下
+const pluginStateFileName = "plugin_state.json"
+
+// ErrPluginNotFound 插件未注册
+var ErrPluginNotFound = errors.New("插件不存在")
+
+// pluginState 单个插件的持久化运行时状态，零值字段表示使用默认值
+type pluginState struct {
+	Enabled   *bool `json:"enabled,omitempty"`
+	Priority  int   `json:"priority,omitempty"`
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+}
+
+// pluginStateFile 插件运行时状态文件
+type pluginStateFile struct {
+	Plugins map[string]pluginState `json:"plugins"`
+}
+
+// 插件运行时状态，修改时需持有锁以保证内存状态与文件一致
+var (
+	pluginStates     = make(map[string]pluginState)
+	pluginStatesLock sync.Mutex
+)
+
+// pluginStatePath 插件运行时状态文件路径
+func pluginStatePath() string {
+	return filepath.Join(config.Get().CachePath, pluginStateFileName)
+}
+
+// loadPluginState 加载并应用持久化的插件运行时状态
+// 状态文件中的启用/禁用设置优先于ENABLED_PLUGINS
+func (s *SearchService) loadPluginState() {
+	pluginStatesLock.Lock()
+	defer pluginStatesLock.Unlock()
+
+	data, err := os.ReadFile(pluginStatePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("[插件状态] 读取状态文件失败: %v\n", err)
+		}
+		s.updatePluginsHash()
+		return
+	}
+
+	var file pluginStateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		fmt.Printf("[插件状态] 解析状态文件失败: %v\n", err)
+		s.updatePluginsHash()
+		return
+	}
+
+	for name, state := range file.Plugins {
+		if _, exists := plugin.GetPluginByName(name); !exists {
+			continue
+		}
+		if err := s.applyPluginState(name, state); err != nil {
+			fmt.Printf("[插件状态] 应用插件 %s 的状态失败: %v\n", name, err)
+			continue
+		}
+		pluginStates[name] = state
+	}
+
+	// 配置文件中设置了优先级或超时、但没有运行时状态的插件
+	for name := range config.Get().Plugins {
+		if _, exists := pluginStates[name]; !exists {
+			plugin.SetPluginOverride(name, pluginOverride(name, pluginState{}))
+		}
+	}
+	s.updatePluginsHash()
+}
+
+// applyConfigReload 配置热加载后同步插件的启用状态和配置文件中的插件设置
+// 通过管理接口修改过启用状态的插件保持不变
+func (s *SearchService) applyConfigReload(_, cfg *config.Config) {
+	pluginStatesLock.Lock()
+	defer pluginStatesLock.Unlock()
+
+	if s.pluginManager != nil && cfg.AsyncPluginEnabled {
+		wanted := make(map[string]bool, len(cfg.EnabledPlugins))
+		for _, name := range cfg.EnabledPlugins {
+			wanted[name] = true
+		}
+
+		for _, p := range plugin.GetRegisteredPlugins() {
+			name := p.Name()
+			if state, ok := pluginStates[name]; ok && state.Enabled != nil {
+				continue
+			}
+			switch enabled := s.pluginManager.IsEnabled(name); {
+			case wanted[name] && !enabled:
+				if err := s.pluginManager.EnablePlugin(name); err != nil {
+					fmt.Printf("[插件状态] 启用插件 %s 失败: %v\n", name, err)
+					continue
+				}
+				injectMainCacheToAsyncPlugins(s.pluginManager, enhancedTwoLevelCache)
+			case !wanted[name] && enabled:
+				s.pluginManager.DisablePlugin(name)
+			}
+		}
+	}
+
+	for _, p := range plugin.GetRegisteredPlugins() {
+		plugin.SetPluginOverride(p.Name(), pluginOverride(p.Name(), pluginStates[p.Name()]))
+	}
+
+	resetPluginLevelCache()
+	s.updatePluginsHash()
+}
+
+// UpdatePluginState 运行时修改插件的启用状态、优先级和响应超时，修改立即生效并持久化
+// 先写入状态文件再应用，保存失败时不生效，避免重启后丢失已生效的修改
+func (s *SearchService) UpdatePluginState(name string, update model.PluginStateUpdate) error {
+	if _, exists := plugin.GetPluginByName(name); !exists {
+		return ErrPluginNotFound
+	}
+
+	pluginStatesLock.Lock()
+	defer pluginStatesLock.Unlock()
+
+	state := pluginStates[name]
+	if update.Enabled != nil {
+		if *update.Enabled && !config.Get().AsyncPluginEnabled {
+			return errors.New("异步插件功能未启用")
+		}
+		enabled := *update.Enabled
+		state.Enabled = &enabled
+	}
+	if update.Priority != nil {
+		if *update.Priority < 0 || *update.Priority > 4 {
+			return errors.New("priority必须为1-4，0表示恢复默认")
+		}
+		state.Priority = *update.Priority
+	}
+	if update.TimeoutMs != nil {
+		maxTimeout := config.Get().PluginTimeout
+		if *update.TimeoutMs < 0 || (maxTimeout > 0 && time.Duration(*update.TimeoutMs)*time.Millisecond > maxTimeout) {
+			return fmt.Errorf("timeout_ms必须在0-%d之间，0表示恢复默认", maxTimeout.Milliseconds())
+		}
+		state.TimeoutMs = *update.TimeoutMs
+	}
+
+	next := make(map[string]pluginState, len(pluginStates)+1)
+	for k, v := range pluginStates {
+		next[k] = v
+	}
+	if state.Enabled == nil && state.Priority == 0 && state.TimeoutMs == 0 {
+		delete(next, name)
+	} else {
+		next[name] = state
+	}
+
+	if err := savePluginStates(next); err != nil {
+		return fmt.Errorf("保存插件状态失败: %w", err)
+	}
+
+	if err := s.applyPluginState(name, state); err != nil {
+		// 应用失败时恢复状态文件，保持与内存状态一致
+		if restoreErr := savePluginStates(pluginStates); restoreErr != nil {
+			fmt.Printf("[插件状态] 恢复状态文件失败: %v\n", restoreErr)
+		}
+		return err
+	}
+	pluginStates = next
+
+	// 优先级可能已变化，清空等级缓存使排序立即生效
+	resetPluginLevelCache()
+	s.updatePluginsHash()
+	return nil
+}
+
+// applyPluginState 将插件状态应用到插件管理器和运行时覆盖
+func (s *SearchService) applyPluginState(name string, state pluginState) error {
+	if state.Enabled != nil && s.pluginManager != nil {
+		if *state.Enabled {
+			// 异步插件功能关闭时忽略持久化的启用设置
+			if !config.Get().AsyncPluginEnabled {
+				return nil
+			}
+			if err := s.pluginManager.EnablePlugin(name); err != nil {
+				return err
+			}
+			// 运行时启用的插件同样需要注入主缓存更新函数
+			injectMainCacheToAsyncPlugins(s.pluginManager, enhancedTwoLevelCache)
+		} else {
+			s.pluginManager.DisablePlugin(name)
+		}
+	}
+
+	plugin.SetPluginOverride(name, pluginOverride(name, state))
+	return nil
+}
+
+// pluginOverride 合并运行时状态和配置文件中的插件设置，运行时状态优先
+func pluginOverride(name string, state pluginState) plugin.PluginOverride {
+	priority, timeoutMs := state.Priority, state.TimeoutMs
+	if fileConfig, ok := config.Get().Plugins[name]; ok {
+		if priority == 0 {
+			priority = fileConfig.Priority
+		}
+		if timeoutMs == 0 {
+			timeoutMs = fileConfig.TimeoutMs
+		}
+	}
+	return plugin.PluginOverride{
+		Priority: priority,
+		Timeout:  time.Duration(timeoutMs) * time.Millisecond,
+	}
+}
+
+// updatePluginsHash 按当前启用的插件更新未指定插件时使用的缓存键哈希
+func (s *SearchService) updatePluginsHash() {
+	if s.pluginManager == nil {
+		return
+	}
+
+	plugins := s.pluginManager.GetPlugins()
+	names := make([]string, 0, len(plugins))
+	for _, p := range plugins {
+		names = append(names, p.Name())
+	}
+	cache.UpdateAllPluginsHash(names)
+}
+
+// savePluginStates 将插件运行时状态写入状态文件，调用方需持有pluginStatesLock
+func savePluginStates(states map[string]pluginState) error {
+	data, err := json.MarshalIndent(pluginStateFile{Plugins: states}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := pluginStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// 先写临时文件再重命名，避免写入中断导致状态文件损坏
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
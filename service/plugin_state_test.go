@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/plugin"
+)
+
+// failingInitPlugin 初始化总是失败的插件，用于模拟应用状态失败
+type failingInitPlugin struct {
+	*refreshStubPlugin
+}
+
+func (p *failingInitPlugin) Initialize() error {
+	return errors.New("初始化失败")
+}
+
+var registerStatePluginsOnce sync.Once
+
+// newTestPluginStateService 注册测试插件并创建使用临时CACHE_PATH的搜索服务，测试结束时恢复插件状态
+func newTestPluginStateService(t *testing.T) (*SearchService, string) {
+	t.Helper()
+	registerStatePluginsOnce.Do(func() {
+		plugin.RegisterGlobalPlugin(&refreshStubPlugin{BaseAsyncPlugin: plugin.NewBaseAsyncPlugin("statestub", 3), refreshed: make(map[string]bool)})
+		plugin.RegisterGlobalPlugin(&failingInitPlugin{&refreshStubPlugin{BaseAsyncPlugin: plugin.NewBaseAsyncPlugin("statefail", 3), refreshed: make(map[string]bool)}})
+	})
+
+	cachePath := t.TempDir()
+	setTestConfig(t, &config.Config{CachePath: cachePath, AsyncPluginEnabled: true})
+
+	pluginStatesLock.Lock()
+	previous := pluginStates
+	pluginStates = make(map[string]pluginState)
+	pluginStatesLock.Unlock()
+	t.Cleanup(func() {
+		pluginStatesLock.Lock()
+		pluginStates = previous
+		pluginStatesLock.Unlock()
+		plugin.SetPluginOverride("statestub", plugin.PluginOverride{})
+		plugin.SetPluginOverride("statefail", plugin.PluginOverride{})
+	})
+
+	manager := plugin.NewPluginManager()
+	stub, _ := plugin.GetPluginByName("statestub")
+	manager.RegisterPlugin(stub)
+	return &SearchService{pluginManager: manager}, cachePath
+}
+
+func TestUpdatePluginState(t *testing.T) {
+	s, cachePath := newTestPluginStateService(t)
+
+	priority, disabled := 1, false
+	if err := s.UpdatePluginState("statestub", model.PluginStateUpdate{Priority: &priority, Enabled: &disabled}); err != nil {
+		t.Fatalf("UpdatePluginState() error = %v", err)
+	}
+	if override, _ := plugin.GetPluginOverride("statestub"); override.Priority != 1 {
+		t.Errorf("override priority = %d, want 1", override.Priority)
+	}
+	if s.pluginManager.IsEnabled("statestub") {
+		t.Error("插件应已禁用")
+	}
+	data, err := os.ReadFile(filepath.Join(cachePath, pluginStateFileName))
+	if err != nil || !strings.Contains(string(data), `"statestub"`) {
+		t.Errorf("状态文件 = %s, %v, want statestub", data, err)
+	}
+
+	if err := s.UpdatePluginState("missing", model.PluginStateUpdate{Priority: &priority}); !errors.Is(err, ErrPluginNotFound) {
+		t.Errorf("UpdatePluginState(missing) error = %v, want ErrPluginNotFound", err)
+	}
+}
+
+// 保存失败时修改不生效
+func TestUpdatePluginStateSaveFailure(t *testing.T) {
+	s, cachePath := newTestPluginStateService(t)
+
+	// CACHE_PATH指向普通文件，无法创建状态文件
+	blocked := filepath.Join(cachePath, "blocked")
+	if err := os.WriteFile(blocked, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config.Set(&config.Config{CachePath: filepath.Join(blocked, "cache"), AsyncPluginEnabled: true})
+
+	priority, disabled := 2, false
+	if err := s.UpdatePluginState("statestub", model.PluginStateUpdate{Priority: &priority, Enabled: &disabled}); err == nil {
+		t.Fatal("UpdatePluginState() error = nil, want save error")
+	}
+	if _, ok := plugin.GetPluginOverride("statestub"); ok {
+		t.Error("保存失败后覆盖设置不应生效")
+	}
+	if !s.pluginManager.IsEnabled("statestub") {
+		t.Error("保存失败后插件不应被禁用")
+	}
+	if len(pluginStates) != 0 {
+		t.Errorf("pluginStates = %v, want empty", pluginStates)
+	}
+}
+
+// 应用失败时恢复状态文件
+func TestUpdatePluginStateApplyFailure(t *testing.T) {
+	s, cachePath := newTestPluginStateService(t)
+
+	enabled := true
+	if err := s.UpdatePluginState("statefail", model.PluginStateUpdate{Enabled: &enabled}); err == nil {
+		t.Fatal("UpdatePluginState() error = nil, want initialize error")
+	}
+	data, err := os.ReadFile(filepath.Join(cachePath, pluginStateFileName))
+	if err != nil {
+		t.Fatalf("读取状态文件失败: %v", err)
+	}
+	if strings.Contains(string(data), "statefail") {
+		t.Errorf("状态文件 = %s, want statefail removed", data)
+	}
+	if _, ok := pluginStates["statefail"]; ok {
+		t.Error("应用失败后不应保存状态")
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+
+	"pansou/checker"
+	"pansou/config"
+	"pansou/model"
+)
+
+// 链接检测状态对结果排序的影响
+const (
+	linkStateOKScore  = 300  // 包含已确认有效链接的结果加分
+	linkStateBadScore = -300 // 链接全部已知失效的结果减分
+)
+
+// SetCheckService 设置链接检测服务，用于搜索结果的链接有效性标注
+func (s *SearchService) SetCheckService(checkService *CheckService) {
+	s.checkService = checkService
+}
+
+// knownLinkStates 从检测缓存中读取结果链接的已知状态，键为链接URL，未检测过的链接值为空
+// 全部链接一次批量查询，持久化缓存只打开一个读事务
+func (s *SearchService) knownLinkStates(results []model.SearchResult) map[string]string {
+	states := make(map[string]string)
+	if s.checkService == nil {
+		return states
+	}
+
+	items := make([]model.CheckItem, 0)
+	for _, result := range results {
+		for _, link := range result.Links {
+			if _, exists := states[link.URL]; exists {
+				continue
+			}
+			states[link.URL] = ""
+			items = append(items, model.CheckItem{
+				DiskType: link.Type,
+				URL:      link.URL,
+				Password: link.Password,
+			})
+		}
+	}
+
+	for i, cached := range s.checkService.LookupBatch(items) {
+		states[items[i].URL] = cached.State
+	}
+	return states
+}
+
+// getLinkStateScore 根据结果中链接的已知状态计算排序得分
+func getLinkStateScore(result model.SearchResult, linkStates map[string]string) int {
+	if len(linkStates) == 0 || len(result.Links) == 0 {
+		return 0
+	}
+
+	allBad := true
+	for _, link := range result.Links {
+		switch linkStates[link.URL] {
+		case checker.StateOK:
+			return linkStateOKScore
+		case checker.StateBad:
+		default:
+			allBad = false
+		}
+	}
+
+	if allBad {
+		return linkStateBadScore
+	}
+	return 0
+}
+
+// annotateLinkStates 为合并链接标注检测状态
+// 已知状态直接来自检测缓存；每种网盘前N个未知状态的链接在时间预算内实时检测，
+// 预算内未完成的检测在后台继续并写入缓存。已知失效的链接按配置移除或排到末尾。
+func (s *SearchService) annotateLinkStates(ctx context.Context, merged model.MergedLinks, linkStates map[string]string) model.MergedLinks {
+	if s.checkService == nil {
+		return merged
+	}
+
+	type linkPosition struct {
+		linkType string
+		index    int
+	}
+
+	topN := config.Get().SearchCheckTopN
+	items := make([]model.CheckItem, 0)
+	positions := make([]linkPosition, 0)
+
+	for linkType, links := range merged {
+		_, supported := checker.GetChecker(linkType)
+		candidates := 0
+		for i := range links {
+			links[i].State = linkStates[links[i].URL]
+			if links[i].State == checker.StateBad || candidates >= topN {
+				continue
+			}
+			candidates++
+
+			if links[i].State == "" && supported {
+				items = append(items, model.CheckItem{
+					DiskType: linkType,
+					URL:      links[i].URL,
+					Password: links[i].Password,
+				})
+				positions = append(positions, linkPosition{linkType: linkType, index: i})
+			}
+		}
+	}
+
+	if len(items) > 0 {
+		checkCtx, cancel := context.WithTimeout(ctx, config.Get().SearchCheckTimeout)
+		response := s.checkService.Check(checkCtx, items)
+		cancel()
+
+		for i, result := range response.Results {
+			position := positions[i]
+			merged[position.linkType][position.index].State = result.State
+		}
+	}
+
+	for linkType, links := range merged {
+		links = demoteBadLinks(links, config.Get().SearchCheckDropBad)
+		if len(links) == 0 {
+			delete(merged, linkType)
+			continue
+		}
+		merged[linkType] = links
+	}
+
+	return merged
+}
+
+// demoteBadLinks 将失效链接排到末尾（保持原有相对顺序），drop为true时直接移除
+func demoteBadLinks(links []model.MergedLink, drop bool) []model.MergedLink {
+	kept := make([]model.MergedLink, 0, len(links))
+	bad := make([]model.MergedLink, 0)
+	for _, link := range links {
+		if link.State == checker.StateBad {
+			bad = append(bad, link)
+		} else {
+			kept = append(kept, link)
+		}
+	}
+
+	if drop {
+		return kept
+	}
+	return append(kept, bad...)
+}
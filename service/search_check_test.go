@@ -0,0 +1,102 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pansou/checker"
+	"pansou/model"
+)
+
+// newTestCheckService 创建使用临时目录的检测服务，不启动后台复检
+func newTestCheckService(t *testing.T) *CheckService {
+	t.Helper()
+	s := &CheckService{
+		cache:     make(map[string]cachedCheckResult),
+		inflight:  make(map[string]*activeCheckCall),
+		hits:      make(map[string]int),
+		cacheFile: filepath.Join(t.TempDir(), "check_cache.db"),
+	}
+	s.openCacheStore()
+	if s.cacheDB == nil {
+		t.Fatal("打开检测缓存失败")
+	}
+	t.Cleanup(func() { _ = s.cacheDB.Close() })
+	return s
+}
+
+func checkCacheKey(item model.CheckItem) string {
+	return item.DiskType + "|" + checker.Normalize(item.DiskType, item.URL, item.Password)
+}
+
+func TestLookupBatch(t *testing.T) {
+	s := newTestCheckService(t)
+	future := time.Now().Add(time.Hour)
+
+	memory := model.CheckItem{DiskType: "quark", URL: "https://pan.quark.cn/s/memory"}
+	persisted := model.CheckItem{DiskType: "quark", URL: "https://pan.quark.cn/s/persisted"}
+	expired := model.CheckItem{DiskType: "quark", URL: "https://pan.quark.cn/s/expired"}
+	unknown := model.CheckItem{DiskType: "quark", URL: "https://pan.quark.cn/s/unknown"}
+
+	s.cache[checkCacheKey(memory)] = cachedCheckResult{result: model.CheckResult{State: checker.StateOK}, expiresAt: future}
+	s.savePersistentCache(checkCacheKey(persisted), cachedCheckResult{result: model.CheckResult{State: checker.StateBad}, expiresAt: future})
+	s.savePersistentCache(checkCacheKey(expired), cachedCheckResult{result: model.CheckResult{State: checker.StateOK}, expiresAt: time.Now().Add(-time.Minute)})
+
+	items := []model.CheckItem{memory, persisted, expired, unknown, persisted, {DiskType: "quark"}}
+	found := s.LookupBatch(items)
+
+	want := map[int]string{0: checker.StateOK, 1: checker.StateBad, 4: checker.StateBad}
+	if len(found) != len(want) {
+		t.Fatalf("LookupBatch() = %+v, want %d hits", found, len(want))
+	}
+	for i, state := range want {
+		if found[i].State != state {
+			t.Errorf("found[%d].State = %q, want %q", i, found[i].State, state)
+		}
+	}
+
+	// 磁盘命中的结果载入内存，过期的记录从磁盘删除
+	if _, ok := s.cache[checkCacheKey(persisted)]; !ok {
+		t.Error("磁盘命中的结果应载入内存缓存")
+	}
+	if s.hits[checkCacheKey(persisted)] != 2 {
+		t.Errorf("hits = %d, want 2", s.hits[checkCacheKey(persisted)])
+	}
+	if _, ok := s.loadPersistentCache(checkCacheKey(expired)); ok {
+		t.Error("过期的记录应从磁盘删除")
+	}
+
+	if result, ok := s.Lookup(persisted); !ok || result.State != checker.StateBad {
+		t.Errorf("Lookup() = %+v, %v", result, ok)
+	}
+	if _, ok := s.Lookup(unknown); ok {
+		t.Error("Lookup(unknown) 不应命中")
+	}
+}
+
+func TestKnownLinkStates(t *testing.T) {
+	checks := newTestCheckService(t)
+	ok := model.CheckItem{DiskType: "quark", URL: "https://pan.quark.cn/s/ok"}
+	checks.savePersistentCache(checkCacheKey(ok), cachedCheckResult{result: model.CheckResult{State: checker.StateOK}, expiresAt: time.Now().Add(time.Hour)})
+
+	results := []model.SearchResult{
+		{Links: []model.Link{{Type: "quark", URL: ok.URL}, {Type: "quark", URL: "https://pan.quark.cn/s/new"}}},
+		{Links: []model.Link{{Type: "quark", URL: ok.URL}}},
+	}
+
+	if states := (&SearchService{}).knownLinkStates(results); len(states) != 0 {
+		t.Errorf("未配置检测服务时 states = %v, want empty", states)
+	}
+
+	states := (&SearchService{checkService: checks}).knownLinkStates(results)
+	want := map[string]string{ok.URL: checker.StateOK, "https://pan.quark.cn/s/new": ""}
+	if len(states) != len(want) {
+		t.Fatalf("states = %v, want %v", states, want)
+	}
+	for url, state := range want {
+		if got, exists := states[url]; !exists || got != state {
+			t.Errorf("states[%q] = %q, want %q", url, got, state)
+		}
+	}
+}
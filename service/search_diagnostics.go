@@ -0,0 +1,101 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"pansou/model"
+)
+
+// sourceDiagnostics 收集一次搜索中各数据源的返回情况，用于生成响应中的诊断信息
+type sourceDiagnostics struct {
+	mu        sync.Mutex
+	startTime time.Time
+	next      SourceCallback // 原有的数据源回调，可以为nil
+	reports   map[string]SourceReport
+}
+
+func newSourceDiagnostics(next SourceCallback) *sourceDiagnostics {
+	return &sourceDiagnostics{
+		startTime: time.Now(),
+		next:      next,
+		reports:   make(map[string]SourceReport),
+	}
+}
+
+// report 记录数据源的返回情况并转发给原有回调
+// 查询有OR分支时同一数据源会回调多次，结果合并，耗时取最长，状态和错误取第一次出错的分支
+func (d *sourceDiagnostics) report(report SourceReport) {
+	d.mu.Lock()
+	if existing, exists := d.reports[report.Source]; exists {
+		existing.Results = append(existing.Results[:len(existing.Results):len(existing.Results)], report.Results...)
+		if report.Latency > existing.Latency {
+			existing.Latency = report.Latency
+		}
+		if existing.Err == nil && report.Err != nil {
+			existing.Status = report.Status
+			existing.Err = report.Err
+		}
+		d.reports[report.Source] = existing
+	} else {
+		d.reports[report.Source] = report
+	}
+	d.mu.Unlock()
+
+	if d.next != nil {
+		d.next(report)
+	}
+}
+
+// build 生成诊断信息，按数据源名称排序
+// 未在批量超时内返回的数据源记为超时；链接数统计合并链接中归属各数据源的链接
+func (d *sourceDiagnostics) build(expectedSources []string, merged model.MergedLinks) []model.SourceDiagnostic {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	elapsed := time.Since(d.startTime)
+	for _, source := range expectedSources {
+		if _, exists := d.reports[source]; !exists {
+			d.reports[source] = SourceReport{
+				Source:  source,
+				Status:  model.SourceStatusTimeout,
+				Latency: elapsed,
+				Err:     ErrSourceTimeout,
+			}
+		}
+	}
+
+	diagnostics := make([]model.SourceDiagnostic, 0, len(d.reports))
+	for source, report := range d.reports {
+		diagnostic := model.SourceDiagnostic{
+			Source:      source,
+			Status:      report.Status,
+			LatencyMs:   report.Latency.Milliseconds(),
+			ResultCount: len(report.Results),
+		}
+		if report.Err != nil {
+			diagnostic.Error = report.Err.Error()
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	sort.Slice(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Source < diagnostics[j].Source
+	})
+	CountDiagnosticLinks(diagnostics, merged)
+	return diagnostics
+}
+
+// CountDiagnosticLinks 按合并链接重新统计诊断信息中各数据源的链接数，用于结果被进一步过滤之后
+func CountDiagnosticLinks(diagnostics []model.SourceDiagnostic, merged model.MergedLinks) {
+	linkCounts := make(map[string]int)
+	for _, links := range merged {
+		for _, link := range links {
+			linkCounts[link.Source]++
+		}
+	}
+	for i := range diagnostics {
+		diagnostics[i].LinkCount = linkCounts[diagnostics[i].Source]
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"time"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/util/cache"
+	jsonutil "pansou/util/json"
+)
+
+const (
+	// DefaultPageSize 未指定page_size时的每页数量
+	DefaultPageSize = 20
+	// MaxPageSize 每页数量上限
+	MaxPageSize = 200
+)
+
+// ErrInvalidCursor 游标无法解析或与当前搜索参数不匹配
+var ErrInvalidCursor = errors.New("无效的分页游标")
+
+// pageCursor 分页游标内容，编码后作为不透明字符串返回给客户端
+type pageCursor struct {
+	Key      string `json:"k"`           // 快照缓存键，用于校验游标与搜索参数是否匹配
+	Filter   string `json:"f,omitempty"` // 过滤配置签名，用于校验游标与过滤条件是否匹配
+	Offset   int    `json:"o"`
+	PageSize int    `json:"n"`
+}
+
+// filterSignature 生成过滤配置的签名，没有过滤条件时返回空字符串
+// 快照缓存的是过滤前的结果，偏移量却是按过滤后的结果计算的，更换过滤条件后沿用游标会跳过或重复结果
+func filterSignature(filter *model.FilterConfig) string {
+	if filter == nil || (len(filter.Include) == 0 && len(filter.Exclude) == 0 && len(filter.Rules) == 0 && filter.MinLinks <= 0) {
+		return ""
+	}
+	data, err := jsonutil.Marshal(filter)
+	if err != nil {
+		return ""
+	}
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// encodeCursor 编码分页游标
+func encodeCursor(cursor pageCursor) string {
+	data, err := jsonutil.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解码分页游标
+func decodeCursor(s string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	if err := jsonutil.Unmarshal(data, &cursor); err != nil || cursor.Key == "" || cursor.Offset < 0 || cursor.PageSize <= 0 {
+		return pageCursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// SearchPage 分页搜索
+// 合并后的完整结果作为快照缓存在搜索缓存键下，第一页之后的请求直接从快照读取，不会重新执行插件搜索，
+// 因此翻页过程中结果顺序保持稳定。filter在分页前应用于快照，可以为nil。
+// req.Debug为true时，只有实际执行了搜索的页返回诊断信息。
+func (s *SearchService) SearchPage(ctx context.Context, req model.SearchRequest, filter func(model.SearchResponse) model.SearchResponse) (model.SearchPageResponse, error) {
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	snapshotKey := cache.GenerateSearchCacheKey(req, cache.SearchCacheScopeSnapshot)
+	filterKey := filterSignature(req.Filter)
+
+	// 确定偏移量：游标优先于页码
+	offset := 0
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return model.SearchPageResponse{}, err
+		}
+		if cursor.Key != snapshotKey || cursor.Filter != filterKey {
+			return model.SearchPageResponse{}, ErrInvalidCursor
+		}
+		offset = cursor.Offset
+		pageSize = cursor.PageSize
+	} else if req.Page > 1 {
+		offset = (req.Page - 1) * pageSize
+	}
+
+	// 第一页重新搜索并刷新快照，后续页优先使用快照
+	useSnapshot := offset > 0 && !req.ForceRefresh
+	snapshot, err := s.searchSnapshot(ctx, req, snapshotKey, useSnapshot)
+	if err != nil {
+		return model.SearchPageResponse{}, err
+	}
+
+	if filter != nil {
+		snapshot = filter(snapshot)
+	}
+
+	page, hasMore := paginateResponse(snapshot, req.ResultType, offset, pageSize)
+	if len(snapshot.Diagnostics) > 0 {
+		// 诊断信息只在实际执行搜索时返回，链接数按过滤后的完整快照统计
+		page.Diagnostics = snapshot.Diagnostics
+		CountDiagnosticLinks(page.Diagnostics, snapshot.MergedByType)
+	}
+	response := model.SearchPageResponse{
+		SearchResponse: page,
+		Page:           offset/pageSize + 1,
+		PageSize:       pageSize,
+		HasMore:        hasMore,
+	}
+	if hasMore {
+		response.NextCursor = encodeCursor(pageCursor{Key: snapshotKey, Filter: filterKey, Offset: offset + pageSize, PageSize: pageSize})
+	}
+	return response, nil
+}
+
+// searchSnapshot 获取完整的合并结果快照（包含results和merged_by_type），必要时执行搜索并写入快照
+func (s *SearchService) searchSnapshot(ctx context.Context, req model.SearchRequest, snapshotKey string, useSnapshot bool) (model.SearchResponse, error) {
+	if useSnapshot && cacheInitialized && enhancedTwoLevelCache != nil {
+		if data, hit, err := enhancedTwoLevelCache.Get(snapshotKey); err == nil && hit {
+			var snapshot model.SearchResponse
+			if err := enhancedTwoLevelCache.GetSerializer().Deserialize(data, &snapshot); err == nil {
+				return snapshot, nil
+			}
+		}
+	}
+
+	snapshot, err := s.search(ctx, req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, "all", req.SourceType, req.Plugins, req.CloudTypes, req.Ext, req.CheckLinks(), req.Debug, nil)
+	if err != nil {
+		return model.SearchResponse{}, err
+	}
+
+	// 快照只保存在内存中，与搜索缓存使用相同的有效期；诊断信息只属于本次搜索，不写入快照
+	if cacheInitialized && enhancedTwoLevelCache != nil {
+		stored := snapshot
+		stored.Diagnostics = nil
+		if data, err := enhancedTwoLevelCache.GetSerializer().Serialize(stored); err == nil {
+			ttl := time.Duration(config.Get().CacheTTLMinutes) * time.Minute
+			enhancedTwoLevelCache.SetMemoryOnly(snapshotKey, data, ttl)
+		}
+	}
+	return snapshot, nil
+}
+
+// paginateResponse 从完整响应中截取一页，Total保持为完整结果数
+func paginateResponse(response model.SearchResponse, resultType string, offset, pageSize int) (model.SearchResponse, bool) {
+	page := model.SearchResponse{}
+	hasMore := false
+
+	if resultType == "all" || resultType == "results" {
+		var more bool
+		page.Results, more = pageSlice(response.Results, offset, pageSize)
+		page.Total = len(response.Results)
+		hasMore = hasMore || more
+	}
+
+	if resultType != "results" {
+		var more bool
+		var total int
+		page.MergedByType, total, more = pageMergedLinks(response.MergedByType, offset, pageSize)
+		if resultType != "all" {
+			page.Total = total
+		}
+		hasMore = hasMore || more
+	}
+
+	return page, hasMore
+}
+
+// pageSlice 截取切片中的一页
+func pageSlice(results []model.SearchResult, offset, pageSize int) ([]model.SearchResult, bool) {
+	if offset >= len(results) {
+		return []model.SearchResult{}, false
+	}
+	end := offset + pageSize
+	if end > len(results) {
+		end = len(results)
+	}
+	return results[offset:end], end < len(results)
+}
+
+// pageMergedLinks 按网盘类型名排序后逐条截取一页链接，返回分页结果、链接总数和是否还有下一页
+func pageMergedLinks(merged model.MergedLinks, offset, pageSize int) (model.MergedLinks, int, bool) {
+	types := make([]string, 0, len(merged))
+	total := 0
+	for linkType, links := range merged {
+		types = append(types, linkType)
+		total += len(links)
+	}
+	sort.Strings(types)
+
+	page := make(model.MergedLinks)
+	position := 0
+	end := offset + pageSize
+	for _, linkType := range types {
+		links := merged[linkType]
+		start := position
+		position += len(links)
+
+		// 与当前页无交集
+		if position <= offset || start >= end {
+			continue
+		}
+
+		from := offset - start
+		if from < 0 {
+			from = 0
+		}
+		to := end - start
+		if to > len(links) {
+			to = len(links)
+		}
+		page[linkType] = links[from:to]
+	}
+
+	return page, total, end < total
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"pansou/model"
+	"pansou/util/cache"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := pageCursor{Key: "abc", Filter: "f1", Offset: 40, PageSize: 20}
+	got, err := decodeCursor(encodeCursor(cursor))
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if got != cursor {
+		t.Errorf("decodeCursor() = %+v, want %+v", got, cursor)
+	}
+
+	// 没有过滤条件时不写入f字段
+	if got, _ := decodeCursor(encodeCursor(pageCursor{Key: "abc", PageSize: 20})); got.Filter != "" {
+		t.Errorf("Filter = %q, want empty", got.Filter)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"not json", encode("abc")},
+		{"missing key", encode(`{"o":0,"n":20}`)},
+		{"negative offset", encode(`{"k":"abc","o":-20,"n":20}`)},
+		{"zero page size", encode(`{"k":"abc","o":20,"n":0}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestFilterSignature(t *testing.T) {
+	include := &model.FilterConfig{Include: []string{"4k"}}
+	if filterSignature(nil) != "" || filterSignature(&model.FilterConfig{}) != "" {
+		t.Error("没有过滤条件时签名应为空")
+	}
+	if filterSignature(include) == "" {
+		t.Error("有过滤条件时签名不应为空")
+	}
+	if filterSignature(include) != filterSignature(&model.FilterConfig{Include: []string{"4k"}}) {
+		t.Error("相同的过滤条件签名应相同")
+	}
+	if filterSignature(include) == filterSignature(&model.FilterConfig{Exclude: []string{"4k"}}) {
+		t.Error("不同的过滤条件签名应不同")
+	}
+}
+
+func TestSearchPageRejectsMismatchedCursor(t *testing.T) {
+	req := model.SearchRequest{Keyword: "庆余年", SourceType: "all", Filter: &model.FilterConfig{Include: []string{"4k"}}}
+	key := cache.GenerateSearchCacheKey(req, cache.SearchCacheScopeSnapshot)
+
+	tests := []struct {
+		name   string
+		cursor pageCursor
+	}{
+		{"other search", pageCursor{Key: "other", Filter: filterSignature(req.Filter), Offset: 20, PageSize: 20}},
+		{"no filter", pageCursor{Key: key, Offset: 20, PageSize: 20}},
+		{"other filter", pageCursor{Key: key, Filter: filterSignature(&model.FilterConfig{Include: []string{"1080p"}}), Offset: 20, PageSize: 20}},
+	}
+	s := &SearchService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paged := req
+			paged.Cursor = encodeCursor(tt.cursor)
+			if _, err := s.SearchPage(context.Background(), paged, nil); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("SearchPage() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestPaginateResults(t *testing.T) {
+	results := make([]model.SearchResult, 45)
+	for i := range results {
+		results[i].UniqueID = fmt.Sprint(i)
+	}
+	response := model.SearchResponse{Results: results}
+
+	tests := []struct {
+		offset, pageSize int
+		wantFirst        string
+		wantLen          int
+		wantMore         bool
+	}{
+		{0, 20, "0", 20, true},
+		{20, 20, "20", 20, true},
+		{40, 20, "40", 5, false},
+		{25, 20, "25", 20, false},
+		{45, 20, "", 0, false},
+		{100, 20, "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("offset %d", tt.offset), func(t *testing.T) {
+			page, more := paginateResponse(response, "results", tt.offset, tt.pageSize)
+			if len(page.Results) != tt.wantLen || more != tt.wantMore || page.Total != 45 {
+				t.Fatalf("len = %d, more = %v, total = %d, want %d, %v, 45", len(page.Results), more, page.Total, tt.wantLen, tt.wantMore)
+			}
+			if tt.wantLen > 0 && page.Results[0].UniqueID != tt.wantFirst {
+				t.Errorf("first = %s, want %s", page.Results[0].UniqueID, tt.wantFirst)
+			}
+			if page.MergedByType != nil {
+				t.Error("res=results时不应返回merged_by_type")
+			}
+		})
+	}
+}
+
+func TestPaginateMergedLinks(t *testing.T) {
+	links := func(prefix string, n int) []model.MergedLink {
+		out := make([]model.MergedLink, n)
+		for i := range out {
+			out[i].URL = fmt.Sprintf("%s%d", prefix, i)
+		}
+		return out
+	}
+	urls := func(merged model.MergedLinks) map[string][]string {
+		out := make(map[string][]string)
+		for linkType, list := range merged {
+			for _, l := range list {
+				out[linkType] = append(out[linkType], l.URL)
+			}
+		}
+		return out
+	}
+	// 按网盘类型名排序：baidu(3) → quark(4) → xunlei(1)
+	merged := model.MergedLinks{"quark": links("q", 4), "baidu": links("b", 3), "xunlei": links("x", 1)}
+
+	tests := []struct {
+		offset, pageSize int
+		want             map[string][]string
+		wantMore         bool
+	}{
+		{0, 2, map[string][]string{"baidu": {"b0", "b1"}}, true},
+		{2, 3, map[string][]string{"baidu": {"b2"}, "quark": {"q0", "q1"}}, true},
+		{5, 3, map[string][]string{"quark": {"q2", "q3"}, "xunlei": {"x0"}}, false},
+		{6, 10, map[string][]string{"quark": {"q3"}, "xunlei": {"x0"}}, false},
+		{8, 2, map[string][]string{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("offset %d", tt.offset), func(t *testing.T) {
+			page, more := paginateResponse(model.SearchResponse{MergedByType: merged}, "merged_by_type", tt.offset, tt.pageSize)
+			if got := urls(page.MergedByType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("page = %v, want %v", got, tt.want)
+			}
+			if more != tt.wantMore || page.Total != 8 {
+				t.Errorf("more = %v, total = %d, want %v, 8", more, page.Total, tt.wantMore)
+			}
+		})
+	}
+}
+
+func TestPaginateAllUsesSameOffset(t *testing.T) {
+	response := model.SearchResponse{
+		Results:      make([]model.SearchResult, 3),
+		MergedByType: model.MergedLinks{"quark": make([]model.MergedLink, 10)},
+	}
+	page, more := paginateResponse(response, "all", 2, 2)
+	if len(page.Results) != 1 || len(page.MergedByType["quark"]) != 2 {
+		t.Errorf("results = %d, links = %d, want 1, 2", len(page.Results), len(page.MergedByType["quark"]))
+	}
+	// 任一部分还有下一页时has_more为true，total为results的数量
+	if !more || page.Total != 3 {
+		t.Errorf("more = %v, total = %d, want true, 3", more, page.Total)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"strings"
+	"sync"
+
+	"pansou/model"
+	"pansou/plugin"
+	"pansou/util/query"
+)
+
+// withSourceKeyword 为数据源回调附加本次上游搜索的关键词
+// 查询顶层为OR分组时，同一数据源的每个分支分别回调，由关键词区分
+func withSourceKeyword(onSource SourceCallback, keyword string) SourceCallback {
+	if onSource == nil {
+		return nil
+	}
+	return func(report SourceReport) {
+		report.Keyword = keyword
+		onSource(report)
+	}
+}
+
+// searchEachKeyword 对每个上游关键词并行执行搜索并合并结果，只有一个关键词时直接调用
+// 查询顶层为OR分组时，各分支分别发送给上游
+func searchEachKeyword(keywords []string, search func(keyword string) ([]model.SearchResult, error)) ([]model.SearchResult, error) {
+	if len(keywords) == 1 {
+		return search(keywords[0])
+	}
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		allResults []model.SearchResult
+		firstErr   error
+	)
+	for _, keyword := range keywords {
+		wg.Add(1)
+		go func(keyword string) {
+			defer wg.Done()
+			results, err := search(keyword)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			allResults = append(allResults, results...)
+		}(keyword)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return allResults, nil
+}
+
+// filterResultsByQuery 按查询语法过滤结果中的链接，没有链接满足条件的结果被移除
+// 链接有WorkTitle时匹配WorkTitle，否则匹配结果的标题和内容
+func filterResultsByQuery(results []model.SearchResult, q *query.Query) []model.SearchResult {
+	filtered := make([]model.SearchResult, 0, len(results))
+	for _, result := range results {
+		source := ResultSource(result)
+		skipTerms := resultSkipsServiceFilter(result)
+
+		links := make([]model.Link, 0, len(result.Links))
+		for _, link := range result.Links {
+			text := link.WorkTitle
+			if text == "" {
+				text = result.Title + "\n" + result.Content
+			}
+			datetime := result.Datetime
+			if !link.Datetime.IsZero() {
+				datetime = link.Datetime
+			}
+
+			if q.Match(query.Document{
+				Text:      text,
+				Types:     []string{link.Type},
+				Source:    source,
+				Datetime:  datetime,
+				SkipTerms: skipTerms,
+			}) {
+				links = append(links, link)
+			}
+		}
+
+		if len(links) > 0 {
+			result.Links = links
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// resultSkipsServiceFilter 结果的来源插件是否跳过Service层的关键词过滤
+func resultSkipsServiceFilter(result model.SearchResult) bool {
+	if result.Channel != "" || !strings.Contains(result.UniqueID, "-") {
+		return false
+	}
+	pluginName := strings.SplitN(result.UniqueID, "-", 2)[0]
+	if pluginInstance, exists := plugin.GetPluginByName(pluginName); exists {
+		return pluginInstance.SkipServiceFilter()
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/plugin"
+)
+
+// refreshStubPlugin 记录每次搜索收到的关键词和refresh参数
+type refreshStubPlugin struct {
+	*plugin.BaseAsyncPlugin
+	mu        sync.Mutex
+	refreshed map[string]bool
+}
+
+func (p *refreshStubPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	p.mu.Lock()
+	p.refreshed[keyword] = ext["refresh"] == true
+	p.mu.Unlock()
+	return []model.SearchResult{{
+		UniqueID: p.Name() + "-" + keyword,
+		Title:    keyword,
+		Links:    []model.Link{{Type: "quark", URL: "https://pan.quark.cn/s/" + keyword}},
+	}}, nil
+}
+
+// OR查询的各分支并发搜索插件，refresh=true时不能并发写同一个ext（配合 go test -race 运行）
+func TestSearchOrQueryWithRefresh(t *testing.T) {
+	setTestConfig(t, &config.Config{
+		AsyncPluginEnabled:      true,
+		AsyncResponseTimeoutDur: time.Second,
+		PluginTimeout:           time.Second,
+		DefaultConcurrency:      4,
+	})
+
+	stub := &refreshStubPlugin{
+		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin("refreshstub", 1),
+		refreshed:       make(map[string]bool),
+	}
+	manager := plugin.NewPluginManager()
+	manager.RegisterPlugin(stub)
+	s := &SearchService{pluginManager: manager}
+
+	ext := map[string]interface{}{"title_en": "x"}
+	response, err := s.search(context.Background(), "a OR b OR c OR d", nil, 0, true, "merged_by_type", "plugin", nil, nil, ext, false, false, nil)
+	if err != nil {
+		t.Fatalf("search() error = %v", err)
+	}
+
+	keywords := make([]string, 0, len(stub.refreshed))
+	for keyword, refreshed := range stub.refreshed {
+		keywords = append(keywords, keyword)
+		if !refreshed {
+			t.Errorf("%s: 插件未收到refresh", keyword)
+		}
+	}
+	sort.Strings(keywords)
+	if len(keywords) != 4 || keywords[0] != "a" || keywords[3] != "d" {
+		t.Errorf("searched keywords = %v, want [a b c d]", keywords)
+	}
+	if links := response.MergedByType["quark"]; len(links) != 4 {
+		t.Errorf("merged quark links = %d, want 4", len(links))
+	}
+
+	// 调用方的ext不被修改
+	if len(ext) != 1 || ext["refresh"] != nil {
+		t.Errorf("ext = %v, want unchanged", ext)
+	}
+}
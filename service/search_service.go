@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"pansou/config"
@@ -17,7 +19,9 @@ import (
 	"pansou/plugin"
 	"pansou/util"
 	"pansou/util/cache"
+	"pansou/util/normalize"
 	"pansou/util/pool"
+	"pansou/util/query"
 )
 
 // normalizeUrl 标准化URL，将URL编码的中文部分解码为中文，用于去重
@@ -62,7 +66,7 @@ func extractKeywordFromCacheKey(cacheKey string) string {
 // logAsyncCacheWithKeyword 异步缓存日志输出辅助函数（带关键词）
 func logAsyncCacheWithKeyword(keyword, cacheKey string, format string, args ...interface{}) {
 	// 检查配置开关
-	if config.AppConfig == nil || !config.AppConfig.AsyncLogEnabled {
+	if config.Get() == nil || !config.Get().AsyncLogEnabled {
 		return
 	}
 
@@ -91,7 +95,7 @@ var (
 
 // 初始化缓存
 func init() {
-	if config.AppConfig != nil && config.AppConfig.CacheEnabled {
+	if config.Get() != nil && config.Get().CacheEnabled {
 		var err error
 		// 使用增强版缓存
 		enhancedTwoLevelCache, err = cache.NewEnhancedTwoLevelCache()
@@ -197,15 +201,37 @@ func calculateCompletenessScore(result model.SearchResult) int {
 	return score
 }
 
+// ErrSourceTimeout 数据源未在响应超时内返回结果（插件会在后台继续处理并写入缓存）
+var ErrSourceTimeout = errors.New("数据源响应超时")
+
+// ErrCircuitOpen 插件连续失败后熔断，冷却期内不调用
+var ErrCircuitOpen = errors.New("插件已熔断")
+
+// SourceReport 单个数据源（TG频道或插件）本次搜索的返回情况
+type SourceReport struct {
+	Source  string               // 格式与MergedLink.Source一致：tg:频道名 或 plugin:插件名
+	Keyword string               // 上游搜索的关键词，查询语法有OR分支时同一数据源每个分支各回调一次
+	Status  string               // model.SourceStatus*
+	Results []model.SearchResult // 插件结果已过滤掉无链接的结果
+	Latency time.Duration
+	Err     error // 超时时为ErrSourceTimeout或context.DeadlineExceeded
+}
+
+// SourceCallback 单个数据源返回结果时的回调
+// 回调可能在多个goroutine中并发执行，调用方需自行保证并发安全
+type SourceCallback func(report SourceReport)
+
 // SearchService 搜索服务
 type SearchService struct {
 	pluginManager *plugin.PluginManager
+	checkService  *CheckService // 链接检测服务，为nil时不标注链接状态
+	prober        *PluginProber // 插件合成探测，为nil时未启用
 }
 
 // NewSearchService 创建搜索服务实例并确保缓存可用
 func NewSearchService(pluginManager *plugin.PluginManager) *SearchService {
 	// 检查缓存是否已初始化，如果未初始化则尝试重新初始化
-	if !cacheInitialized && config.AppConfig != nil && config.AppConfig.CacheEnabled {
+	if !cacheInitialized && config.Get() != nil && config.Get().CacheEnabled {
 		var err error
 		// 使用增强版缓存
 		enhancedTwoLevelCache, err = cache.NewEnhancedTwoLevelCache()
@@ -224,9 +250,21 @@ func NewSearchService(pluginManager *plugin.PluginManager) *SearchService {
 		})
 	}
 
-	return &SearchService{
+	s := &SearchService{
 		pluginManager: pluginManager,
 	}
+
+	// 应用管理接口保存的插件运行时状态
+	s.loadPluginState()
+	config.OnReload(s.applyConfigReload)
+
+	// 定期探测已启用的插件
+	if pluginManager != nil && config.Get().AsyncPluginEnabled && config.Get().PluginProbeInterval > 0 {
+		s.prober = newPluginProber(pluginManager, config.Get().PluginProbeInterval, config.Get().PluginProbeKeyword, config.Get().PluginProbeHistory)
+		s.prober.start()
+	}
+
+	return s
 }
 
 // injectMainCacheToAsyncPlugins 将主缓存系统注入到异步插件中
@@ -256,7 +294,7 @@ func injectMainCacheToAsyncPlugins(pluginManager *plugin.PluginManager, mainCach
 			if err := mainCache.GetSerializer().Deserialize(existingData, &existingResults); err == nil {
 				// 合并新旧结果，去重保留最完整的数据
 				finalResults = mergeSearchResults(existingResults, newResults)
-				if config.AppConfig != nil && config.AppConfig.AsyncLogEnabled {
+				if config.Get() != nil && config.Get().AsyncLogEnabled {
 					if keyword != "" {
 						fmt.Printf("🔄 [%s:%s] 更新缓存| 原有: %d + 新增: %d = 合并后: %d\n",
 							pluginName, keyword, len(existingResults), len(newResults), len(finalResults))
@@ -265,7 +303,7 @@ func injectMainCacheToAsyncPlugins(pluginManager *plugin.PluginManager, mainCach
 			} else {
 				// 反序列化失败，使用新结果
 				finalResults = newResults
-				if config.AppConfig != nil && config.AppConfig.AsyncLogEnabled {
+				if config.Get() != nil && config.Get().AsyncLogEnabled {
 					displayKey := key[:8] + "..."
 					if keyword != "" {
 						fmt.Printf("[异步插件 %s] 缓存反序列化失败，使用新结果: %s(关键词:%s) | 结果数: %d\n", pluginName, displayKey, keyword, len(newResults))
@@ -277,7 +315,7 @@ func injectMainCacheToAsyncPlugins(pluginManager *plugin.PluginManager, mainCach
 		} else {
 			// 无现有缓存，直接使用新结果
 			finalResults = newResults
-			if config.AppConfig != nil && config.AppConfig.AsyncLogEnabled {
+			if config.Get() != nil && config.Get().AsyncLogEnabled {
 				displayKey := key[:8] + "..."
 				if keyword != "" {
 					fmt.Printf("[异步插件 %s] 初始缓存创建: %s(关键词:%s) | 结果数: %d\n", pluginName, displayKey, keyword, len(newResults))
@@ -350,11 +388,129 @@ func injectMainCacheToAsyncPlugins(pluginManager *plugin.PluginManager, mainCach
 }
 
 // Search 执行搜索
-func (s *SearchService) Search(keyword string, channels []string, concurrency int, forceRefresh bool, resultType string, sourceType string, plugins []string, cloudTypes []string, ext map[string]interface{}) (model.SearchResponse, error) {
-	// 确保ext不为nil
-	if ext == nil {
-		ext = make(map[string]interface{})
+// ctx通常为HTTP请求的上下文，客户端断开时停止TG频道抓取和插件的前台请求；
+// checkLinks为true时标注合并链接的检测状态并优先排列有效链接；debug为true时在响应中附带各数据源的诊断信息
+func (s *SearchService) Search(ctx context.Context, keyword string, channels []string, concurrency int, forceRefresh bool, resultType string, sourceType string, plugins []string, cloudTypes []string, ext map[string]interface{}, checkLinks bool, debug bool) (model.SearchResponse, error) {
+	return s.search(ctx, keyword, channels, concurrency, forceRefresh, resultType, sourceType, plugins, cloudTypes, ext, checkLinks, debug, nil)
+}
+
+// SearchStream 流式搜索：每个TG频道和插件返回时通过emit推送一次source事件（查询有OR分支时每个分支各推送一次），
+// 全部完成后推送merged（merged_by_type快照）事件和done事件（列出超时和失败的数据源）
+func (s *SearchService) SearchStream(ctx context.Context, keyword string, channels []string, concurrency int, forceRefresh bool, sourceType string, plugins []string, cloudTypes []string, ext map[string]interface{}, checkLinks bool, filter func(model.SearchResponse) model.SearchResponse, emit func(event string, data interface{})) error {
+	startTime := time.Now()
+
+	var mu sync.Mutex
+	closed := false
+	reported := make(map[string]bool) // 已推送的数据源和关键词
+	sources := make(map[string]bool)  // 已返回的数据源
+	timedOut := make([]string, 0)
+	failed := make([]string, 0)
+
+	onSource := func(report SourceReport) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// 整体搜索已结束（例如批量任务超时后才返回的数据源），不再推送
+		key := report.Source + "\x00" + report.Keyword
+		if closed || reported[key] {
+			return
+		}
+		reported[key] = true
+		sources[report.Source] = true
+
+		results := report.Results
+		if results == nil {
+			results = []model.SearchResult{}
+		}
+
+		event := model.StreamSourceEvent{
+			Source:    report.Source,
+			Keyword:   report.Keyword,
+			Count:     len(results),
+			Results:   results,
+			ElapsedMs: time.Since(startTime).Milliseconds(),
+		}
+		if report.Err != nil {
+			event.Error = report.Err.Error()
+			if report.Status == model.SourceStatusTimeout {
+				timedOut = appendSource(timedOut, report.Source)
+			} else {
+				failed = appendSource(failed, report.Source)
+			}
+		}
+		emit(model.StreamEventSource, event)
+	}
+
+	response, err := s.search(ctx, keyword, channels, concurrency, forceRefresh, "merged_by_type", sourceType, plugins, cloudTypes, ext, checkLinks, false, onSource)
+
+	mu.Lock()
+	closed = true
+	mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	// 未在批量超时内返回的数据源同样视为超时
+	for _, source := range s.expectedSources(sourceType, channels, plugins) {
+		if !sources[source] {
+			timedOut = appendSource(timedOut, source)
+		}
+	}
+
+	if filter != nil {
+		response = filter(response)
+	}
+
+	emit(model.StreamEventMerged, model.StreamMergedEvent{
+		Total:        response.Total,
+		MergedByType: response.MergedByType,
+	})
+
+	emit(model.StreamEventDone, model.StreamDoneEvent{
+		Total:     response.Total,
+		TimedOut:  timedOut,
+		Failed:    failed,
+		ElapsedMs: time.Since(startTime).Milliseconds(),
+	})
+
+	return nil
+}
+
+// appendSource 将数据源加入列表，已存在时不重复添加
+func appendSource(list []string, source string) []string {
+	for _, s := range list {
+		if s == source {
+			return list
+		}
+	}
+	return append(list, source)
+}
+
+// expectedSources 返回本次搜索应当返回结果的全部数据源
+func (s *SearchService) expectedSources(sourceType string, channels []string, plugins []string) []string {
+	if sourceType == "" {
+		sourceType = "all"
+	}
+
+	sources := make([]string, 0, len(channels))
+	if sourceType == "all" || sourceType == "tg" {
+		for _, channel := range channels {
+			sources = append(sources, "tg:"+channel)
+		}
+	}
+	if (sourceType == "all" || sourceType == "plugin") && config.Get().AsyncPluginEnabled {
+		for _, p := range s.resolvePlugins(plugins) {
+			sources = append(sources, "plugin:"+p.Name())
+		}
 	}
+	return sources
+}
+
+// search 执行搜索，onSource不为nil时在每个数据源返回时回调
+func (s *SearchService) search(ctx context.Context, keyword string, channels []string, concurrency int, forceRefresh bool, resultType string, sourceType string, plugins []string, cloudTypes []string, ext map[string]interface{}, checkLinks bool, debug bool, onSource SourceCallback) (model.SearchResponse, error) {
+	// 去掉调用方传入的保留键（同时确保ext不为nil），搜索上下文和调用状态由插件框架自行设置
+	ext = plugin.StripReservedExt(ext)
 
 	// 参数预处理
 	// 源类型标准化
@@ -362,6 +518,16 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 		sourceType = "all"
 	}
 
+	// 解析kw中的查询语法，上游只搜索纯关键词，其余条件在合并结果时过滤
+	q := query.Parse(keyword)
+	if err := q.Validate(); err != nil {
+		return model.SearchResponse{}, err
+	}
+	upstreamKeywords := q.UpstreamKeywords()
+	if required := q.RequiredSource(); required != "" && sourceType == "all" {
+		sourceType = required
+	}
+
 	// 插件参数规范化处理
 	if sourceType == "tg" {
 		// 对于只搜索Telegram的请求，忽略插件参数
@@ -427,7 +593,14 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 
 	// 如果未指定并发数，使用配置中的默认值
 	if concurrency <= 0 {
-		concurrency = config.AppConfig.DefaultConcurrency
+		concurrency = config.Get().DefaultConcurrency
+	}
+
+	// 需要诊断信息时收集每个数据源的返回情况
+	var diagnostics *sourceDiagnostics
+	if debug {
+		diagnostics = newSourceDiagnostics(onSource)
+		onSource = diagnostics.report
 	}
 
 	// 并行获取TG搜索和插件搜索结果
@@ -442,17 +615,21 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			tgResults, tgErr = s.searchTG(keyword, channels, forceRefresh)
+			tgResults, tgErr = searchEachKeyword(upstreamKeywords, func(kw string) ([]model.SearchResult, error) {
+				return s.searchTG(ctx, kw, channels, cloudTypes, forceRefresh, withSourceKeyword(onSource, kw))
+			})
 		}()
 	}
 	// 如果需要搜索插件（且插件功能已启用）
-	if (sourceType == "all" || sourceType == "plugin") && config.AppConfig.AsyncPluginEnabled {
+	if (sourceType == "all" || sourceType == "plugin") && config.Get().AsyncPluginEnabled {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			// 对于插件搜索，我们总是希望获取最新的缓存数据
 			// 因此，即使forceRefresh=false，我们也需要确保获取到最新的缓存
-			pluginResults, pluginErr = s.searchPlugins(keyword, plugins, forceRefresh, concurrency, ext)
+			pluginResults, pluginErr = searchEachKeyword(upstreamKeywords, func(kw string) ([]model.SearchResult, error) {
+				return s.searchPlugins(ctx, kw, plugins, cloudTypes, forceRefresh, concurrency, ext, withSourceKeyword(onSource, kw))
+			})
 		}()
 	}
 
@@ -470,13 +647,19 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 	// 合并结果
 	allResults := mergeSearchResults(tgResults, pluginResults)
 
+	// 需要检测链接时，先读取检测缓存中的已知状态用于排序
+	var linkStates map[string]string
+	if checkLinks {
+		linkStates = s.knownLinkStates(allResults)
+	}
+
 	// 按照优化后的规则排序结果
-	sortResultsByTimeAndKeywords(allResults)
+	sortResultsByTimeAndKeywords(allResults, linkStates)
 
 	// 过滤结果，只保留有时间的结果或包含优先关键词的结果或高等级插件结果到Results中
 	filteredForResults := make([]model.SearchResult, 0, len(allResults))
 	for _, result := range allResults {
-		source := getResultSource(result)
+		source := ResultSource(result)
 		pluginLevel := getPluginLevelBySource(source)
 
 		// 有时间的结果或包含优先关键词的结果或高等级插件(1-2级)结果保留在Results中
@@ -485,8 +668,18 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 		}
 	}
 
+	// 使用查询语法时，Results中只保留满足条件的链接
+	if q.Structured() {
+		filteredForResults = filterResultsByQuery(filteredForResults, q)
+	}
+
 	// 合并链接按网盘类型分组（使用所有过滤后的结果）
-	mergedLinks := mergeResultsByType(allResults, keyword, cloudTypes)
+	mergedLinks := mergeResultsByType(allResults, q, cloudTypes)
+
+	// 标注链接检测状态，仅返回results时不需要
+	if checkLinks && resultType != "results" {
+		mergedLinks = s.annotateLinkStates(ctx, mergedLinks, linkStates)
+	}
 
 	// 构建响应
 	var total int
@@ -508,7 +701,11 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 	}
 
 	// 根据resultType过滤返回结果
-	return filterResponseByType(response, resultType), nil
+	response = filterResponseByType(response, resultType)
+	if diagnostics != nil {
+		response.Diagnostics = diagnostics.build(s.expectedSources(sourceType, channels, plugins), mergedLinks)
+	}
+	return response, nil
 }
 
 // filterResponseByType 根据结果类型过滤响应
@@ -540,26 +737,28 @@ func filterResponseByType(response model.SearchResponse, resultType string) mode
 	}
 }
 
-// 根据时间和关键词排序结果
-func sortResultsByTimeAndKeywords(results []model.SearchResult) {
+// 根据时间、关键词和链接检测状态排序结果，linkStates为nil时不考虑链接状态
+func sortResultsByTimeAndKeywords(results []model.SearchResult, linkStates map[string]string) {
 	// 1. 计算每个结果的综合得分
 	scores := make([]ResultScore, len(results))
 
 	for i, result := range results {
-		source := getResultSource(result)
+		source := ResultSource(result)
 
 		scores[i] = ResultScore{
 			Result:       result,
 			TimeScore:    calculateTimeScore(result.Datetime),
 			KeywordScore: getKeywordPriority(result.Title),
 			PluginScore:  getPluginLevelScore(source),
+			LinkScore:    getLinkStateScore(result, linkStates),
 			TotalScore:   0, // 稍后计算
 		}
 
 		// 计算综合得分
 		scores[i].TotalScore = scores[i].TimeScore +
 			float64(scores[i].KeywordScore) +
-			float64(scores[i].PluginScore)
+			float64(scores[i].PluginScore) +
+			float64(scores[i].LinkScore)
 	}
 
 	// 2. 按综合得分排序
@@ -585,44 +784,114 @@ func getKeywordPriority(title string) int {
 	return 0
 }
 
-// 搜索单个频道
-func (s *SearchService) searchChannel(keyword string, channel string) ([]model.SearchResult, error) {
+// searchChannel 搜索单个TG频道
+// 沿before分页参数向更早的消息翻页，最多抓取TGSearchMaxPages页且总耗时不超过TGSearchTimeout，
+// 结果按消息ID去重。第一页失败时返回错误，后续页失败或超出预算时返回已抓取的结果。
+func (s *SearchService) searchChannel(ctx context.Context, keyword string, channel string, forceRefresh bool) ([]model.SearchResult, error) {
+	maxPages := config.Get().TGSearchMaxPages
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+
+	// 整个频道的时间预算，请求取消时同时中止抓取
+	ctx, cancel := context.WithTimeout(ctx, config.Get().TGSearchTimeout)
+	defer cancel()
+
+	var results []model.SearchResult
+	seen := make(map[string]bool)
+	pageParam := ""
+
+	for page := 0; page < maxPages; page++ {
+		pageResults, nextPageParam, err := s.fetchChannelPage(ctx, keyword, channel, pageParam, forceRefresh)
+		if err != nil {
+			if page == 0 {
+				return nil, err
+			}
+			break
+		}
+
+		for _, result := range pageResults {
+			if seen[result.MessageID] {
+				continue
+			}
+			seen[result.MessageID] = true
+			results = append(results, result)
+		}
+
+		// 没有更早的消息
+		if nextPageParam == "" || nextPageParam == pageParam {
+			break
+		}
+		pageParam = nextPageParam
+	}
+
+	return results, nil
+}
+
+// cachedChannelPage 缓存的TG频道单页搜索结果
+type cachedChannelPage struct {
+	Results       []model.SearchResult
+	NextPageParam string
+}
+
+// fetchChannelPage 抓取并解析TG频道搜索结果的一页，返回本页结果和下一页的分页参数
+// 每页结果单独缓存，更早的页内容基本不变，后续搜索翻页时可以直接复用
+func (s *SearchService) fetchChannelPage(ctx context.Context, keyword string, channel string, pageParam string, forceRefresh bool) ([]model.SearchResult, string, error) {
+	cacheKey := cache.GenerateTGPageCacheKey(channel, keyword, pageParam)
+	cacheEnabled := cacheInitialized && config.Get().CacheEnabled && enhancedTwoLevelCache != nil
+
+	if !forceRefresh && cacheEnabled {
+		if data, hit, err := enhancedTwoLevelCache.Get(cacheKey); err == nil && hit {
+			var page cachedChannelPage
+			if err := enhancedTwoLevelCache.GetSerializer().Deserialize(data, &page); err == nil {
+				return page.Results, page.NextPageParam, nil
+			}
+		}
+	}
+
 	// 构建搜索URL
-	url := util.BuildSearchURL(channel, keyword, "")
+	url := util.BuildSearchURL(channel, keyword, pageParam)
 
 	// 使用全局HTTP客户端（已配置代理）
 	client := util.GetHTTPClient()
 
-	// 创建一个带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	// 单页请求的超时，同时受频道整体时间预算限制
+	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
 	defer cancel()
 
 	// 创建请求
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// 发送请求
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
 	// 读取响应体
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// 解析响应
-	results, _, err := util.ParseSearchResults(string(body), channel)
+	results, nextPageParam, err := util.ParseSearchResults(string(body), channel)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return results, nil
+	if cacheEnabled {
+		ttl := time.Duration(config.Get().CacheTTLMinutes) * time.Minute
+		if data, err := enhancedTwoLevelCache.GetSerializer().Serialize(cachedChannelPage{Results: results, NextPageParam: nextPageParam}); err == nil {
+			enhancedTwoLevelCache.Set(cacheKey, data, ttl)
+		}
+	}
+
+	return results, nextPageParam, nil
 }
 
 // 用于从消息内容中提取链接-标题对应关系的函数
@@ -987,15 +1256,24 @@ func isEmpty(line string) bool {
 }
 
 // 将搜索结果按网盘类型分组
-func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes []string) model.MergedLinks {
+func mergeResultsByType(results []model.SearchResult, q *query.Query, cloudTypes []string) model.MergedLinks {
 	// 创建合并结果的映射
 	mergedLinks := make(model.MergedLinks, 12) // 预分配容量，假设有12种不同的网盘类型
 
-	// 用于去重的映射，键为URL
+	// 用于去重的映射，键为链接的规范标识（网盘类型+分享ID），同一分享的不同写法视为同一链接
 	uniqueLinks := make(map[string]model.MergedLink)
+	// 按首次出现的顺序记录链接标识和链接类型，每个链接的标识只解析一次
+	orderedKeys := make([]string, 0)
+	linkTypes := make(map[string]string)
+
+	// 未使用查询语法时整体匹配关键词，否则按语法树过滤
+	keyword := q.Raw
+	if q.Structured() {
+		keyword = ""
+	}
 
-	// 将关键词转为小写，用于不区分大小写的匹配
-	lowerKeyword := strings.ToLower(keyword)
+	// 关键词归一化（繁简、全半角、大小写、标点和序数），与同样归一化的标题比较
+	normalizedKeyword := normalize.Text(keyword)
 
 	// 遍历所有搜索结果
 	for _, result := range results {
@@ -1095,7 +1373,7 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 			// 关键词过滤：现在我们有了准确的链接-标题对应关系，只需检查每个链接的具体标题
 			if !skipKeywordFilter && keyword != "" {
 				// 只检查链接的具体标题，无论是TG来源还是插件来源
-				if !strings.Contains(strings.ToLower(title), lowerKeyword) {
+				if !strings.Contains(normalize.Text(title), normalizedKeyword) {
 					continue
 				}
 			}
@@ -1125,63 +1403,68 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 				linkDatetime = link.Datetime
 			}
 
+			// 查询语法过滤：关键词同样只检查链接的具体标题
+			if q.Structured() && !q.Match(query.Document{
+				Text:      title,
+				Types:     []string{link.Type},
+				Source:    source,
+				Datetime:  linkDatetime,
+				SkipTerms: skipKeywordFilter,
+			}) {
+				continue
+			}
+
+			// 链接未单独给出提取码时，使用链接中携带的提取码
+			password := link.Password
+			identity, hasIdentity := util.CanonicalShareIdentity(link.URL)
+			if password == "" && hasIdentity {
+				password = identity.Password
+			}
+
 			mergedLink := model.MergedLink{
 				URL:      link.URL,
-				Password: link.Password,
+				Password: password,
 				Note:     title, // 使用找到的特定标题
 				Datetime: linkDatetime,
 				Source:   source,        // 添加数据来源字段
 				Images:   result.Images, // 添加TG消息中的图片链接
 			}
 
-			// 检查是否已存在相同URL的链接
-			if existingLink, exists := uniqueLinks[link.URL]; exists {
-				// 如果已存在，只有当当前链接的时间更新时才替换
-				if mergedLink.Datetime.After(existingLink.Datetime) {
-					uniqueLinks[link.URL] = mergedLink
-				}
-			} else {
-				// 如果不存在，直接添加
-				uniqueLinks[link.URL] = mergedLink
+			linkKey := strings.TrimSpace(link.URL)
+			if hasIdentity {
+				linkKey = identity.Key()
 			}
-		}
-	}
 
-	// 为保持排序顺序，按原始results顺序处理链接，而不是随机遍历map
-	// 创建一个有序的链接列表，按原始results中的顺序
-	orderedLinks := make([]model.MergedLink, 0, len(uniqueLinks))
-	linkTypeMap := make(map[string]string) // URL -> Type的映射
-
-	// 按原始results的顺序收集唯一链接
-	for _, result := range results {
-		for _, link := range result.Links {
-			if mergedLink, exists := uniqueLinks[link.URL]; exists {
-				// 检查是否已经添加过这个链接
-				found := false
-				for _, existing := range orderedLinks {
-					if existing.URL == link.URL {
-						found = true
-						break
+			// 检查是否已存在同一分享的链接
+			if existingLink, exists := uniqueLinks[linkKey]; exists {
+				// 如果已存在，只有当当前链接的时间更新时才替换，提取码保留非空的一方
+				if mergedLink.Datetime.After(existingLink.Datetime) {
+					if mergedLink.Password == "" {
+						mergedLink.Password = existingLink.Password
 					}
+					uniqueLinks[linkKey] = mergedLink
+				} else if existingLink.Password == "" && mergedLink.Password != "" {
+					existingLink.Password = mergedLink.Password
+					uniqueLinks[linkKey] = existingLink
 				}
-				if !found {
-					orderedLinks = append(orderedLinks, mergedLink)
-					linkTypeMap[link.URL] = link.Type
-				}
+			} else {
+				// 如果不存在，直接添加
+				uniqueLinks[linkKey] = mergedLink
+				orderedKeys = append(orderedKeys, linkKey)
+				linkTypes[linkKey] = link.Type
 			}
 		}
 	}
 
-	// 将有序链接按类型分组
-	for _, mergedLink := range orderedLinks {
-		// 从预建的映射中获取链接类型
-		linkType := linkTypeMap[mergedLink.URL]
+	// 为保持排序顺序，按原始results中首次出现的顺序将唯一链接按类型分组，而不是随机遍历map
+	for _, linkKey := range orderedKeys {
+		linkType := linkTypes[linkKey]
 		if linkType == "" {
 			linkType = "unknown"
 		}
 
 		// 添加到对应类型的列表中
-		mergedLinks[linkType] = append(mergedLinks[linkType], mergedLink)
+		mergedLinks[linkType] = append(mergedLinks[linkType], uniqueLinks[linkKey])
 	}
 
 	// 如果指定了cloudTypes，则过滤结果
@@ -1209,12 +1492,16 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 }
 
 // searchTG 搜索TG频道
-func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh bool) ([]model.SearchResult, error) {
+func (s *SearchService) searchTG(ctx context.Context, keyword string, channels []string, cloudTypes []string, forceRefresh bool, onSource SourceCallback) ([]model.SearchResult, error) {
 	// 生成缓存键
-	cacheKey := cache.GenerateTGCacheKey(keyword, channels)
+	cacheKey := cache.GenerateSearchCacheKey(model.SearchRequest{
+		Keyword:    keyword,
+		Channels:   channels,
+		CloudTypes: cloudTypes,
+	}, cache.SearchCacheScopeTG)
 
 	// 如果未启用强制刷新，尝试从缓存获取结果
-	if !forceRefresh && cacheInitialized && config.AppConfig.CacheEnabled {
+	if !forceRefresh && cacheInitialized && config.Get().CacheEnabled {
 		var data []byte
 		var hit bool
 		var err error
@@ -1227,6 +1514,13 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 				var results []model.SearchResult
 				if err := enhancedTwoLevelCache.GetSerializer().Deserialize(data, &results); err == nil {
 					// 直接返回缓存数据，不检查新鲜度
+					if onSource != nil {
+						sources := make([]string, 0, len(channels))
+						for _, channel := range channels {
+							sources = append(sources, "tg:"+channel)
+						}
+						reportCachedSources(onSource, sources, results)
+					}
 					return results, nil
 				}
 			}
@@ -1242,7 +1536,18 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 	for _, channel := range channels {
 		ch := channel // 创建副本，避免闭包问题
 		tasks = append(tasks, func() interface{} {
-			results, err := s.searchChannel(keyword, ch)
+			startTime := time.Now()
+			results, err := s.searchChannel(ctx, keyword, ch, forceRefresh)
+			recordChannelFetch(ch, err)
+			if onSource != nil {
+				onSource(SourceReport{
+					Source:  "tg:" + ch,
+					Status:  sourceStatusForError(err),
+					Results: results,
+					Latency: time.Since(startTime),
+					Err:     err,
+				})
+			}
 			if err != nil {
 				return nil
 			}
@@ -1251,7 +1556,12 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 	}
 
 	// 执行搜索任务并获取结果
-	taskResults := pool.ExecuteBatchWithTimeout(tasks, len(channels), config.AppConfig.PluginTimeout)
+	taskResults := pool.ExecuteBatchWithContext(ctx, tasks, len(channels), config.Get().PluginTimeout)
+
+	// 请求已取消，结果不完整，不写入缓存
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// 合并所有频道的结果
 	for _, result := range taskResults {
@@ -1262,9 +1572,9 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 	}
 
 	// 异步缓存结果
-	if cacheInitialized && config.AppConfig.CacheEnabled {
+	if cacheInitialized && config.Get().CacheEnabled {
 		go func(res []model.SearchResult) {
-			ttl := time.Duration(config.AppConfig.CacheTTLMinutes) * time.Minute
+			ttl := time.Duration(config.Get().CacheTTLMinutes) * time.Minute
 
 			// 使用增强版缓存
 			if enhancedTwoLevelCache != nil {
@@ -1281,22 +1591,36 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 }
 
 // searchPlugins 搜索插件
-func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRefresh bool, concurrency int, ext map[string]interface{}) ([]model.SearchResult, error) {
+func (s *SearchService) searchPlugins(ctx context.Context, keyword string, plugins []string, cloudTypes []string, forceRefresh bool, concurrency int, ext map[string]interface{}, onSource SourceCallback) ([]model.SearchResult, error) {
 	// 确保ext不为nil
 	if ext == nil {
 		ext = make(map[string]interface{})
 	}
 
 	// 关键：将forceRefresh同步到插件ext["refresh"]
+	// ext来自调用方的请求，且OR查询的各分支并发使用同一个ext，只能在副本上设置
 	if forceRefresh {
-		ext["refresh"] = true
+		refreshExt := make(map[string]interface{}, len(ext)+1)
+		for k, v := range ext {
+			refreshExt[k] = v
+		}
+		refreshExt["refresh"] = true
+		ext = refreshExt
 	}
 
-	// 生成缓存键
-	cacheKey := cache.GeneratePluginCacheKey(keyword, plugins)
+	// 生成缓存键，包含插件声明会影响结果的ext参数
+	cacheKey := cache.GenerateSearchCacheKey(model.SearchRequest{
+		Keyword:    keyword,
+		Plugins:    plugins,
+		CloudTypes: cloudTypes,
+		Ext:        ext,
+	}, cache.SearchCacheScopePlugin)
+
+	// 获取本次搜索涉及的插件
+	availablePlugins := s.resolvePlugins(plugins)
 
 	// 如果未启用强制刷新，尝试从缓存获取结果
-	if !forceRefresh && cacheInitialized && config.AppConfig.CacheEnabled {
+	if !forceRefresh && cacheInitialized && config.Get().CacheEnabled {
 		var data []byte
 		var hit bool
 		var err error
@@ -1313,6 +1637,13 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 				if err := enhancedTwoLevelCache.GetSerializer().Deserialize(data, &results); err == nil {
 					// 返回缓存数据
 					fmt.Printf("✅ [%s] 命中缓存 结果数: %d\n", keyword, len(results))
+					if onSource != nil {
+						sources := make([]string, 0, len(availablePlugins))
+						for _, p := range availablePlugins {
+							sources = append(sources, "plugin:"+p.Name())
+						}
+						reportCachedSources(onSource, sources, results)
+					}
 					return results, nil
 				} else {
 					displayKey := cacheKey[:8] + "..."
@@ -1324,48 +1655,10 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 
 	// 缓存未命中或强制刷新，执行实际搜索
 
-	// 获取所有可用插件
-	var availablePlugins []plugin.AsyncSearchPlugin
-	if s.pluginManager != nil {
-		allPlugins := s.pluginManager.GetPlugins()
-
-		// 确保plugins不为nil并且有非空元素
-		hasPlugins := plugins != nil && len(plugins) > 0
-		hasNonEmptyPlugin := false
-
-		if hasPlugins {
-			for _, p := range plugins {
-				if p != "" {
-					hasNonEmptyPlugin = true
-					break
-				}
-			}
-		}
-
-		// 只有当plugins数组包含非空元素时才进行过滤
-		if hasPlugins && hasNonEmptyPlugin {
-			pluginMap := make(map[string]bool)
-			for _, p := range plugins {
-				if p != "" { // 忽略空字符串
-					pluginMap[strings.ToLower(p)] = true
-				}
-			}
-
-			for _, p := range allPlugins {
-				if pluginMap[strings.ToLower(p.Name())] {
-					availablePlugins = append(availablePlugins, p)
-				}
-			}
-		} else {
-			// 如果plugins为nil、空数组或只包含空字符串，视为未指定，使用所有插件
-			availablePlugins = allPlugins
-		}
-	}
-
 	// 控制并发数
 	if concurrency <= 0 {
 		// 使用配置中的默认值
-		concurrency = config.AppConfig.DefaultConcurrency
+		concurrency = config.Get().DefaultConcurrency
 	}
 
 	// 使用工作池执行并行搜索
@@ -1373,16 +1666,70 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 	for _, p := range availablePlugins {
 		plugin := p // 创建副本，避免闭包问题
 		tasks = append(tasks, func() interface{} {
-			// 设置主缓存键和当前关键词
-			plugin.SetMainCacheKey(cacheKey)
-			plugin.SetCurrentKeyword(keyword)
+			// 熔断中的插件直接跳过，避免每次搜索都等到超时
+			if !allowPluginSearch(plugin.Name()) {
+				if onSource != nil {
+					onSource(SourceReport{
+						Source: "plugin:" + plugin.Name(),
+						Status: model.SourceStatusCircuitOpen,
+						Err:    ErrCircuitOpen,
+					})
+				}
+				return nil
+			}
+
+			// 记录插件搜索函数是否已返回以及结果是否为最终结果，用于判断是否超时
+			var searchState int32 = searchStatePending
+			startTime := time.Now()
+
+			// 调用异步插件的AsyncSearch方法，主缓存键和关键词随本次调用传递，不写入共享的插件实例
+			results, err := plugin.AsyncSearchWithContext(ctx, keyword, func(client *http.Client, kw string, extParams map[string]interface{}) ([]model.SearchResult, error) {
+				// 优先使用带IsFinal标记的搜索方法，以区分超时返回的空结果
+				if resultPlugin, ok := plugin.(searchWithResultPlugin); ok {
+					result, err := resultPlugin.SearchWithResult(kw, extParams)
+					if err == nil && !result.IsFinal {
+						atomic.StoreInt32(&searchState, searchStatePartial)
+					} else {
+						atomic.StoreInt32(&searchState, searchStateFinal)
+					}
+					return result.Results, err
+				}
 
-			// 调用异步插件的AsyncSearch方法
-			results, err := plugin.AsyncSearch(keyword, func(client *http.Client, kw string, extParams map[string]interface{}) ([]model.SearchResult, error) {
 				// 使用插件的Search方法作为搜索函数
-				return plugin.Search(kw, extParams)
+				results, err := plugin.Search(kw, extParams)
+				atomic.StoreInt32(&searchState, searchStateFinal)
+				return results, err
 			}, cacheKey, ext)
 
+			elapsed := time.Since(startTime)
+			timedOut := err == nil && len(results) == 0 && atomic.LoadInt32(&searchState) != searchStateFinal
+			recordPluginSearch(plugin.Name(), elapsed, err, timedOut)
+			recordPluginHealth(ctx, plugin.Name(), err, timedOut, atomic.LoadInt32(&searchState) == searchStatePending && len(results) > 0)
+
+			if onSource != nil {
+				report := SourceReport{
+					Source:  "plugin:" + plugin.Name(),
+					Status:  sourceStatusForError(err),
+					Results: filterResultsWithLinks(results),
+					Latency: elapsed,
+					Err:     err,
+				}
+				if timedOut {
+					report.Status = model.SourceStatusTimeout
+					report.Err = ErrSourceTimeout
+				} else if err == nil {
+					switch atomic.LoadInt32(&searchState) {
+					case searchStatePartial:
+						// 插件在内部响应超时前只返回了部分结果
+						report.Status = model.SourceStatusPartial
+					case searchStatePending:
+						// 搜索函数未返回就有结果，说明命中了插件自身的缓存
+						report.Status = model.SourceStatusCacheHit
+					}
+				}
+				onSource(report)
+			}
+
 			if err != nil {
 				return nil
 			}
@@ -1391,7 +1738,12 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 	}
 
 	// 执行搜索任务并获取结果
-	results := pool.ExecuteBatchWithTimeout(tasks, concurrency, config.AppConfig.PluginTimeout)
+	results := pool.ExecuteBatchWithContext(ctx, tasks, concurrency, config.Get().PluginTimeout)
+
+	// 请求已取消，结果不完整，不覆盖主缓存（已在后台运行的插件仍会自行更新缓存）
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// 合并所有插件的结果，过滤掉无链接的结果
 	var allResults []model.SearchResult
@@ -1399,18 +1751,14 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 		if result != nil {
 			pluginResults := result.([]model.SearchResult)
 			// 只添加有链接的结果到最终结果中
-			for _, pluginResult := range pluginResults {
-				if len(pluginResult.Links) > 0 {
-					allResults = append(allResults, pluginResult)
-				}
-			}
+			allResults = append(allResults, filterResultsWithLinks(pluginResults)...)
 		}
 	}
 
 	// 恢复主程序缓存更新：确保最终合并结果被正确缓存
-	if cacheInitialized && config.AppConfig.CacheEnabled {
+	if cacheInitialized && config.Get().CacheEnabled {
 		go func(res []model.SearchResult, kw string, key string) {
-			ttl := time.Duration(config.AppConfig.CacheTTLMinutes) * time.Minute
+			ttl := time.Duration(config.Get().CacheTTLMinutes) * time.Minute
 
 			// 使用增强版缓存，确保与异步插件使用相同的序列化器
 			if enhancedTwoLevelCache != nil {
@@ -1423,7 +1771,7 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 				// 主程序最后更新，覆盖可能有问题的异步插件缓存
 				// 使用同步方式确保数据写入磁盘
 				enhancedTwoLevelCache.SetBothLevels(key, data, ttl)
-				if config.AppConfig != nil && config.AppConfig.AsyncLogEnabled {
+				if config.Get() != nil && config.Get().AsyncLogEnabled {
 					fmt.Printf("[主程序] 缓存更新完成: %s | 结果数: %d",
 						key, len(res))
 				}
@@ -1434,6 +1782,88 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 	return allResults, nil
 }
 
+// 插件搜索函数的执行状态
+const (
+	searchStatePending int32 = iota // 搜索函数尚未返回
+	searchStatePartial              // 搜索函数返回了非最终结果（插件内部响应超时）
+	searchStateFinal                // 搜索函数返回了最终结果
+)
+
+// searchWithResultPlugin 支持返回IsFinal标记的插件
+type searchWithResultPlugin interface {
+	SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error)
+}
+
+// resolvePlugins 根据请求的插件列表获取本次搜索实际使用的插件
+func (s *SearchService) resolvePlugins(plugins []string) []plugin.AsyncSearchPlugin {
+	var availablePlugins []plugin.AsyncSearchPlugin
+	if s.pluginManager == nil {
+		return availablePlugins
+	}
+
+	allPlugins := s.pluginManager.GetPlugins()
+
+	// 只有当plugins数组包含非空元素时才进行过滤
+	pluginMap := make(map[string]bool)
+	for _, p := range plugins {
+		if p != "" { // 忽略空字符串
+			pluginMap[strings.ToLower(p)] = true
+		}
+	}
+
+	if len(pluginMap) == 0 {
+		// 如果plugins为nil、空数组或只包含空字符串，视为未指定，使用所有插件
+		return allPlugins
+	}
+
+	for _, p := range allPlugins {
+		if pluginMap[strings.ToLower(p.Name())] {
+			availablePlugins = append(availablePlugins, p)
+		}
+	}
+	return availablePlugins
+}
+
+// filterResultsWithLinks 只保留包含链接的结果
+func filterResultsWithLinks(results []model.SearchResult) []model.SearchResult {
+	filtered := make([]model.SearchResult, 0, len(results))
+	for _, result := range results {
+		if len(result.Links) > 0 {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// reportCachedSources 命中整体缓存时，按数据源拆分缓存结果并逐一回调
+func reportCachedSources(onSource SourceCallback, sources []string, results []model.SearchResult) {
+	grouped := make(map[string][]model.SearchResult, len(sources))
+	for _, result := range results {
+		source := ResultSource(result)
+		grouped[source] = append(grouped[source], result)
+	}
+
+	for _, source := range sources {
+		onSource(SourceReport{
+			Source:  source,
+			Status:  model.SourceStatusCacheHit,
+			Results: grouped[source],
+		})
+	}
+}
+
+// sourceStatusForError 根据数据源返回的错误判断诊断状态
+func sourceStatusForError(err error) string {
+	switch {
+	case err == nil:
+		return model.SourceStatusOK
+	case errors.Is(err, ErrSourceTimeout), errors.Is(err, context.DeadlineExceeded):
+		return model.SourceStatusTimeout
+	default:
+		return model.SourceStatusError
+	}
+}
+
 // GetPluginManager 获取插件管理器
 func (s *SearchService) GetPluginManager() *plugin.PluginManager {
 	return s.pluginManager
@@ -1449,6 +1879,7 @@ type ResultScore struct {
 	TimeScore    float64 // 时间得分
 	KeywordScore int     // 关键词得分
 	PluginScore  int     // 插件等级得分
+	LinkScore    int     // 链接检测状态得分
 	TotalScore   float64 // 综合得分
 }
 
@@ -1457,8 +1888,16 @@ var (
 	pluginLevelCache = sync.Map{} // 插件等级缓存
 )
 
-// getResultSource 从SearchResult推断数据来源
-func getResultSource(result model.SearchResult) string {
+// resetPluginLevelCache 清空插件等级缓存，插件优先级被修改后调用
+func resetPluginLevelCache() {
+	pluginLevelCache.Range(func(key, _ interface{}) bool {
+		pluginLevelCache.Delete(key)
+		return true
+	})
+}
+
+// ResultSource 从SearchResult推断数据来源
+func ResultSource(result model.SearchResult) string {
 	if result.Channel != "" {
 		// 来自TG频道
 		return "tg:" + result.Channel
@@ -1502,17 +1941,25 @@ func getPluginLevelBySource(source string) int {
 
 // getPluginPriorityByName 根据插件名获取优先级
 func getPluginPriorityByName(pluginName string) int {
-	// 从插件管理器动态获取真实的优先级 (O(1)哈希查找)
+	// 从插件管理器动态获取真实的优先级 (O(1)哈希查找)，运行时覆盖优先
 	if pluginInstance, exists := plugin.GetPluginByName(pluginName); exists {
-		return pluginInstance.Priority()
+		return plugin.EffectivePriority(pluginInstance)
 	}
 	return 3 // 默认等级
 }
 
 // getPluginLevelScore 获取插件等级得分
+// 插件的正得分按健康分折算，避免不稳定的高等级插件占据结果前列
 func getPluginLevelScore(source string) int {
-	level := getPluginLevelBySource(source)
+	score := levelScore(getPluginLevelBySource(source))
+	if score > 0 && strings.HasPrefix(source, "plugin:") {
+		score = int(float64(score) * plugin.PluginHealthScore(strings.TrimPrefix(source, "plugin:")))
+	}
+	return score
+}
 
+// levelScore 插件等级对应的得分
+func levelScore(level int) int {
 	switch level {
 	case 1:
 		return 1000 // 等级1插件：1000分
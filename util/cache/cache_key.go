@@ -8,7 +8,16 @@ import (
 	"strings"
 	"sync"
 	
+	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/normalize"
+)
+
+// 搜索缓存键的作用域
+const (
+	SearchCacheScopeTG       = "tg"       // TG频道搜索结果
+	SearchCacheScopePlugin   = "plugin"   // 插件搜索结果
+	SearchCacheScopeSnapshot = "snapshot" // 合并后的完整结果快照，用于分页
 )
 
 // 预计算的哈希值映射
@@ -46,10 +55,23 @@ func init() {
 	precomputedHashes.Store("all_channels", allChannelsHash)
 }
 
+// UpdateAllPluginsHash 根据当前启用的插件更新"所有插件"的哈希值
+// 未指定插件的搜索使用该哈希生成缓存键，启用的插件变化后需要调用以避免命中旧的缓存
+func UpdateAllPluginsHash(enabledPlugins []string) {
+	names := make([]string, 0, len(enabledPlugins))
+	for _, name := range enabledPlugins {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	precomputedHashes.Store("all_plugins", calculateListHash(names))
+}
+
 // GenerateTGCacheKey 为TG搜索生成缓存键
 func GenerateTGCacheKey(keyword string, channels []string) string {
 	// 关键词标准化
-	normalizedKeyword := strings.ToLower(strings.TrimSpace(keyword))
+	normalizedKeyword := normalize.Key(keyword)
 	
 	// 获取频道列表哈希
 	channelsHash := getChannelsHash(channels)
@@ -60,10 +82,20 @@ func GenerateTGCacheKey(keyword string, channels []string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// GenerateTGPageCacheKey 为TG频道单页搜索结果生成缓存键，pageParam为空表示第一页
+func GenerateTGPageCacheKey(channel string, keyword string, pageParam string) string {
+	// 关键词标准化
+	normalizedKeyword := normalize.Key(keyword)
+
+	keyStr := fmt.Sprintf("tg_page:%s:%s:%s", channel, normalizedKeyword, pageParam)
+	hash := md5.Sum([]byte(keyStr))
+	return hex.EncodeToString(hash[:])
+}
+
 // GeneratePluginCacheKey 为插件搜索生成缓存键
 func GeneratePluginCacheKey(keyword string, plugins []string) string {
 	// 关键词标准化
-	normalizedKeyword := strings.ToLower(strings.TrimSpace(keyword))
+	normalizedKeyword := normalize.Key(keyword)
 	
 	// 获取插件列表哈希
 	pluginsHash := getPluginsHash(plugins)
@@ -74,10 +106,43 @@ func GeneratePluginCacheKey(keyword string, plugins []string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// GenerateSearchCacheKey 根据规范化后的搜索请求生成缓存键
+// 包含关键词、频道或插件列表、网盘类型，插件和快照作用域还包含请求插件声明的ext参数
+func GenerateSearchCacheKey(req model.SearchRequest, scope string) string {
+	// 关键词标准化
+	normalizedKeyword := normalize.Key(req.Keyword)
+	
+	var sourceHash, extSignature string
+	switch scope {
+	case SearchCacheScopeTG:
+		// TG搜索不使用ext参数
+		sourceHash = getChannelsHash(req.Channels)
+	case SearchCacheScopePlugin:
+		sourceHash = getPluginsHash(req.Plugins)
+		extSignature = plugin.ExtCacheSignature(req.Ext, getPluginsExtKeys(req.Plugins))
+	default:
+		// 快照同时包含TG和插件结果
+		sourceType := req.SourceType
+		if sourceType == "" {
+			sourceType = "all"
+		}
+		sourceHash = fmt.Sprintf("%s|%s|%s", sourceType, getChannelsHash(req.Channels), getPluginsHash(req.Plugins))
+		extSignature = plugin.ExtCacheSignature(req.Ext, getPluginsExtKeys(req.Plugins))
+		// 检测链接有效性的快照会移除失效链接，与未检测的快照分开缓存
+		if req.CheckLinks() {
+			sourceHash += "|check"
+		}
+	}
+	
+	keyStr := fmt.Sprintf("%s:%s:%s:%s:%s", scope, normalizedKeyword, sourceHash, getCloudTypesHash(req.CloudTypes), extSignature)
+	hash := md5.Sum([]byte(keyStr))
+	return hex.EncodeToString(hash[:])
+}
+
 // GenerateCacheKey 根据所有影响搜索结果的参数生成缓存键
 func GenerateCacheKey(keyword string, channels []string, sourceType string, plugins []string) string {
 	// 关键词标准化
-	normalizedKeyword := strings.ToLower(strings.TrimSpace(keyword))
+	normalizedKeyword := normalize.Key(keyword)
 	
 	// 获取频道列表哈希
 	channelsHash := getChannelsHash(channels)
@@ -207,6 +272,43 @@ func getPluginsHash(plugins []string) string {
 	return hash
 }
 
+// 获取网盘类型列表的规范化表示
+func getCloudTypesHash(cloudTypes []string) string {
+	normalized := make([]string, 0, len(cloudTypes))
+	seen := make(map[string]bool, len(cloudTypes))
+	for _, t := range cloudTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			normalized = append(normalized, t)
+		}
+	}
+	if len(normalized) == 0 {
+		return "all"
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, ",")
+}
+
+// 获取请求插件声明的ext参数的并集，插件列表为空时使用所有已注册插件
+func getPluginsExtKeys(plugins []string) []string {
+	requested := make(map[string]bool, len(plugins))
+	for _, p := range plugins {
+		if p != "" {
+			requested[strings.ToLower(p)] = true
+		}
+	}
+	
+	var keys []string
+	for _, p := range plugin.GetRegisteredPlugins() {
+		if len(requested) > 0 && !requested[strings.ToLower(p.Name())] {
+			continue
+		}
+		keys = append(keys, plugin.CacheExtKeysOf(p)...)
+	}
+	return keys
+}
+
 // 计算列表的哈希值
 func calculateListHash(items []string) string {
 	h := md5.New()
@@ -219,8 +321,8 @@ func calculateListHash(items []string) string {
 // GenerateCacheKeyV2 根据所有影响搜索结果的参数生成缓存键
 // 为保持向后兼容，保留原函数，但标记为已弃用
 func GenerateCacheKeyV2(keyword string, channels []string, sourceType string, plugins []string) string {
-	// 关键词标准化：去除首尾空格，转为小写
-	normalizedKeyword := strings.ToLower(strings.TrimSpace(keyword))
+	// 关键词标准化：繁简、全半角和大小写折叠，合并空白
+	normalizedKeyword := normalize.Key(keyword)
 	
 	// 频道处理
 	var channelsStr string
@@ -20,13 +20,13 @@ type EnhancedTwoLevelCache struct {
 func NewEnhancedTwoLevelCache() (*EnhancedTwoLevelCache, error) {
 	// 内存缓存大小为磁盘缓存的60%
 	memCacheMaxItems := 5000
-	memCacheSizeMB := config.AppConfig.CacheMaxSizeMB * 3 / 5
+	memCacheSizeMB := config.Get().CacheMaxSizeMB * 3 / 5
 	
 	memCache := NewShardedMemoryCache(memCacheMaxItems, memCacheSizeMB)
 	memCache.StartCleanupTask()
 
 	// 创建优化的分片磁盘缓存，使用动态分片数量
-	diskCache, err := NewOptimizedShardedDiskCache(config.AppConfig.CachePath, config.AppConfig.CacheMaxSizeMB)
+	diskCache, err := NewOptimizedShardedDiskCache(config.Get().CachePath, config.Get().CacheMaxSizeMB)
 	if err != nil {
 		return nil, err
 	}
@@ -97,6 +97,7 @@ func (c *EnhancedTwoLevelCache) Get(key string) ([]byte, bool, error) {
 	// 检查内存缓存
 	data, _, memHit := c.memory.GetWithTimestamp(key)
 	if memHit {
+		cacheLookups.Inc("memory")
 		return data, true, nil
 	}
 
@@ -105,11 +106,13 @@ func (c *EnhancedTwoLevelCache) Get(key string) ([]byte, bool, error) {
 	if diskErr == nil && diskHit {
 		// 磁盘缓存命中，更新内存缓存
 		diskLastModified, _ := c.disk.GetLastModified(key)
-		ttl := time.Duration(config.AppConfig.CacheTTLMinutes) * time.Minute
+		ttl := time.Duration(config.Get().CacheTTLMinutes) * time.Minute
 		c.memory.SetWithTimestamp(key, diskData, ttl, diskLastModified)
+		cacheLookups.Inc("disk")
 		return diskData, true, nil
 	}
 	
+	cacheLookups.Inc("miss")
 	return nil, false, nil
 }
 
@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"pansou/util/metrics"
+)
+
+// 两级缓存查询结果：memory（内存命中）、disk（磁盘命中）、miss（未命中）
+var cacheLookups = metrics.NewCounterVec("pansou_cache_lookups_total", "两级缓存查询次数，按命中层级统计", "result")
+
+func init() {
+	metrics.NewGaugeVecFunc("pansou_cache_hit_ratio", "两级缓存命中率，按命中层级统计", []string{"level"}, func() []metrics.Sample {
+		memory := cacheLookups.Value("memory")
+		disk := cacheLookups.Value("disk")
+		total := memory + disk + cacheLookups.Value("miss")
+		if total == 0 {
+			return []metrics.Sample{
+				{LabelValues: []string{"memory"}, Value: 0},
+				{LabelValues: []string{"disk"}, Value: 0},
+			}
+		}
+		return []metrics.Sample{
+			{LabelValues: []string{"memory"}, Value: memory / total},
+			{LabelValues: []string{"disk"}, Value: disk / total},
+		}
+	})
+}
@@ -35,7 +35,7 @@ func (g *gzipResponseWriter) Close() {
 func GzipMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 如果未启用压缩，直接跳过
-		if !config.AppConfig.EnableCompression {
+		if !config.Get().EnableCompression {
 			c.Next()
 			return
 		}
@@ -46,6 +46,12 @@ func GzipMiddleware() gin.HandlerFunc {
 			return
 		}
 		
+		// 流式响应（SSE）需要逐条推送，不能缓冲后整体压缩
+		if strings.Contains(c.Request.Header.Get("Accept"), "text/event-stream") || strings.HasSuffix(c.Request.URL.Path, "/stream") {
+			c.Next()
+			return
+		}
+		
 		// 创建一个缓冲响应写入器
 		buffer := &bytes.Buffer{}
 		blw := &bodyLogWriter{body: buffer, ResponseWriter: c.Writer}
@@ -58,7 +64,7 @@ func GzipMiddleware() gin.HandlerFunc {
 		responseData := buffer.Bytes()
 		
 		// 如果响应大小小于最小压缩大小，直接返回原始内容
-		if len(responseData) < config.AppConfig.MinSizeToCompress {
+		if len(responseData) < config.Get().MinSizeToCompress {
 			c.Writer.Write(responseData)
 			return
 		}
@@ -45,8 +45,8 @@ func InitHTTPClient() {
 	}
 
 	// 如果配置了代理，设置代理
-	if config.AppConfig.UseProxy {
-		proxyURL, err := url.Parse(config.AppConfig.ProxyURL)
+	if config.Get().UseProxy {
+		proxyURL, err := url.Parse(config.Get().ProxyURL)
 		if err == nil {
 			// 根据代理类型设置不同的处理方式
 			if proxyURL.Scheme == "socks5" {
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -10,11 +12,12 @@ import (
 // Claims JWT载荷结构
 type Claims struct {
 	Username string `json:"username"`
+	Role     string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成JWT token
-func GenerateToken(username string, secret string, expiry time.Duration) (string, error) {
+// GenerateToken 生成JWT token，每个token带有唯一的jti，用于注销时吊销
+func GenerateToken(username string, role string, secret string, expiry time.Duration) (string, error) {
 	if username == "" {
 		return "", errors.New("username cannot be empty")
 	}
@@ -22,10 +25,17 @@ func GenerateToken(username string, secret string, expiry time.Duration) (string
 		return "", errors.New("secret cannot be empty")
 	}
 
+	tokenID, err := NewTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	expirationTime := time.Now().Add(expiry)
 	claims := &Claims{
 		Username: username,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "pansou",
@@ -65,3 +75,12 @@ func ValidateToken(tokenString string, secret string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// NewTokenID 生成随机的令牌ID
+func NewTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
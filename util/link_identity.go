@@ -0,0 +1,172 @@
+package util
+
+import (
+	netUrl "net/url"
+	"regexp"
+	"strings"
+)
+
+// 分享ID中允许的字符，遇到其他字符（标点、中文说明等）即视为ID结束
+var shareIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+`)
+
+// 磁力链接和ed2k链接的文件哈希
+var (
+	magnetHashPattern = regexp.MustCompile(`(?i)xt=urn:btih:([a-z0-9]+)`)
+	ed2kHashPattern   = regexp.MustCompile(`(?i)ed2k://\|file\|[^|]*\|\d+\|([a-f0-9]{32})\|`)
+)
+
+// ShareIdentity 分享链接的规范标识
+// 同一分享的不同写法（镜像域名、提取码参数、片段、跟踪参数等）得到相同的Type和ShareID
+type ShareIdentity struct {
+	Type     string // 网盘类型，与GetLinkType的返回值一致
+	ShareID  string // 分享ID，磁力和ed2k链接为文件哈希
+	Password string // 链接中携带的提取码，没有时为空
+}
+
+// Key 用于去重的标识键
+func (id ShareIdentity) Key() string {
+	return id.Type + ":" + id.ShareID
+}
+
+// CanonicalShareIdentity 解析分享链接的规范标识，无法识别分享ID时返回false
+func CanonicalShareIdentity(rawURL string) (ShareIdentity, bool) {
+	rawURL = strings.TrimSpace(rawURL)
+	linkType := GetLinkType(rawURL)
+
+	switch linkType {
+	case "magnet":
+		if match := magnetHashPattern.FindStringSubmatch(rawURL); match != nil {
+			return ShareIdentity{Type: linkType, ShareID: strings.ToLower(match[1])}, true
+		}
+		return ShareIdentity{}, false
+	case "ed2k":
+		if match := ed2kHashPattern.FindStringSubmatch(rawURL); match != nil {
+			return ShareIdentity{Type: linkType, ShareID: strings.ToLower(match[1])}, true
+		}
+		return ShareIdentity{}, false
+	case "others":
+		return ShareIdentity{}, false
+	}
+
+	parsed, err := netUrl.Parse(cleanShareURL(linkType, rawURL))
+	if err != nil {
+		return ShareIdentity{}, false
+	}
+
+	shareID := extractShareID(linkType, parsed)
+	if shareID == "" {
+		return ShareIdentity{}, false
+	}
+
+	// 部分Clean*PanURL会去掉提取码参数，此时从原始链接中提取
+	password := extractURLPassword(parsed)
+	if password == "" {
+		if rawParsed, err := netUrl.Parse(rawURL); err == nil {
+			password = extractURLPassword(rawParsed)
+		}
+	}
+
+	return ShareIdentity{
+		Type:     linkType,
+		ShareID:  shareID,
+		Password: password,
+	}, true
+}
+
+// CanonicalLinkKey 返回用于去重的链接键，无法识别分享ID的链接使用原始URL
+func CanonicalLinkKey(rawURL string) string {
+	if identity, ok := CanonicalShareIdentity(rawURL); ok {
+		return identity.Key()
+	}
+	return strings.TrimSpace(rawURL)
+}
+
+// cleanShareURL 使用各网盘的Clean*PanURL去掉链接前后的无关文本
+// CleanUCPanURL会在"123"等网盘名称处截断链接，分享ID中含有这些字符时会被截短，因此UC链接不经过清理
+func cleanShareURL(linkType string, rawURL string) string {
+	switch linkType {
+	case "baidu":
+		return CleanBaiduPanURL(rawURL)
+	case "tianyi":
+		return CleanTianyiPanURL(rawURL)
+	case "123":
+		return Clean123PanURL(rawURL)
+	case "115":
+		return Clean115PanURL(rawURL)
+	case "aliyun":
+		return CleanAliyunPanURL(rawURL)
+	case "mobile":
+		return CleanMobilePanURL(rawURL)
+	}
+	return rawURL
+}
+
+// extractShareID 从已解析的分享链接中提取分享ID
+func extractShareID(linkType string, parsed *netUrl.URL) string {
+	path := parsed.Path
+
+	switch linkType {
+	case "baidu":
+		// pan.baidu.com/share/init?surl=xxx 与 pan.baidu.com/s/1xxx 为同一分享
+		if surl := parsed.Query().Get("surl"); surl != "" {
+			return matchShareID("1" + surl)
+		}
+	case "tianyi":
+		// cloud.189.cn/web/share?code=xxx 与 cloud.189.cn/t/xxx 为同一分享
+		if code := parsed.Query().Get("code"); code != "" {
+			return matchShareID(code)
+		}
+		return shareIDAfter(path, "/t/")
+	case "mobile":
+		// yun.139.com/shareweb/#/w/i/xxx 的分享ID在片段中
+		if id := shareIDAfter(parsed.Fragment, "/w/i/"); id != "" {
+			return id
+		}
+		if id := shareIDAfter(path, "/w/i/"); id != "" {
+			return id
+		}
+		// caiyun.139.com/m/i?xxx
+		if strings.HasSuffix(path, "/m/i") {
+			return matchShareID(parsed.RawQuery)
+		}
+		// caiyun.feixin.10086.cn/xxx
+		return matchShareID(strings.TrimPrefix(path, "/"))
+	}
+
+	return shareIDAfter(path, "/s/")
+}
+
+// shareIDAfter 提取路径中marker之后的分享ID
+func shareIDAfter(path string, marker string) string {
+	idx := strings.Index(path, marker)
+	if idx < 0 {
+		return ""
+	}
+	return matchShareID(path[idx+len(marker):])
+}
+
+func matchShareID(s string) string {
+	return shareIDPattern.FindString(s)
+}
+
+// extractURLPassword 提取链接查询参数中的提取码（pwd、password、提取码）
+func extractURLPassword(parsed *netUrl.URL) string {
+	query := parsed.Query()
+	for _, name := range []string{"pwd", "password", "提取码"} {
+		if password := strings.TrimSpace(query.Get(name)); password != "" {
+			return password
+		}
+	}
+
+	// 123网盘的提取码以“?提取码:xxxx”的形式附在链接后
+	rawQuery, err := netUrl.QueryUnescape(parsed.RawQuery)
+	if err != nil {
+		rawQuery = parsed.RawQuery
+	}
+	for _, prefix := range []string{"提取码:", "提取码："} {
+		if strings.HasPrefix(rawQuery, prefix) {
+			return matchShareID(strings.TrimPrefix(rawQuery, prefix))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package util
+
+import (
+	netUrl "net/url"
+	"testing"
+)
+
+func TestCanonicalShareIdentity(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want ShareIdentity
+	}{
+		// 百度
+		{"baidu", "https://pan.baidu.com/s/1AbC-d_9?pwd=x1y2", ShareIdentity{"baidu", "1AbC-d_9", "x1y2"}},
+		{"baidu surl", "https://pan.baidu.com/share/init?surl=AbC-d_9", ShareIdentity{"baidu", "1AbC-d_9", ""}},
+		{"baidu trailing text", "https://pan.baidu.com/s/1AbC-d_9，提取码见简介", ShareIdentity{"baidu", "1AbC-d_9", ""}},
+		// 夸克
+		{"quark", "https://pan.quark.cn/s/abc123def#/list/share", ShareIdentity{"quark", "abc123def", ""}},
+		{"quark pwd", "https://pan.quark.cn/s/abc123def?pwd=8888", ShareIdentity{"quark", "abc123def", "8888"}},
+		// 阿里云盘的两个域名
+		{"aliyun", "https://www.aliyundrive.com/s/AbCdEf123", ShareIdentity{"aliyun", "AbCdEf123", ""}},
+		{"alipan", "https://www.alipan.com/s/AbCdEf123", ShareIdentity{"aliyun", "AbCdEf123", ""}},
+		// 天翼
+		{"tianyi", "https://cloud.189.cn/t/AbCdEf12（访问码：x1y2）", ShareIdentity{"tianyi", "AbCdEf12", ""}},
+		{"tianyi code", "https://cloud.189.cn/web/share?code=AbCdEf12", ShareIdentity{"tianyi", "AbCdEf12", ""}},
+		// UC
+		{"uc", "https://drive.uc.cn/s/abc123def?public=1", ShareIdentity{"uc", "abc123def", ""}},
+		{"uc trailing text", "https://drive.uc.cn/s/abc123def，夸克备用", ShareIdentity{"uc", "abc123def", ""}},
+		// 移动云盘
+		{"mobile fragment", "https://yun.139.com/shareweb/#/w/i/0a5CJ0Abc", ShareIdentity{"mobile", "0a5CJ0Abc", ""}},
+		{"mobile m/i", "https://caiyun.139.com/m/i?0a5CJ0Abc", ShareIdentity{"mobile", "0a5CJ0Abc", ""}},
+		{"mobile feixin", "https://caiyun.feixin.10086.cn/0a5CJ0Abc", ShareIdentity{"mobile", "0a5CJ0Abc", ""}},
+		// 115
+		{"115", "https://115.com/s/sw1abcd?password=x1y2", ShareIdentity{"115", "sw1abcd", "x1y2"}},
+		{"115cdn", "https://115cdn.com/s/sw1abcd?password=x1y2#", ShareIdentity{"115", "sw1abcd", "x1y2"}},
+		// 123网盘的多个域名和提取码写法
+		{"123", "https://www.123pan.com/s/abc-123", ShareIdentity{"123", "abc-123", ""}},
+		{"123 mirror", "https://www.123684.com/s/abc-123?提取码:x1y2", ShareIdentity{"123", "abc-123", "x1y2"}},
+		{"123 escaped", "https://www.123912.com/s/abc-123?%E6%8F%90%E5%8F%96%E7%A0%81:x1y2", ShareIdentity{"123", "abc-123", "x1y2"}},
+		// 迅雷、PikPak、光鸭
+		{"xunlei", "https://pan.xunlei.com/s/VOabc123?pwd=x1y2#", ShareIdentity{"xunlei", "VOabc123", "x1y2"}},
+		{"pikpak", "https://mypikpak.com/s/VOabc123", ShareIdentity{"pikpak", "VOabc123", ""}},
+		{"guangya", "https://www.guangyapan.com/s/abc123", ShareIdentity{"guangya", "abc123", ""}},
+		// 磁力和ed2k使用文件哈希
+		{"magnet", "magnet:?xt=urn:btih:ABCDEF0123456789ABCDEF0123456789ABCDEF01&dn=test", ShareIdentity{"magnet", "abcdef0123456789abcdef0123456789abcdef01", ""}},
+		{"ed2k", "ed2k://|file|test.mkv|1024|0123456789ABCDEF0123456789ABCDEF|/", ShareIdentity{"ed2k", "0123456789abcdef0123456789abcdef", ""}},
+		// 前后空白
+		{"whitespace", "  https://pan.quark.cn/s/abc123def \n", ShareIdentity{"quark", "abc123def", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CanonicalShareIdentity(tt.url)
+			if !ok {
+				t.Fatalf("CanonicalShareIdentity(%q) ok = false", tt.url)
+			}
+			if got != tt.want {
+				t.Errorf("CanonicalShareIdentity(%q) = %+v, want %+v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalShareIdentityUnrecognized(t *testing.T) {
+	for _, url := range []string{
+		"",
+		"https://example.com/s/abc123",
+		"https://pan.quark.cn/list",
+		"https://pan.baidu.com/s/",
+		"https://cloud.189.cn/web/main",
+		"magnet:?dn=no-hash",
+		"ed2k://|server|1.2.3.4|4661|/",
+	} {
+		if got, ok := CanonicalShareIdentity(url); ok {
+			t.Errorf("CanonicalShareIdentity(%q) = %+v, want not ok", url, got)
+		}
+	}
+}
+
+// 同一分享的不同写法得到相同的去重键
+func TestCanonicalLinkKey(t *testing.T) {
+	groups := [][]string{
+		{"https://pan.baidu.com/s/1AbC-d_9?pwd=x1y2", "https://pan.baidu.com/share/init?surl=AbC-d_9", "链接：https://pan.baidu.com/s/1AbC-d_9 提取码：x1y2"},
+		{"https://www.aliyundrive.com/s/AbCdEf123", "https://www.alipan.com/s/AbCdEf123/folder/xyz"},
+		{"https://cloud.189.cn/t/AbCdEf12", "https://cloud.189.cn/web/share?code=AbCdEf12"},
+		{"https://www.123pan.com/s/abc-123", "https://www.123865.com/s/abc-123?提取码:x1y2"},
+		{"magnet:?xt=urn:btih:ABCDEF0123456789ABCDEF0123456789ABCDEF01", "magnet:?xt=urn:btih:abcdef0123456789abcdef0123456789abcdef01&tr=udp://tracker"},
+	}
+	for _, group := range groups {
+		want := CanonicalLinkKey(group[0])
+		for _, url := range group[1:] {
+			if got := CanonicalLinkKey(url); got != want {
+				t.Errorf("CanonicalLinkKey(%q) = %q, want %q", url, got, want)
+			}
+		}
+	}
+
+	// 无法识别时使用去掉空白的原始链接
+	if got := CanonicalLinkKey(" https://example.com/a "); got != "https://example.com/a" {
+		t.Errorf("CanonicalLinkKey() = %q", got)
+	}
+	if CanonicalLinkKey("https://pan.quark.cn/s/abc") == CanonicalLinkKey("https://drive.uc.cn/s/abc") {
+		t.Error("不同网盘的相同分享ID不应视为同一链接")
+	}
+}
+
+func TestExtractShareID(t *testing.T) {
+	tests := []struct {
+		linkType string
+		url      string
+		want     string
+	}{
+		{"quark", "https://pan.quark.cn/s/abc123", "abc123"},
+		{"quark", "https://pan.quark.cn/s/abc123/", "abc123"},
+		{"quark", "https://pan.quark.cn/other", ""},
+		{"baidu", "https://pan.baidu.com/share/init?surl=xyz", "1xyz"},
+		{"baidu", "https://pan.baidu.com/s/1xyz", "1xyz"},
+		{"tianyi", "https://cloud.189.cn/web/share?code=AbC", "AbC"},
+		{"tianyi", "https://cloud.189.cn/t/AbC", "AbC"},
+		{"tianyi", "https://cloud.189.cn/s/AbC", ""},
+		{"mobile", "https://yun.139.com/shareweb/#/w/i/0a5C", "0a5C"},
+		{"mobile", "https://yun.139.com/w/i/0a5C", "0a5C"},
+		{"mobile", "https://caiyun.139.com/m/i?0a5C", "0a5C"},
+		{"mobile", "https://caiyun.feixin.10086.cn/0a5C", "0a5C"},
+		{"aliyun", "https://www.alipan.com/s/AbC/folder/1", "AbC"},
+		{"115", "https://115.com/s/sw1?password=x", "sw1"},
+		{"123", "https://www.123pan.com/s/abc-1.html", "abc-1"},
+		{"xunlei", "https://pan.xunlei.com/s/VOabc", "VOabc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			parsed, err := netUrl.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.url, err)
+			}
+			if got := extractShareID(tt.linkType, parsed); got != tt.want {
+				t.Errorf("extractShareID(%s, %q) = %q, want %q", tt.linkType, tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuckets 默认的耗时直方图分桶（秒）
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 4, 8, 15, 30, 60}
+
+// Collector 可导出为Prometheus文本格式的指标
+type Collector interface {
+	// Name 指标名
+	Name() string
+	// Write 按Prometheus文本格式写出指标（包括HELP和TYPE行）
+	Write(w io.Writer)
+}
+
+// Registry 指标注册表
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]Collector
+}
+
+// NewRegistry 创建指标注册表
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// DefaultRegistry 默认注册表，New*函数创建的指标都注册在这里
+var DefaultRegistry = NewRegistry()
+
+// Register 注册指标，同名指标后注册的覆盖先注册的
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors[c.Name()] = c
+}
+
+// WriteText 按指标名排序写出所有指标
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.RLock()
+	collectors := make([]Collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].Name() < collectors[j].Name()
+	})
+
+	buf := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.Write(buf)
+	}
+	return buf.Flush()
+}
+
+// WriteText 写出默认注册表中的所有指标
+func WriteText(w io.Writer) error {
+	return DefaultRegistry.WriteText(w)
+}
+
+// ============================================================
+// 计数器
+// ============================================================
+
+// CounterVec 带标签的计数器
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+	values     sync.Map // 标签值组合 -> *uint64（float64的位表示）
+}
+
+// NewCounterVec 创建并注册带标签的计数器
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labelNames: labelNames}
+	DefaultRegistry.Register(c)
+	return c
+}
+
+// Name 指标名
+func (c *CounterVec) Name() string { return c.name }
+
+// Inc 计数加1
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add 计数增加v，v必须非负
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	key := joinLabelValues(labelValues)
+	actual, _ := c.values.LoadOrStore(key, new(uint64))
+	addFloat(actual.(*uint64), v)
+}
+
+// Value 获取指定标签组合的当前计数
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	if bits, ok := c.values.Load(joinLabelValues(labelValues)); ok {
+		return math.Float64frombits(atomic.LoadUint64(bits.(*uint64)))
+	}
+	return 0
+}
+
+// Write 写出计数器
+func (c *CounterVec) Write(w io.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	for _, key := range sortedKeys(&c.values) {
+		bits, _ := c.values.Load(key)
+		value := math.Float64frombits(atomic.LoadUint64(bits.(*uint64)))
+		writeSample(w, c.name, formatLabels(c.labelNames, splitLabelValues(key), "", ""), value)
+	}
+}
+
+// ============================================================
+// 采集时计算的指标
+// ============================================================
+
+// Sample 采集时计算的一个样本
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// FuncVec 在采集时调用函数计算样本的指标，适合导出已有的统计数据
+type FuncVec struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	collect    func() []Sample
+}
+
+// NewGaugeFunc 创建并注册无标签的仪表盘指标
+func NewGaugeFunc(name, help string, value func() float64) *FuncVec {
+	return NewGaugeVecFunc(name, help, nil, func() []Sample {
+		return []Sample{{Value: value()}}
+	})
+}
+
+// NewCounterFunc 创建并注册无标签的计数器指标，value必须单调递增
+func NewCounterFunc(name, help string, value func() float64) *FuncVec {
+	return NewCounterVecFunc(name, help, nil, func() []Sample {
+		return []Sample{{Value: value()}}
+	})
+}
+
+// NewGaugeVecFunc 创建并注册带标签的仪表盘指标
+func NewGaugeVecFunc(name, help string, labelNames []string, collect func() []Sample) *FuncVec {
+	f := &FuncVec{name: name, help: help, metricType: "gauge", labelNames: labelNames, collect: collect}
+	DefaultRegistry.Register(f)
+	return f
+}
+
+// NewCounterVecFunc 创建并注册带标签的计数器指标
+func NewCounterVecFunc(name, help string, labelNames []string, collect func() []Sample) *FuncVec {
+	f := &FuncVec{name: name, help: help, metricType: "counter", labelNames: labelNames, collect: collect}
+	DefaultRegistry.Register(f)
+	return f
+}
+
+// Name 指标名
+func (f *FuncVec) Name() string { return f.name }
+
+// Write 写出采集时计算的样本
+func (f *FuncVec) Write(w io.Writer) {
+	writeHeader(w, f.name, f.help, f.metricType)
+	for _, sample := range f.collect() {
+		writeSample(w, f.name, formatLabels(f.labelNames, sample.LabelValues, "", ""), sample.Value)
+	}
+}
+
+// ============================================================
+// 直方图
+// ============================================================
+
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64 // 每个分桶的计数（非累计）
+	sum    float64
+	count  uint64
+}
+
+// HistogramVec 带标签的直方图
+type HistogramVec struct {
+	name       string
+	help       string
+	labelNames []string
+	buckets    []float64
+	values     sync.Map // 标签值组合 -> *histogram
+}
+
+// NewHistogramVec 创建并注册带标签的直方图，buckets为空时使用DefaultBuckets
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &HistogramVec{name: name, help: help, labelNames: labelNames, buckets: sorted}
+	DefaultRegistry.Register(h)
+	return h
+}
+
+// Name 指标名
+func (h *HistogramVec) Name() string { return h.name }
+
+// Observe 记录一次观测值
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	key := joinLabelValues(labelValues)
+	actual, ok := h.values.Load(key)
+	if !ok {
+		actual, _ = h.values.LoadOrStore(key, &histogram{counts: make([]uint64, len(h.buckets))})
+	}
+	hist := actual.(*histogram)
+
+	hist.mu.Lock()
+	defer hist.mu.Unlock()
+	for i, upper := range h.buckets {
+		if v <= upper {
+			hist.counts[i]++
+			break
+		}
+	}
+	hist.sum += v
+	hist.count++
+}
+
+// Write 写出直方图的分桶、总和与计数
+func (h *HistogramVec) Write(w io.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	for _, key := range sortedKeys(&h.values) {
+		actual, _ := h.values.Load(key)
+		hist := actual.(*histogram)
+		labelValues := splitLabelValues(key)
+
+		hist.mu.Lock()
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += hist.counts[i]
+			writeSample(w, h.name+"_bucket", formatLabels(h.labelNames, labelValues, "le", formatFloat(upper)), float64(cumulative))
+		}
+		writeSample(w, h.name+"_bucket", formatLabels(h.labelNames, labelValues, "le", "+Inf"), float64(hist.count))
+		writeSample(w, h.name+"_sum", formatLabels(h.labelNames, labelValues, "", ""), hist.sum)
+		writeSample(w, h.name+"_count", formatLabels(h.labelNames, labelValues, "", ""), float64(hist.count))
+		hist.mu.Unlock()
+	}
+}
+
+// ============================================================
+// 文本格式辅助函数
+// ============================================================
+
+// 标签值组合的分隔符，不会出现在正常的标签值中
+const labelValueSeparator = "\xff"
+
+func joinLabelValues(values []string) string {
+	return strings.Join(values, labelValueSeparator)
+}
+
+func splitLabelValues(key string) []string {
+	if key == "" {
+		return nil
+	}
+	return strings.Split(key, labelValueSeparator)
+}
+
+func sortedKeys(m *sync.Map) []string {
+	keys := make([]string, 0)
+	m.Range(func(key, _ interface{}) bool {
+		keys = append(keys, key.(string))
+		return true
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+func addFloat(bits *uint64, v float64) {
+	for {
+		old := atomic.LoadUint64(bits)
+		updated := math.Float64bits(math.Float64frombits(old) + v)
+		if atomic.CompareAndSwapUint64(bits, old, updated) {
+			return
+		}
+	}
+}
+
+func writeHeader(w io.Writer, name, help, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+func writeSample(w io.Writer, name, labels string, value float64) {
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(value))
+}
+
+// formatLabels 格式化标签，extraName非空时追加一个额外标签（直方图的le）
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(value)))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+
+	requests := NewCounterVec("test_requests_total", "请求数\n按插件统计", "plugin", "status")
+	requests.Inc("labi", "ok")
+	requests.Add(2.5, "labi", "ok")
+	requests.Inc(`a"b\c`, "error")
+	requests.Add(-1, "labi", "ok") // 负数被忽略
+	r.Register(requests)
+
+	duration := NewHistogramVec("test_duration_seconds", "耗时", []float64{1, 0.5}, "plugin")
+	duration.Observe(0.25, "labi")
+	duration.Observe(0.75, "labi")
+	duration.Observe(3, "labi")
+	r.Register(duration)
+
+	gauge := NewGaugeFunc("test_queue_size", "队列长度", func() float64 { return 7 })
+	r.Register(gauge)
+
+	var sb strings.Builder
+	if err := r.WriteText(&sb); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+
+	want := `# HELP test_duration_seconds 耗时
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{plugin="labi",le="0.5"} 1
+test_duration_seconds_bucket{plugin="labi",le="1"} 2
+test_duration_seconds_bucket{plugin="labi",le="+Inf"} 3
+test_duration_seconds_sum{plugin="labi"} 4
+test_duration_seconds_count{plugin="labi"} 3
+# HELP test_queue_size 队列长度
+# TYPE test_queue_size gauge
+test_queue_size 7
+# HELP test_requests_total 请求数\n按插件统计
+# TYPE test_requests_total counter
+test_requests_total{plugin="a\"b\\c",status="error"} 1
+test_requests_total{plugin="labi",status="ok"} 3.5
+`
+	if got := sb.String(); got != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCounterVecValue(t *testing.T) {
+	c := NewCounterVec("test_value_total", "计数", "result")
+	c.Inc("ok")
+	c.Inc("ok")
+	if got := c.Value("ok"); got != 2 {
+		t.Errorf("Value(ok) = %v, want 2", got)
+	}
+	if got := c.Value("missing"); got != 0 {
+		t.Errorf("Value(missing) = %v, want 0", got)
+	}
+}
+
+func TestFuncVecLabels(t *testing.T) {
+	f := NewCounterVecFunc("test_writes_total", "写入次数", []string{"result"}, func() []Sample {
+		return []Sample{
+			{LabelValues: []string{"success"}, Value: 3},
+			{LabelValues: []string{"failed"}, Value: 0},
+		}
+	})
+
+	var sb strings.Builder
+	f.Write(&sb)
+	want := `# HELP test_writes_total 写入次数
+# TYPE test_writes_total counter
+test_writes_total{result="success"} 3
+test_writes_total{result="failed"} 0
+`
+	if got := sb.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package normalize
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ============================================================
+// 文本归一化：关键词匹配和缓存键使用同一套字符折叠规则
+//
+//   繁体 → 简体        進擊的巨人 → 进击的巨人
+//   全角 → 半角        ＡＢＣ１２３ → abc123
+//   中文序数 → 数字    第八季、第十二集 → 第8季、第12集
+//
+// Text额外去掉标点和空白，用于包含匹配；Key保留标点并合并空白，用于缓存键
+// ============================================================
+
+// 繁体字到简体字的映射
+var traditionalToSimplified = buildTraditionalMap()
+
+func buildTraditionalMap() map[rune]rune {
+	m := make(map[rune]rune, utf8.RuneCountInString(traditionalPairs)/2)
+	runes := []rune(traditionalPairs)
+	for i := 0; i+1 < len(runes); i += 2 {
+		m[runes[i]] = runes[i+1]
+	}
+	return m
+}
+
+// 中文序数后可以跟的单位，繁体已折叠为简体
+var ordinalUnits = map[rune]bool{
+	'季': true, '集': true, '期': true, '部': true, '话': true, '章': true,
+	'卷': true, '篇': true, '辑': true, '回': true, '册': true, '弹': true,
+}
+
+// 中文数字
+var chineseDigits = map[rune]int{
+	'零': 0, '〇': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+// 中文数字单位
+var chineseUnits = map[rune]int{
+	'十': 10, '百': 100, '千': 1000,
+}
+
+// Text 归一化用于包含匹配的文本：折叠繁简、全半角和大小写，去掉标点和空白，中文序数转为数字
+// 关键词和候选文本都经过Text处理后再用strings.Contains比较
+func Text(s string) string {
+	return fold(s, true)
+}
+
+// Key 归一化用于缓存键的关键词：折叠繁简、全半角和大小写，中文序数转为数字，
+// 保留标点（查询语法依赖引号、减号和冒号），首尾空白去掉、连续空白合并为一个空格
+func Key(s string) string {
+	return fold(s, false)
+}
+
+// fold 逐字折叠，stripPunct为true时去掉标点和空白
+func fold(s string, stripPunct bool) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	space := false
+	for _, r := range s {
+		r = foldRune(r)
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if stripPunct && unicode.IsPunct(r) {
+			continue
+		}
+		if space && !stripPunct && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return foldOrdinals(b.String())
+}
+
+// foldRune 折叠单个字符：全角转半角、繁体转简体、转小写
+func foldRune(r rune) rune {
+	switch {
+	case r == '　':
+		return ' '
+	case r >= '！' && r <= '～':
+		r -= 0xFEE0
+	}
+	if simplified, ok := traditionalToSimplified[r]; ok {
+		r = simplified
+	}
+	return unicode.ToLower(r)
+}
+
+// foldOrdinals 将"第...季/集"等序数中的中文数字转为阿拉伯数字，并去掉数字的前导零
+func foldOrdinals(s string) string {
+	if !strings.ContainsRune(s, '第') {
+		return s
+	}
+
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(runes); i++ {
+		b.WriteRune(runes[i])
+		if runes[i] != '第' {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isNumeral(runes[end]) {
+			end++
+		}
+		if end == i+1 || end >= len(runes) || !ordinalUnits[runes[end]] {
+			continue
+		}
+		if value, ok := parseNumeral(runes[i+1 : end]); ok {
+			b.WriteString(strconv.Itoa(value))
+			i = end - 1
+		}
+	}
+	return b.String()
+}
+
+func isNumeral(r rune) bool {
+	if r >= '0' && r <= '9' {
+		return true
+	}
+	_, isDigit := chineseDigits[r]
+	_, isUnit := chineseUnits[r]
+	return isDigit || isUnit
+}
+
+// parseNumeral 解析阿拉伯数字或中文数字（如十二、二十、一百零一、一二），不支持混写
+func parseNumeral(runes []rune) (int, bool) {
+	if runes[0] >= '0' && runes[0] <= '9' {
+		value, err := strconv.Atoi(string(runes))
+		return value, err == nil
+	}
+
+	total, digit := 0, -1
+	hasUnit := false
+	for _, r := range runes {
+		if d, ok := chineseDigits[r]; ok {
+			if digit >= 0 && !hasUnit {
+				// 没有单位的连续数字按位拼接，如"一二"表示12
+				digit = digit*10 + d
+			} else {
+				digit = d
+			}
+			continue
+		}
+		unit, ok := chineseUnits[r]
+		if !ok {
+			return 0, false
+		}
+		hasUnit = true
+		if digit < 0 {
+			// "十二"省略了开头的一
+			digit = 1
+		}
+		total += digit * unit
+		digit = -1
+	}
+	if digit > 0 {
+		total += digit
+	}
+	return total, true
+}
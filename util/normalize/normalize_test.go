@@ -0,0 +1,91 @@
+package normalize
+
+import "testing"
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"traditional", "進擊的巨人", "进击的巨人"},
+		{"simplified unchanged", "进击的巨人", "进击的巨人"},
+		{"full width", "ＡＢＣ１２３", "abc123"},
+		{"full width punct and space", "Ａ！　Ｂ", "ab"},
+		{"lower case", "Breaking BAD", "breakingbad"},
+		{"punct stripped", "【4K】庆余年·第二季", "4k庆余年第2季"},
+		{"ordinal", "第八季", "第8季"},
+		{"ordinal arabic", "第8季", "第8季"},
+		{"ordinal leading zero", "第08集", "第8集"},
+		{"ordinal ten", "第十集", "第10集"},
+		{"ordinal twelve", "第十二集", "第12集"},
+		{"ordinal twenty", "第二十集", "第20集"},
+		{"ordinal hundred and one", "第一百零一集", "第101集"},
+		{"ordinal digits", "第一二集", "第12集"},
+		{"ordinal traditional unit", "第三話", "第3话"},
+		{"ordinal without unit", "第八", "第八"},
+		{"ordinal unknown unit", "第八人", "第八人"},
+		{"ordinal mixed", "第1十集", "第1十集"},
+		{"multiple ordinals", "第二季第十集", "第2季第10集"},
+		{"empty", "", ""},
+		{"only punct", " -！。 ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.in); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"進擊的巨人", "进击的巨人"},
+		{"  速度与激情　　10 ", "速度与激情 10"},
+		{"ＡＢＣ", "abc"},
+		{`庆余年 第八季 -"枪版" type:quark`, `庆余年 第8季 -"枪版" type:quark`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Key(tt.in); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// 繁简、全半角和序数写法不同的关键词应归一化为同一个值
+func TestEquivalentForms(t *testing.T) {
+	pairs := [][2]string{
+		{"進擊的巨人", "进击的巨人"},
+		{"ＡＢＣ", "abc"},
+		{"第八季", "第8季"},
+		{"第一百零一集", "第101集"},
+		{"慶餘年 第二季", "庆余年 第2季"},
+	}
+	for _, p := range pairs {
+		if Text(p[0]) != Text(p[1]) {
+			t.Errorf("Text(%q) = %q, Text(%q) = %q", p[0], Text(p[0]), p[1], Text(p[1]))
+		}
+		if Key(p[0]) != Key(p[1]) {
+			t.Errorf("Key(%q) = %q, Key(%q) = %q", p[0], Key(p[0]), p[1], Key(p[1]))
+		}
+	}
+}
+
+func TestTraditionalPairs(t *testing.T) {
+	runes := []rune(traditionalPairs)
+	if len(runes)%2 != 0 {
+		t.Fatalf("traditionalPairs长度为奇数: %d", len(runes))
+	}
+	for i := 0; i < len(runes); i += 2 {
+		if runes[i] == runes[i+1] {
+			t.Errorf("映射 %c → %c 无效", runes[i], runes[i+1])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package normalize
+
+// traditionalPairs 常用繁体字（含部分异体字）到简体字的映射，每两个字为一组：繁体在前，简体在后
+// 只收录影视、书籍、软件标题中常见的字，归一化时不在表中的字保持不变
+const traditionalPairs = "" +
+	"進进擊击劇剧電电視视聯联鬥斗羅罗蘭兰戰战爭争國国時时間间愛爱與与為为們们個个來来說说説说話话語语" +
+	"體体學学開开關关門门問问聞闻長长東东車车馬马鳥鸟魚鱼龍龙風风雲云氣气書书畫画圖图團团園园場场異异" +
+	"聲声當当對对從从後后裡里裏里這这還还過过遠远運运遊游達达選选邊边醫医鐵铁錢钱銀银錄录鏡镜陽阳陰阴" +
+	"陸陆隊队際际險险隨随雙双難难離离靈灵頭头題题顏颜願愿類类顯显飛飞飯饭館馆驚惊驗验髮发發发鳳凤鴨鸭" +
+	"麗丽黃黄點点齊齐齒齿龜龟萬万華华葉叶蘇苏藍蓝藝艺蟲虫術术衛卫裝装見见規规親亲覺觉觀观計计記记許许" +
+	"設设詩诗誰谁課课調调請请讀读變变讓让貓猫貝贝負负財财貨货貴贵買买賣卖費费資资賊贼賽赛贏赢趙赵跡迹" +
+	"軍军輕轻輪轮轉转辦办農农鄉乡鄭郑醜丑釋释針针鋼钢錯错鍵键閃闪閱阅闆板隱隐雜杂雞鸡霧雾靜静韓韩頁页" +
+	"項项順顺須须預预領领頻频顧顾飄飘養养餘余駕驾騎骑騰腾驅驱鬆松鬧闹魯鲁鮮鲜鯊鲨鷹鹰鹽盐麥麦黨党齡龄" +
+	"亂乱亞亚僅仅價价優优兒儿兩两內内冊册凍冻別别則则剛刚創创劃划劍剑勁劲動动務务勝胜勞劳勢势區区協协" +
+	"單单卻却厲厉參参號号吳吴員员啟启啓启喪丧喬乔嗎吗嚴严囉啰圍围圓圆報报塊块塵尘墮堕壞坏壓压壯壮壽寿" +
+	"夢梦奪夺奮奋婦妇媽妈孫孙寧宁實实寫写寶宝將将專专尋寻導导屆届屍尸層层屬属島岛峽峡崗岗嶺岭巔巅幣币" +
+	"帥帅師师帳帐帶带幫帮廣广廳厅張张強强彈弹彎弯彥彦徑径復复徵征徹彻恆恒悅悦惡恶慣惯態态憂忧憶忆應应" +
+	"懷怀戀恋戲戏戶户拋抛掃扫掛挂採采換换揚扬損损搖摇撲扑擁拥擇择據据擬拟擴扩攝摄敗败敵敌數数斷断無无" +
+	"舊旧晉晋暫暂曆历歷历曉晓曬晒會会條条楊杨極极槍枪樂乐樓楼標标樣样橋桥機机權权歡欢歲岁歸归殺杀殼壳" +
+	"毀毁漢汉湯汤溫温滅灭滿满漁渔漲涨潛潜澤泽濕湿濟济濤涛灣湾災灾烏乌煙烟煩烦熱热燈灯營营燦灿爐炉爺爷" +
+	"牆墙獄狱獨独獲获獵猎獸兽獻献現现環环瑪玛產产畢毕療疗癡痴盜盗盡尽監监盤盘眾众衆众睜睁礎础碼码確确" +
+	"禮礼禍祸禦御種种稱称穩稳窮穷競竞筆笔節节範范築筑簡简籃篮糧粮紀纪約约紅红紋纹納纳純纯紙纸級级紛纷" +
+	"細细終终組组結结絕绝給给統统絲丝經经綁绑綠绿維维網网緊紧線线緣缘編编練练縣县總总績绩織织繩绳繼继" +
+	"續续罰罚罷罢義义習习聖圣聰聪聽听職职肅肃脅胁腦脑腳脚臉脸臨临興兴舉举艦舰艱艰莊庄萊莱著着蓋盖蔣蒋" +
+	"蕭萧薩萨藥药處处蝦虾蠻蛮補补製制複复襲袭覽览訂订訊讯討讨訓训託托訪访證证評评識识試试該该詳详誅诛" +
+	"認认誘诱誤误誠诚談谈諜谍謀谋謎谜講讲謝谢謊谎護护譯译議议豐丰豬猪貞贞責责貧贫貪贪貫贯賀贺賞赏賢贤" +
+	"質质賭赌購购贈赠贊赞讚赞趕赶蹤踪躍跃軌轨軟软載载輔辅輝辉輩辈輸输辭辞遲迟遷迁遺遗適适郵邮鄰邻醬酱" +
+	"釣钓鈴铃銳锐鋒锋鍋锅鎖锁鎮镇鏈链鐘钟鍾钟鑽钻閉闭閒闲閣阁闊阔闖闯陣阵陳陈階阶隸隶雖虽雛雏靂雳響响" +
+	"頂顶頓顿頒颁頗颇頸颈顆颗額额颱台臺台檯台颶飓飾饰餅饼餓饿騙骗驕骄驢驴骯肮鬍胡衚胡鯨鲸鱷鳄鳴鸣鴻鸿" +
+	"鵝鹅鶴鹤麼么黴霉鼴鼹齣出劉刘楓枫嬰婴夾夹奧奥姦奸孿孪尷尴廢废彌弥擔担擋挡擺摆攤摊灑洒燒烧爛烂犧牺" +
+	"狀状猶犹猻狲獅狮瘋疯癢痒皺皱矯矫碩硕礦矿禪禅積积穀谷竊窃糾纠紗纱絨绒綜综綱纲緒绪緝缉締缔縮缩繞绕" +
+	"繪绘繹绎纏缠罈坛壇坛罵骂羨羡翹翘聳耸膽胆膚肤艷艳豔艳芻刍蒼苍蘋苹蟬蝉蠍蝎衝冲裊袅褲裤覓觅訝讶詐诈" +
+	"詭诡諾诺諸诸謠谣譜谱貍狸賓宾贖赎軒轩輯辑轟轰辯辩遙遥遞递鄧邓釘钉鈔钞鉤钩銅铜鋪铺舖铺錦锦鏢镖闌阑" +
+	"隕陨雋隽靚靓韻韵頌颂頹颓颯飒餡馅饑饥饒饶馳驰駭骇驟骤髒脏臟脏鬱郁鯉鲤鴉鸦鵬鹏麵面齋斋乾干幹干於于" +
+	"隻只嘆叹噴喷嚇吓囂嚣壺壶獎奖奬奖妝妆嬌娇寵宠屜屉巖岩幟帜庫库廚厨廟庙彙汇匯汇徬彷憐怜憑凭憲宪懶懒" +
+	"懼惧掙挣揮挥搶抢摯挚撐撑撥拨撫抚擠挤攔拦攜携敘叙斂敛暈晕暢畅曠旷棄弃棟栋棧栈櫃柜檢检殘残沒没洶汹" +
+	"淚泪淺浅渦涡測测準准溝沟漸渐潔洁潤润澀涩濃浓濫滥瀏浏瀟潇瀨濑灘滩牽牵犢犊狹狭猙狰璽玺甦苏痺痹瘡疮" +
+	"盞盏睏困矚瞩硃朱禱祷稅税穌稣窩窝窯窑竄窜筍笋箏筝篩筛簽签籤签籠笼粵粤糞粪紐纽紮扎絡络綸纶緩缓縫缝" +
+	"繃绷繭茧缽钵罌罂羈羁翺翱脈脉脫脱腎肾腫肿膠胶艙舱蘆芦虛虚蟻蚁蠟蜡蠶蚕袞衮襯衬訴诉詞词誇夸誌志諷讽" +
+	"諧谐謙谦譏讥豎竖貳贰賴赖贍赡趨趋踐践蹌跄軀躯較较輛辆轄辖轎轿辮辫逕迳違违遜逊邁迈醞酝釀酿鈍钝鉛铅" +
+	"銘铭錘锤鍛锻鏟铲鑑鉴鑒鉴閘闸閩闽閻阎闡阐陝陕隴陇霽霁靦腼韋韦韌韧頰颊顫颤颳刮餚肴餵喂饅馒駐驻駛驶" +
+	"騷骚驛驿鬨哄鯽鲫鴿鸽鵑鹃鶯莺鸚鹦鹹咸麴曲黷黩齜龇龐庞傳传倫伦偵侦側侧偉伟傷伤傑杰備备僕仆億亿儀仪" +
+	"儉俭償偿儲储兇凶減减幾几凱凯劑剂勵励勸劝匱匮厭厌叢丛啞哑喚唤噸吨嚨咙嚮向堅坚執执塗涂墳坟墜坠夠够" +
+	"夥伙奐奂媧娲孃娘寢寝岡冈嵐岚巒峦幀帧廂厢廬庐弒弑彆别徠徕恥耻悶闷惱恼慘惨慶庆慮虑慾欲憤愤戔戋挾挟" +
+	"捨舍掄抡揀拣搗捣摟搂撈捞擄掳擾扰攏拢斃毙昇升晝昼暉晖曇昙朧胧桿杆梟枭椏桠榮荣構构槳桨樁桩橫横檸柠" +
+	"櫻樱欄栏欖榄歐欧殤殇殲歼氫氢氳氲決决況况涼凉淒凄淵渊渾浑湧涌滄沧滬沪滯滞滾滚漣涟漿浆潑泼潰溃澗涧" +
+	"濁浊濱滨瀉泻瀋沈瀾澜灕漓煉炼鍊炼煒炜燁烨燭烛爾尔牠它獃呆璣玑瓊琼甌瓯畝亩疊叠瘓痪癒愈癱瘫皚皑盧卢" +
+	"瞞瞒瞭了矇蒙礙碍祕秘禎祯稟禀穎颖窺窥篤笃簾帘籬篱紳绅紹绍絢绚綺绮綽绰緋绯緬缅緯纬縱纵繳缴羣群聶聂" +
+	"膩腻臍脐荊荆莖茎菸烟萵莴蔥葱蔭荫蕩荡薦荐薑姜蘊蕴蘿萝虜虏蛻蜕蝕蚀螢萤蠱蛊衊蔑褸褛襖袄覲觐訣诀註注" +
+	"詠咏諒谅諭谕謂谓謹谨譁哗譴谴讒谗豈岂貶贬賄贿賜赐賦赋贓赃贗赝跼局蹕跸躋跻軻轲輿舆轍辙遼辽邏逻醃腌" +
+	"釐厘鈞钧鉅巨銜衔鋁铝錫锡鏽锈鐮镰鑄铸閥阀閨闺闈闱陘陉靄霭鞏巩韜韬頑顽頡颉顛颠飆飙飪饪餃饺餒馁饗飨" +
+	"馮冯馴驯駁驳駿骏騁骋驪骊髏髅鬢鬓魘魇鯤鲲鱗鳞鳩鸠鴛鸳鴦鸯鵲鹊鶩鹜鷗鸥鷺鹭麩麸黿鼋齟龃係系繫系錶表" +
+	"捲卷嚐尝嘗尝傢家併并並并佈布佔占倆俩倉仓偽伪僑侨儂侬兌兑冪幂凜凛剎刹剝剥劊刽勻匀卹恤唄呗喲哟嘍喽" +
+	"噁恶嚕噜囪囱堯尧塢坞壘垒奩奁娛娱婁娄嫵妩宮宫衹只殭僵鬪斗鬭斗戯戏灋法絃弦綫线蹟迹迴回週周秈籼棲栖" +
+	"迺乃"
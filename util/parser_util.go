@@ -550,6 +550,11 @@ func ParseSearchResults(html string, channel string) ([]model.SearchResult, stri
 		}
 	})
 
+	// 提取更早消息的分页参数：页面顶部的“加载更多”链接带有data-before属性
+	if before, exists := doc.Find(".tme_messages_more[data-before]").First().Attr("data-before"); exists && before != "" {
+		nextPageParam = "before=" + url.QueryEscape(before)
+	}
+
 	return results, nextPageParam, nil
 }
 
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("读取测试数据失败: %v", err)
+	}
+	return string(data)
+}
+
+func TestParseSearchResultsPagination(t *testing.T) {
+	html := readFixture(t, "tg_search_page.html")
+
+	results, nextPageParam, err := ParseSearchResults(html, "tgsearchers6")
+	if err != nil {
+		t.Fatalf("ParseSearchResults() error = %v", err)
+	}
+
+	if nextPageParam != "before=4807" {
+		t.Errorf("nextPageParam = %q, want before=4807", nextPageParam)
+	}
+	if got := BuildSearchURL("tgsearchers6", "盗梦空间", nextPageParam); got != "https://t.me/s/tgsearchers6?q=%E7%9B%97%E6%A2%A6%E7%A9%BA%E9%97%B4&before=4807" {
+		t.Errorf("BuildSearchURL() = %q", got)
+	}
+
+	// 没有网盘链接的消息被忽略
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+	if results[0].MessageID != "4807" || results[0].UniqueID != "tgsearchers6_4807" {
+		t.Errorf("results[0] id = %q/%q", results[0].MessageID, results[0].UniqueID)
+	}
+	if len(results[0].Links) != 1 || results[0].Links[0].Type != "quark" {
+		t.Errorf("results[0].Links = %+v, want one quark link", results[0].Links)
+	}
+	if results[1].MessageID != "4812" {
+		t.Errorf("results[1].MessageID = %q, want 4812", results[1].MessageID)
+	}
+	if len(results[1].Links) != 1 || results[1].Links[0].Type != "baidu" || results[1].Links[0].Password != "x1y2" {
+		t.Errorf("results[1].Links = %+v, want one baidu link with password", results[1].Links)
+	}
+}
+
+func TestParseSearchResultsNextPageParam(t *testing.T) {
+	page := readFixture(t, "tg_search_page.html")
+	more := `<a href="/s/tgsearchers6?q=%E7%9B%97%E6%A2%A6%E7%A9%BA%E9%97%B4&amp;before=4807" class="tme_messages_more js-messages_more" data-before="4807"></a>`
+	if !strings.Contains(page, more) {
+		t.Fatal("测试数据中缺少加载更多链接")
+	}
+
+	tests := []struct {
+		name string
+		more string
+		want string
+	}{
+		{"last page", "", ""},
+		{"empty before", `<a class="tme_messages_more js-messages_more" data-before=""></a>`, ""},
+		{"newer messages only", `<a class="tme_messages_more js-messages_more" data-after="4815"></a>`, ""},
+		{"escaped before", `<a class="tme_messages_more js-messages_more" data-before="48&amp;07"></a>`, "before=48%2607"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := strings.Replace(page, more, tt.more, 1)
+			_, nextPageParam, err := ParseSearchResults(html, "tgsearchers6")
+			if err != nil {
+				t.Fatalf("ParseSearchResults() error = %v", err)
+			}
+			if nextPageParam != tt.want {
+				t.Errorf("nextPageParam = %q, want %q", nextPageParam, tt.want)
+			}
+		})
+	}
+}
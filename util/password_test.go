@@ -0,0 +1,57 @@
+package util
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2idHash 生成PHC格式的argon2id哈希，参数可以超出VerifyPassword允许的范围
+func argon2idHash(password string, memory, iterations uint32, parallelism uint8) string {
+	salt := []byte("0123456789abcdef")
+	hash := argon2.IDKey([]byte(password), salt, max(iterations, 1), min(memory, 64), max(parallelism, 1), 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, memory, iterations, parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(hash))
+}
+
+func TestVerifyPassword(t *testing.T) {
+	bcryptHash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	argonHash := argon2idHash("secret", 64, 1, 1)
+
+	tests := []struct {
+		name     string
+		stored   string
+		password string
+		want     bool
+	}{
+		{"bcrypt", bcryptHash, "secret", true},
+		{"bcrypt wrong password", bcryptHash, "wrong", false},
+		{"bcrypt malformed", "$2a$10$invalid", "secret", false},
+		{"argon2id", argonHash, "secret", true},
+		{"argon2id wrong password", argonHash, "wrong", false},
+		{"argon2id wrong version", "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$aGFzaA", "secret", false},
+		{"argon2id missing part", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA", "secret", false},
+		{"argon2id bad params", "$argon2id$v=19$m=x,t=1,p=1$c2FsdA$aGFzaA", "secret", false},
+		{"argon2id bad salt", "$argon2id$v=19$m=64,t=1,p=1$!!!$aGFzaA", "secret", false},
+		{"argon2id empty hash", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$", "secret", false},
+		{"argon2id zero iterations", argon2idHash("secret", 64, 0, 1), "secret", false},
+		{"argon2id zero parallelism", argon2idHash("secret", 64, 1, 0), "secret", false},
+		{"argon2id too many iterations", argon2idHash("secret", 64, argon2MaxIterations+1, 1), "secret", false},
+		{"argon2id too much memory", argon2idHash("secret", argon2MaxMemory+1, 1, 1), "secret", false},
+		{"plaintext", "secret", "secret", true},
+		{"plaintext wrong password", "secret", "secret2", false},
+		{"plaintext empty", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPassword(tt.stored, tt.password); got != tt.want {
+				t.Errorf("VerifyPassword(%q, %q) = %v, want %v", tt.stored, tt.password, got, tt.want)
+			}
+		})
+	}
+}
@@ -145,6 +145,12 @@ func ExecuteBatch(tasks []Task, maxWorkers int) []interface{} {
 
 // ExecuteBatchWithTimeout 批量执行任务，带有超时控制，并返回结果
 func ExecuteBatchWithTimeout(tasks []Task, maxWorkers int, timeout time.Duration) []interface{} {
+	return ExecuteBatchWithContext(context.Background(), tasks, maxWorkers, timeout)
+}
+
+// ExecuteBatchWithContext 批量执行任务，带有超时和上下文取消控制，并返回结果
+// parent取消时停止提交和等待任务，返回已收集的结果
+func ExecuteBatchWithContext(parent context.Context, tasks []Task, maxWorkers int, timeout time.Duration) []interface{} {
 	if len(tasks) == 0 {
 		return []interface{}{}
 	}
@@ -155,7 +161,7 @@ func ExecuteBatchWithTimeout(tasks []Task, maxWorkers int, timeout time.Duration
 	}
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(parent, timeout)
 	defer cancel()
 	
 	// 创建工作池
@@ -175,4 +181,4 @@ func ExecuteBatchWithTimeout(tasks []Task, maxWorkers int, timeout time.Duration
 	
 	// 获取所有结果，GetResults方法会处理超时情况
 	return pool.GetResults(len(tasks))
-} 
\ No newline at end of file
+}
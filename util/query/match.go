@@ -0,0 +1,102 @@
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"pansou/util/normalize"
+)
+
+// 标题中的独立4位年份
+var yearPattern = regexp.MustCompile(`(?:^|[^0-9])((?:19|20)[0-9]{2})(?:[^0-9]|$)`)
+
+// Document 参与匹配的一条结果或链接
+type Document struct {
+	Text      string    // 标题等用于关键词匹配的文本
+	Types     []string  // 网盘类型
+	Source    string    // 数据来源：tg:频道名 或 plugin:插件名
+	Datetime  time.Time // 发布时间
+	SkipTerms bool      // 来源插件自行完成了关键词过滤，必须满足的关键词视为已满足（排除条件仍然生效）
+
+	normalized string // 归一化后的Text，用于关键词匹配
+}
+
+// Match 检查文档是否满足查询，空查询匹配所有文档
+func (q *Query) Match(doc Document) bool {
+	if q.Root == nil {
+		return true
+	}
+	doc.Text = strings.ToLower(doc.Text)
+	doc.normalized = normalize.Text(doc.Text)
+	return match(q.Root, doc, false)
+}
+
+// match negated表示节点位于排除条件内
+func match(n Node, doc Document, negated bool) bool {
+	switch node := n.(type) {
+	case Term:
+		if doc.SkipTerms && !negated {
+			return true
+		}
+		return strings.Contains(doc.normalized, normalize.Text(node.Text))
+	case Field:
+		return matchField(node, doc)
+	case Not:
+		return !match(node.Node, doc, !negated)
+	case And:
+		for _, child := range node.Nodes {
+			if !match(child, doc, negated) {
+				return false
+			}
+		}
+		return true
+	case Or:
+		for _, child := range node.Nodes {
+			if match(child, doc, negated) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchField 检查字段过滤条件
+func matchField(field Field, doc Document) bool {
+	switch field.Name {
+	case FieldType:
+		for _, linkType := range doc.Types {
+			if strings.EqualFold(linkType, field.Value) {
+				return true
+			}
+		}
+		return false
+	case FieldSource:
+		return matchSource(strings.ToLower(doc.Source), field.Value)
+	case FieldAfter:
+		return !doc.Datetime.IsZero() && !doc.Datetime.Before(field.Date)
+	case FieldBefore:
+		return !doc.Datetime.IsZero() && doc.Datetime.Before(field.Date)
+	case FieldYear:
+		for _, match := range yearPattern.FindAllStringSubmatch(doc.Text, -1) {
+			if year, err := strconv.Atoi(match[1]); err == nil && year == field.Year {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchSource 来源可以写作tg、plugin、完整来源（tg:频道名、plugin:插件名）或单独的插件名、频道名
+func matchSource(source string, value string) bool {
+	if source == value || strings.HasPrefix(source, value+":") {
+		return true
+	}
+	if idx := strings.Index(source, ":"); idx >= 0 {
+		return source[idx+1:] == value
+	}
+	return false
+}
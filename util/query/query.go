@@ -0,0 +1,394 @@
+package query
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// 搜索查询语法：kw中的短语、排除、OR分组和字段过滤
+//
+//   "exact phrase"      短语，整体匹配
+//   -word / -"phrase"   排除包含该词的结果（NOT word同理）
+//   a OR b              满足任一即可，OR的优先级高于空格分隔的与
+//   type:quark          网盘类型
+//   source:tg           数据来源：tg、plugin、插件名、频道名或tg:频道名
+//   after:2024-01-01    发布时间不早于该日期（before:同理）
+//   year:2023           标题中出现该年份
+//
+// 不含上述语法的关键词保持原有的整体匹配行为
+// ============================================================
+
+// 同一查询最多拆分出的上游搜索关键词数
+const maxUpstreamKeywords = 4
+
+// ErrNoSearchTerm 查询只有排除条件或字段过滤，没有可发送给上游的关键词
+var ErrNoSearchTerm = errors.New("kw至少需要包含一个搜索关键词，不能只有排除条件或字段过滤")
+
+// 支持的字段过滤
+const (
+	FieldType   = "type"
+	FieldSource = "source"
+	FieldAfter  = "after"
+	FieldBefore = "before"
+	FieldYear   = "year"
+)
+
+// Node 查询语法树节点
+type Node interface {
+	node()
+}
+
+// Term 关键词或短语
+type Term struct {
+	Text   string // 原始文本，匹配时不区分大小写
+	Phrase bool   // 是否为引号包围的短语
+}
+
+// Field 字段过滤
+type Field struct {
+	Name  string
+	Value string    // 小写后的值
+	Date  time.Time // after、before的日期
+	Year  int       // year的年份
+}
+
+// Not 排除
+type Not struct {
+	Node Node
+}
+
+// And 全部满足
+type And struct {
+	Nodes []Node
+}
+
+// Or 满足任一
+type Or struct {
+	Nodes []Node
+}
+
+func (Term) node()  {}
+func (Field) node() {}
+func (Not) node()   {}
+func (And) node()   {}
+func (Or) node()    {}
+
+// Query 解析后的查询
+type Query struct {
+	Raw        string
+	Root       Node // 为nil表示空查询
+	structured bool // 是否使用了查询语法
+}
+
+// Parse 解析kw中的查询语法，不会失败：无法识别的写法按普通关键词处理
+func Parse(raw string) *Query {
+	p := &parser{tokens: tokenize(raw)}
+	q := &Query{Raw: raw}
+	q.Root = p.parseAnd()
+	q.structured = p.structured
+	return q
+}
+
+// Structured 查询是否使用了短语、排除、OR或字段过滤语法
+// 未使用时调用方应保持原有的关键词处理方式
+func (q *Query) Structured() bool {
+	return q.structured
+}
+
+// UpstreamKeywords 发送给TG频道和插件的纯关键词
+// 必须满足的关键词和短语以空格连接；顶层没有这类关键词时，第一个OR分组的每个分支各搜索一次（最多4个）。
+// 排除条件和字段过滤只在服务端过滤时使用，查询只有这些条件时返回nil。
+func (q *Query) UpstreamKeywords() []string {
+	if !q.structured {
+		return []string{strings.TrimSpace(q.Raw)}
+	}
+
+	required, alternatives := upstreamTerms(q.Root)
+	if len(required) > 0 {
+		return []string{strings.Join(required, " ")}
+	}
+
+	keywords := make([]string, 0, len(alternatives))
+	seen := make(map[string]bool)
+	for _, alternative := range alternatives {
+		if alternative == "" || seen[alternative] {
+			continue
+		}
+		seen[alternative] = true
+		keywords = append(keywords, alternative)
+		if len(keywords) == maxUpstreamKeywords {
+			break
+		}
+	}
+	if len(keywords) == 0 {
+		return nil
+	}
+	return keywords
+}
+
+// Validate 检查查询是否有可发送给上游的关键词，否则返回ErrNoSearchTerm
+// 只有排除条件或字段过滤的查询会向所有数据源发送空搜索
+func (q *Query) Validate() error {
+	if len(q.UpstreamKeywords()) == 0 {
+		return ErrNoSearchTerm
+	}
+	return nil
+}
+
+// RequiredSource 查询在顶层要求的数据来源类型（tg或plugin），没有要求时返回空
+// 用于跳过不可能满足条件的数据源
+func (q *Query) RequiredSource() string {
+	for _, n := range topLevel(q.Root) {
+		if field, ok := n.(Field); ok && field.Name == FieldSource {
+			if field.Value == "tg" || strings.HasPrefix(field.Value, "tg:") {
+				return "tg"
+			}
+			if field.Value == "plugin" {
+				return "plugin"
+			}
+		}
+	}
+	return ""
+}
+
+// topLevel 顶层与条件中的各节点
+func topLevel(root Node) []Node {
+	switch n := root.(type) {
+	case nil:
+		return nil
+	case And:
+		return n.Nodes
+	default:
+		return []Node{n}
+	}
+}
+
+// upstreamTerms 收集顶层必须满足的关键词，以及顶层第一个OR分组的各分支关键词
+// 结果必须满足每个OR分组，只搜索第一个分组的分支即可覆盖全部结果，其余分组只作为过滤条件
+func upstreamTerms(root Node) ([]string, []string) {
+	var required []string
+	var alternatives []string
+	orGroups := 0
+
+	for _, n := range topLevel(root) {
+		switch node := n.(type) {
+		case Term:
+			required = append(required, node.Text)
+		case Or:
+			orGroups++
+			if orGroups > 1 {
+				continue
+			}
+			for _, branch := range node.Nodes {
+				branchRequired, _ := upstreamTerms(branch)
+				alternatives = append(alternatives, strings.Join(branchRequired, " "))
+			}
+		}
+	}
+	return required, alternatives
+}
+
+// ============================================================
+// 词法和语法分析
+// ============================================================
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind    tokenKind
+	text    string
+	negated bool
+}
+
+// tokenize 按空白切分，引号内的内容作为一个短语
+func tokenize(raw string) []token {
+	var tokens []token
+	runes := []rune(raw)
+	for i := 0; i < len(runes); {
+		if isSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negated := false
+		if runes[i] == '-' && i+1 < len(runes) && !isSpace(runes[i+1]) {
+			negated = true
+			i++
+		}
+
+		if isQuote(runes[i]) {
+			closing := matchingQuote(runes[i])
+			end := i + 1
+			for end < len(runes) && runes[end] != closing {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenPhrase, text: string(runes[i+1 : end]), negated: negated})
+			i = end + 1
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !isSpace(runes[end]) {
+			end++
+		}
+		text := string(runes[i:end])
+		if !negated && text == "OR" {
+			tokens = append(tokens, token{kind: tokenOr, text: text})
+		} else if !negated && text == "NOT" {
+			tokens = append(tokens, token{kind: tokenNot, text: text})
+		} else {
+			tokens = append(tokens, token{kind: tokenWord, text: text, negated: negated})
+		}
+		i = end
+	}
+	return tokens
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '　'
+}
+
+func isQuote(r rune) bool {
+	return r == '"' || r == '“'
+}
+
+func matchingQuote(r rune) rune {
+	if r == '“' {
+		return '”'
+	}
+	return '"'
+}
+
+type parser struct {
+	tokens     []token
+	pos        int
+	structured bool
+}
+
+// parseAnd 解析空格分隔的与条件
+func (p *parser) parseAnd() Node {
+	var nodes []Node
+	for p.pos < len(p.tokens) {
+		if n := p.parseOr(); n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil
+	case 1:
+		return nodes[0]
+	default:
+		return And{Nodes: nodes}
+	}
+}
+
+// parseOr 解析以OR连接的分组
+func (p *parser) parseOr() Node {
+	first := p.parseUnary()
+	nodes := []Node{first}
+	for p.pos+1 < len(p.tokens) && p.tokens[p.pos].kind == tokenOr {
+		p.pos++
+		nodes = append(nodes, p.parseUnary())
+	}
+
+	if len(nodes) == 1 {
+		return first
+	}
+	p.structured = true
+
+	var branches []Node
+	for _, n := range nodes {
+		if n != nil {
+			branches = append(branches, n)
+		}
+	}
+	if len(branches) == 1 {
+		return branches[0]
+	}
+	return Or{Nodes: branches}
+}
+
+// parseUnary 解析单个词、短语或字段过滤，以及前缀排除
+func (p *parser) parseUnary() Node {
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	var n Node
+	switch tok.kind {
+	case tokenOr:
+		// 开头或结尾的OR按普通关键词处理
+		n = Term{Text: tok.text}
+	case tokenNot:
+		// NOT排除其后的词，结尾的NOT按普通关键词处理
+		if p.pos < len(p.tokens) && p.tokens[p.pos].kind != tokenOr {
+			p.structured = true
+			if next := p.parseUnary(); next != nil {
+				return Not{Node: next}
+			}
+			return nil
+		}
+		n = Term{Text: tok.text}
+	case tokenPhrase:
+		p.structured = true
+		text := strings.TrimSpace(tok.text)
+		if text == "" {
+			return nil
+		}
+		n = Term{Text: text, Phrase: true}
+	default:
+		if field, ok := parseField(tok.text); ok {
+			p.structured = true
+			n = field
+		} else {
+			n = Term{Text: tok.text}
+		}
+	}
+
+	if tok.negated {
+		p.structured = true
+		return Not{Node: n}
+	}
+	return n
+}
+
+// parseField 解析name:value形式的字段过滤，值无效时按普通关键词处理
+func parseField(text string) (Field, bool) {
+	idx := strings.Index(text, ":")
+	if idx <= 0 || idx == len(text)-1 {
+		return Field{}, false
+	}
+
+	name := strings.ToLower(text[:idx])
+	value := strings.ToLower(text[idx+1:])
+	field := Field{Name: name, Value: value}
+
+	switch name {
+	case FieldType, FieldSource:
+		return field, true
+	case FieldAfter, FieldBefore:
+		for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+			if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				field.Date = date
+				return field, true
+			}
+		}
+	case FieldYear:
+		if year, err := strconv.Atoi(value); err == nil && year >= 1900 && year <= 2100 {
+			field.Year = year
+			return field, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,247 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// render 将语法树输出为便于比较的文本
+func render(n Node) string {
+	switch node := n.(type) {
+	case nil:
+		return "<nil>"
+	case Term:
+		if node.Phrase {
+			return strconv.Quote(node.Text)
+		}
+		return node.Text
+	case Field:
+		return node.Name + ":" + node.Value
+	case Not:
+		return "(not " + render(node.Node) + ")"
+	case And:
+		return "(and " + renderAll(node.Nodes) + ")"
+	case Or:
+		return "(or " + renderAll(node.Nodes) + ")"
+	}
+	return "?"
+}
+
+func renderAll(nodes []Node) string {
+	parts := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		parts = append(parts, render(n))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []token
+	}{
+		{"", nil},
+		{"  速度与激情\t10 ", []token{{kind: tokenWord, text: "速度与激情"}, {kind: tokenWord, text: "10"}}},
+		{`"exact phrase" -"枪版"`, []token{{kind: tokenPhrase, text: "exact phrase"}, {kind: tokenPhrase, text: "枪版", negated: true}}},
+		{"“中文 引号”", []token{{kind: tokenPhrase, text: "中文 引号"}}},
+		{`"未闭合 短语`, []token{{kind: tokenPhrase, text: "未闭合 短语"}}},
+		{"a OR b", []token{{kind: tokenWord, text: "a"}, {kind: tokenOr, text: "OR"}, {kind: tokenWord, text: "b"}}},
+		{"a or b", []token{{kind: tokenWord, text: "a"}, {kind: tokenWord, text: "or"}, {kind: tokenWord, text: "b"}}},
+		{"NOT a -OR", []token{{kind: tokenNot, text: "NOT"}, {kind: tokenWord, text: "a"}, {kind: tokenWord, text: "OR", negated: true}}},
+		{"- a", []token{{kind: tokenWord, text: "-"}, {kind: tokenWord, text: "a"}}},
+		{"蜘蛛侠　纵横宇宙", []token{{kind: tokenWord, text: "蜘蛛侠"}, {kind: tokenWord, text: "纵横宇宙"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := tokenize(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw        string
+		want       string
+		structured bool
+	}{
+		{"", "<nil>", false},
+		{"速度与激情", "速度与激情", false},
+		{"速度与激情 10", "(and 速度与激情 10)", false},
+		// OR的优先级高于空格分隔的与
+		{"a b OR c", "(and a (or b c))", true},
+		{"a OR b c", "(and (or a b) c)", true},
+		{"a OR b OR c", "(or a b c)", true},
+		{`"x y" -z`, `(and "x y" (not z))`, true},
+		{`-"枪版" 庆余年`, `(and (not "枪版") 庆余年)`, true},
+		{"NOT 预告 庆余年", "(and (not 预告) 庆余年)", true},
+		{"庆余年 NOT", "(and 庆余年 NOT)", false},
+		{"NOT OR a", "(or NOT a)", true},
+		{"type:quark 庆余年", "(and type:quark 庆余年)", true},
+		{"TYPE:Quark", "type:quark", true},
+		{"source:tg -source:tg:abc", "(and source:tg (not source:tg:abc))", true},
+		{"after:2024-01-01 before:2024", "(and after:2024-01-01 before:2024)", true},
+		{"year:2023", "year:2023", true},
+		// 无效的字段按普通关键词处理
+		{"after:abc", "after:abc", false},
+		{"year:1800", "year:1800", false},
+		{"unknown:x", "unknown:x", false},
+		{"http://example.com", "http://example.com", false},
+		// 开头或结尾的OR按普通关键词处理
+		{"OR a", "(and OR a)", false},
+		{"a OR", "(and a OR)", false},
+		{`""`, "<nil>", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			q := Parse(tt.raw)
+			if got := render(q.Root); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.raw, got, tt.want)
+			}
+			if q.Structured() != tt.structured {
+				t.Errorf("Parse(%q).Structured() = %v, want %v", tt.raw, q.Structured(), tt.structured)
+			}
+		})
+	}
+}
+
+func TestParseFieldValues(t *testing.T) {
+	q := Parse("after:2024-03 year:2023")
+	and, ok := q.Root.(And)
+	if !ok || len(and.Nodes) != 2 {
+		t.Fatalf("Parse() = %s", render(q.Root))
+	}
+	after := and.Nodes[0].(Field)
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local); !after.Date.Equal(want) {
+		t.Errorf("after.Date = %v, want %v", after.Date, want)
+	}
+	if year := and.Nodes[1].(Field).Year; year != 2023 {
+		t.Errorf("year.Year = %d, want 2023", year)
+	}
+}
+
+func TestUpstreamKeywords(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"  速度与激情 10 ", []string{"速度与激情 10"}},
+		{`庆余年 "第二季" -预告 type:quark after:2024`, []string{"庆余年 第二季"}},
+		{"庆余年 OR 雪中悍刀行", []string{"庆余年", "雪中悍刀行"}},
+		{`"庆余年 2" OR 雪中 -预告`, []string{"庆余年 2", "雪中"}},
+		{"a OR b OR a", []string{"a", "b"}},
+		// 最多4个分支
+		{"a OR b OR c OR d OR e OR f", []string{"a", "b", "c", "d"}},
+		// 有必须满足的关键词时OR只作为过滤条件
+		{"x a OR b", []string{"x"}},
+		{"a OR b type:quark", []string{"a", "b"}},
+		// 多个OR分组时搜索第一个分组的分支，其余分组只作为过滤条件
+		{"a OR b c OR d", []string{"a", "b"}},
+		// 只有排除条件或字段过滤时没有上游关键词
+		{"-预告", nil},
+		{"type:quark", nil},
+		{`NOT 预告 -"枪版" after:2024`, nil},
+		{"-a OR -b", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := Parse(tt.raw).UpstreamKeywords(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpstreamKeywords(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, raw := range []string{"庆余年", "庆余年 -预告", "a OR -b", `"速度与激情" type:quark`} {
+		if err := Parse(raw).Validate(); err != nil {
+			t.Errorf("Validate(%q) = %v, want nil", raw, err)
+		}
+	}
+	for _, raw := range []string{"-预告", "type:quark source:tg", "NOT a"} {
+		if err := Parse(raw).Validate(); !errors.Is(err, ErrNoSearchTerm) {
+			t.Errorf("Validate(%q) = %v, want ErrNoSearchTerm", raw, err)
+		}
+	}
+}
+
+func TestRequiredSource(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"庆余年", ""},
+		{"庆余年 source:tg", "tg"},
+		{"庆余年 source:tg:abc", "tg"},
+		{"庆余年 source:plugin", "plugin"},
+		{"庆余年 source:labi", ""},
+		{"庆余年 -source:tg", ""},
+		{"source:tg OR source:plugin", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := Parse(tt.raw).RequiredSource(); got != tt.want {
+				t.Errorf("RequiredSource(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	published := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	doc := Document{
+		Text:     "庆余年 第二季 2024 4K 全集",
+		Types:    []string{"quark", "baidu"},
+		Source:   "plugin:labi",
+		Datetime: published,
+	}
+
+	tests := []struct {
+		raw  string
+		doc  Document
+		want bool
+	}{
+		{"", doc, true},
+		{"庆余年", doc, true},
+		{"慶餘年", doc, true},
+		{"庆余年 第2季", doc, true},
+		{"庆余年 雪中", doc, false},
+		{`"第二季 2024"`, doc, true},
+		{`"2024 第二季"`, doc, false},
+		{"庆余年 -预告", doc, true},
+		{"庆余年 -4k", doc, false},
+		{"庆余年 NOT 全集", doc, false},
+		{"雪中 OR 庆余年", doc, true},
+		{"雪中 OR 赘婿", doc, false},
+		{"type:quark", doc, true},
+		{"type:aliyun", doc, false},
+		{"-type:baidu", doc, false},
+		{"source:plugin", doc, true},
+		{"source:labi", doc, true},
+		{"source:plugin:labi", doc, true},
+		{"source:tg", doc, false},
+		{"after:2024-06-01", doc, true},
+		{"after:2024-06-02", doc, false},
+		{"before:2024-06-02", doc, true},
+		{"before:2024", doc, false},
+		{"after:2024", Document{Text: "庆余年"}, false},
+		{"year:2024", doc, true},
+		{"year:2023", doc, false},
+		{"year:2024", Document{Text: "庆余年 120241"}, false},
+		// 插件自行过滤关键词时只检查排除条件和字段
+		{"完全不同 type:quark", Document{Text: "庆余年", Types: []string{"quark"}, SkipTerms: true}, true},
+		{"完全不同 -庆余年", Document{Text: "庆余年", SkipTerms: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := Parse(tt.raw).Match(tt.doc); got != tt.want {
+				t.Errorf("Parse(%q).Match(%q) = %v, want %v", tt.raw, tt.doc.Text, got, tt.want)
+			}
+		})
+	}
+}
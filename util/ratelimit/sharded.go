@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// limiterShardCount 分片数，按键的哈希分散锁竞争
+const limiterShardCount = 32
+
+// ShardedLimiter 按键限流的令牌桶集合，按键的哈希分片加锁
+// 适合客户端数量多、请求频繁的场景；空闲到令牌桶回满的键由Prune清理，清理后再次访问等同于满桶
+type ShardedLimiter struct {
+	rate   float64
+	burst  int
+	shards [limiterShardCount]limiterShard
+}
+
+type limiterShard struct {
+	mu      sync.Mutex
+	buckets map[string]*TokenBucket
+}
+
+// NewShardedLimiter 创建分片限流器，每个键使用相同的速率和桶容量
+func NewShardedLimiter(ratePerSecond float64, burst int) *ShardedLimiter {
+	l := &ShardedLimiter{rate: ratePerSecond, burst: burst}
+	for i := range l.shards {
+		l.shards[i].buckets = make(map[string]*TokenBucket)
+	}
+	return l
+}
+
+// shard 返回键所在的分片（FNV-1a哈希）
+func (l *ShardedLimiter) shard(key string) *limiterShard {
+	hash := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+	return &l.shards[hash%limiterShardCount]
+}
+
+// Take 尝试立即获取指定键的令牌，失败时同时返回需要等待的时间
+func (l *ShardedLimiter) Take(key string) (bool, time.Duration) {
+	s := l.shard(key)
+	s.mu.Lock()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = NewTokenBucket(l.rate, l.burst)
+		s.buckets[key] = bucket
+	}
+	s.mu.Unlock()
+	return bucket.Take()
+}
+
+// Prune 删除已经回满的令牌桶，返回删除的数量
+func (l *ShardedLimiter) Prune() int {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	// 空闲这么久的令牌桶一定已经回满，删除后重新创建的满桶与之等价
+	idle := time.Duration(float64(l.burst) / l.rate * float64(time.Second))
+	now := time.Now()
+	pruned := 0
+	for i := range l.shards {
+		s := &l.shards[i]
+		s.mu.Lock()
+		for key, bucket := range s.buckets {
+			bucket.mu.Lock()
+			full := now.Sub(bucket.lastFill) >= idle
+			bucket.mu.Unlock()
+			if full {
+				delete(s.buckets, key)
+				pruned++
+			}
+		}
+		s.mu.Unlock()
+	}
+	return pruned
+}
+
+// Len 返回当前的令牌桶数量
+func (l *ShardedLimiter) Len() int {
+	n := 0
+	for i := range l.shards {
+		s := &l.shards[i]
+		s.mu.Lock()
+		n += len(s.buckets)
+		s.mu.Unlock()
+	}
+	return n
+}
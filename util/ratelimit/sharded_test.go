@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShardedLimiterTake(t *testing.T) {
+	l := NewShardedLimiter(1, 2)
+
+	for i := 0; i < 2; i++ {
+		if ok, wait := l.Take("a"); !ok || wait != 0 {
+			t.Fatalf("Take(a) #%d = %v, %v, want true, 0", i+1, ok, wait)
+		}
+	}
+	ok, wait := l.Take("a")
+	if ok {
+		t.Fatal("Take(a) 超出突发数后应失败")
+	}
+	if wait <= 0 || wait > time.Second {
+		t.Errorf("Take(a) wait = %v, want (0, 1s]", wait)
+	}
+
+	// 不同键使用各自的令牌桶
+	if ok, _ := l.Take("b"); !ok {
+		t.Error("Take(b) 不应受a的限额影响")
+	}
+	if got := l.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+}
+
+func TestShardedLimiterUnlimited(t *testing.T) {
+	l := NewShardedLimiter(0, 1)
+	for i := 0; i < 10; i++ {
+		if ok, _ := l.Take("a"); !ok {
+			t.Fatal("速率为0时不应限流")
+		}
+	}
+	if got := l.Prune(); got != 0 {
+		t.Errorf("Prune() = %d, want 0", got)
+	}
+}
+
+func TestShardedLimiterPrune(t *testing.T) {
+	l := NewShardedLimiter(1000, 10) // 10ms回满
+	l.Take("idle")
+	l.Take("busy")
+
+	if got := l.Prune(); got != 0 {
+		t.Fatalf("Prune() = %d, want 0（令牌桶未回满）", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	// busy在回满前再次访问后仍未回满，idle已回满
+	for i := 0; i < 10; i++ {
+		l.Take("busy")
+	}
+	if got := l.Prune(); got != 1 {
+		t.Fatalf("Prune() = %d, want 1", got)
+	}
+	if got := l.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+
+	// 清理后再次访问等同于满桶
+	for i := 0; i < 10; i++ {
+		if ok, _ := l.Take("idle"); !ok {
+			t.Fatalf("Take(idle) #%d 清理后应为满桶", i+1)
+		}
+	}
+}
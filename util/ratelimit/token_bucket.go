@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket 令牌桶限流器
+// 以固定速率补充令牌，桶容量决定允许的突发请求数
+type TokenBucket struct {
+	mu       sync.Mutex
+	rate     float64   // 每秒补充的令牌数
+	burst    float64   // 桶容量
+	tokens   float64   // 当前令牌数
+	lastFill time.Time // 上次补充令牌的时间
+}
+
+// NewTokenBucket 创建令牌桶，初始为满桶
+func NewTokenBucket(ratePerSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:     ratePerSecond,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// refill 按经过的时间补充令牌，调用方需持有锁
+func (b *TokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastFill).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.lastFill = now
+	}
+}
+
+// Allow 尝试立即获取一个令牌，成功返回true
+func (b *TokenBucket) Allow() bool {
+	ok, _ := b.Take()
+	return ok
+}
+
+// Take 尝试立即获取一个令牌，失败时同时返回补充一个令牌需要等待的时间
+func (b *TokenBucket) Take() (bool, time.Duration) {
+	// 速率不大于0表示不限流
+	if b.rate <= 0 {
+		return true, 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Wait 阻塞直到获取一个令牌，ctx取消或截止时间不足以等到令牌时返回错误
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	// 速率不大于0表示不限流
+	if b.rate <= 0 {
+		return ctx.Err()
+	}
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.refill(now)
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		// 截止时间前等不到令牌，直接返回，避免无意义的等待
+		if deadline, ok := ctx.Deadline(); ok && now.Add(wait).After(deadline) {
+			return context.DeadlineExceeded
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Limiter 按键（如网盘类型）分别限流的令牌桶集合
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   int
+	buckets map[string]*TokenBucket
+}
+
+// NewLimiter 创建按键限流器，每个键使用相同的速率和桶容量
+func NewLimiter(ratePerSecond float64, burst int) *Limiter {
+	return &Limiter{
+		rate:    ratePerSecond,
+		burst:   burst,
+		buckets: make(map[string]*TokenBucket),
+	}
+}
+
+// Bucket 获取指定键的令牌桶，不存在时创建
+func (l *Limiter) Bucket(key string) *TokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = NewTokenBucket(l.rate, l.burst)
+		l.buckets[key] = bucket
+	}
+	return bucket
+}
+
+// Wait 等待指定键的令牌
+func (l *Limiter) Wait(ctx context.Context, key string) error {
+	return l.Bucket(key).Wait(ctx)
+}
+
+// Allow 尝试立即获取指定键的令牌
+func (l *Limiter) Allow(key string) bool {
+	return l.Bucket(key).Allow()
+}